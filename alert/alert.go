@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package alert
+
+import (
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+)
+
+const (
+	LevelInfo     = "info"
+	LevelWarn     = "warn"
+	LevelCritical = "critical"
+)
+
+type Alert struct {
+	Level   string
+	Source  string // the monitor raising the alert
+	Title   string
+	Content string
+	Time    int64
+	Key     string `json:"-"` // alerts of the same key are de-duplicated, source, title and content by default
+}
+
+func (a *Alert) key() string {
+	if a.Key != "" {
+		return a.Key
+	}
+	return a.Source + "|" + a.Title + "|" + a.Content
+}
+
+// levelRank orders the levels, an unknown level ranks as info.
+func levelRank(level string) int {
+	switch level {
+	case LevelCritical:
+		return 2
+	case LevelWarn:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type Sink interface {
+	Send(alert *Alert) error
+	Name() string
+}
+
+// Notifier fans an alert out to all sinks. A failing sink is logged and does
+// not keep the alert from the others. It is shared by the monitors, and drops
+// the repeated alerts and the alerts over the rate limit once a throttle is
+// set.
+type Notifier struct {
+	sinks    []Sink
+	lock     sync.Mutex
+	throttle *throttle
+}
+
+func NewNotifier(sinks ...Sink) *Notifier {
+	return &Notifier{sinks: sinks}
+}
+
+func (n *Notifier) SetThrottle(cfg *conf.AlertThrottleConfig) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if cfg == nil {
+		n.throttle = nil
+		return
+	}
+	n.throttle = newThrottle(cfg)
+}
+
+// Forget lets the next alert of the key through the de-duplication, used when
+// the condition of the key is resolved.
+func (n *Notifier) Forget(key string) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.throttle != nil {
+		delete(n.throttle.seen, key)
+	}
+}
+
+func NewNotifierFromConfig(cfgs []*conf.AlertSinkConfig) (*Notifier, error) {
+	sinks := make([]Sink, 0)
+	for _, cfg := range cfgs {
+		sink, err := NewSink(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Level != "" {
+			sink = &levelSink{Sink: sink, level: cfg.Level}
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		sinks = append(sinks, NewLogSink())
+	}
+	return NewNotifier(sinks...), nil
+}
+
+func (n *Notifier) Notify(alert *Alert) {
+	if alert.Time == 0 {
+		alert.Time = time.Now().Unix()
+	}
+	n.lock.Lock()
+	allowed := n.throttle == nil || n.throttle.allow(alert, time.Now().Unix())
+	n.lock.Unlock()
+	if !allowed {
+		return
+	}
+	for _, sink := range n.sinks {
+		if err := sink.Send(alert); err != nil {
+			logs.Error("alert %s to sink %s failed, err: %v", alert.Title, sink.Name(), err)
+		}
+	}
+}
+
+// levelSink skips the alerts below its level.
+type levelSink struct {
+	Sink
+	level string
+}
+
+func (s *levelSink) Send(alert *Alert) error {
+	if levelRank(alert.Level) < levelRank(s.level) {
+		return nil
+	}
+	return s.Sink.Send(alert)
+}
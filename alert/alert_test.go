@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package alert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordSink struct {
+	alerts []*Alert
+}
+
+func (s *recordSink) Send(alert *Alert) error {
+	s.alerts = append(s.alerts, alert)
+	return nil
+}
+
+func (s *recordSink) Name() string {
+	return "record"
+}
+
+func TestThrottle(t *testing.T) {
+	th := newThrottle(&conf.AlertThrottleConfig{Dedup: 60, Limit: 2, Period: 10})
+	stalled := &Alert{Source: "rules", Title: "stalled", Content: "height 1", Key: "stalled:2"}
+	assert.True(t, th.allow(stalled, 100))
+	// the key is kept within the window even when the content changes
+	assert.False(t, th.allow(&Alert{Source: "rules", Title: "stalled", Content: "height 2", Key: "stalled:2"}, 130))
+	assert.True(t, th.allow(&Alert{Source: "rules", Title: "lag"}, 130))
+	assert.True(t, th.allow(&Alert{Source: "rules", Title: "balance"}, 131))
+	// over the limit of the period
+	assert.False(t, th.allow(&Alert{Source: "rules", Title: "pending"}, 132))
+	assert.True(t, th.allow(&Alert{Source: "rules", Title: "pending"}, 141))
+	assert.True(t, th.allow(stalled, 160))
+}
+
+func TestThrottleCritical(t *testing.T) {
+	th := newThrottle(&conf.AlertThrottleConfig{Dedup: 60, Limit: 2, Period: 10})
+	for i := 0; i < 10; i++ {
+		unlock := &Alert{Level: LevelCritical, Source: "unlock", Title: "unmatched unlock", Content: fmt.Sprintf("tx %d", i)}
+		assert.True(t, th.allow(unlock, 100))
+	}
+	// the critical alerts count, the others are over the limit
+	assert.False(t, th.allow(&Alert{Level: LevelWarn, Source: "rules", Title: "lag"}, 101))
+	// a duplicated critical alert is still dropped
+	assert.False(t, th.allow(&Alert{Level: LevelCritical, Source: "unlock", Title: "unmatched unlock", Content: "tx 0"}, 102))
+
+	sink := new(recordSink)
+	notifier := NewNotifier(sink)
+	notifier.SetThrottle(&conf.AlertThrottleConfig{Limit: 1})
+	for i := 0; i < 5; i++ {
+		notifier.Notify(&Alert{Level: LevelCritical, Source: "governance", Title: "owner changed", Content: fmt.Sprintf("tx %d", i)})
+	}
+	notifier.Notify(&Alert{Level: LevelWarn, Source: "rules", Title: "lag"})
+	assert.Equal(t, 5, len(sink.alerts))
+}
+
+func TestNotifierLevel(t *testing.T) {
+	all, critical := new(recordSink), new(recordSink)
+	notifier := NewNotifier(all, &levelSink{Sink: critical, level: LevelCritical})
+	notifier.Notify(&Alert{Level: LevelInfo, Title: "resolved"})
+	notifier.Notify(&Alert{Level: LevelCritical, Title: "stalled"})
+	notifier.Notify(&Alert{Level: LevelCritical, Title: "stalled"})
+	assert.Equal(t, 3, len(all.alerts))
+	assert.Equal(t, 2, len(critical.alerts))
+
+	notifier.SetThrottle(&conf.AlertThrottleConfig{Dedup: 60})
+	notifier.Notify(&Alert{Level: LevelCritical, Title: "stalled"})
+	notifier.Notify(&Alert{Level: LevelCritical, Title: "stalled"})
+	assert.Equal(t, 4, len(all.alerts))
+}
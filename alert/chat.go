@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package alert
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DingTalkSink sends the alert as a text message of a dingtalk robot. The
+// request is signed when the robot has a secret.
+type DingTalkSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewDingTalkSink(url string, secret string) *DingTalkSink {
+	return &DingTalkSink{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *DingTalkSink) Send(alert *Alert) error {
+	body := map[string]interface{}{
+		"msgtype": "text",
+		"text":    map[string]string{"content": formatText(alert)},
+	}
+	respBody, err := postJson(s.client, s.signedUrl(time.Now()), nil, body)
+	if err != nil {
+		return err
+	}
+	// dingtalk responds 200 with an error code
+	rsp := struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}{}
+	if err := json.Unmarshal(respBody, &rsp); err != nil {
+		return err
+	}
+	if rsp.ErrCode != 0 {
+		return fmt.Errorf("dingtalk responds %d: %s", rsp.ErrCode, rsp.ErrMsg)
+	}
+	return nil
+}
+
+func (s *DingTalkSink) signedUrl(now time.Time) string {
+	if s.secret == "" {
+		return s.url
+	}
+	timestamp := fmt.Sprintf("%d", now.UnixNano()/int64(time.Millisecond))
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write([]byte(timestamp + "\n" + s.secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	sep := "?"
+	if strings.Contains(s.url, "?") {
+		sep = "&"
+	}
+	return s.url + sep + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+}
+
+func (s *DingTalkSink) Name() string {
+	return SinkDingTalk
+}
+
+// SlackSink sends the alert to a slack incoming webhook.
+type SlackSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewSlackSink(url string) *SlackSink {
+	return &SlackSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *SlackSink) Send(alert *Alert) error {
+	_, err := postJson(s.client, s.url, nil, map[string]string{"text": formatText(alert)})
+	return err
+}
+
+func (s *SlackSink) Name() string {
+	return SinkSlack
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+)
+
+const (
+	SinkLog      = "log"
+	SinkWebhook  = "webhook"
+	SinkDingTalk = "dingtalk"
+	SinkSlack    = "slack"
+	SinkSmtp     = "smtp"
+)
+
+func NewSink(cfg *conf.AlertSinkConfig) (Sink, error) {
+	switch cfg.Type {
+	case "", SinkLog:
+		return NewLogSink(), nil
+	case SinkWebhook:
+		if cfg.Url == "" {
+			return nil, fmt.Errorf("webhook sink without url")
+		}
+		return NewWebhookSink(cfg.Url, cfg.Headers), nil
+	case SinkDingTalk:
+		if cfg.Url == "" {
+			return nil, fmt.Errorf("dingtalk sink without url")
+		}
+		return NewDingTalkSink(cfg.Url, cfg.Secret), nil
+	case SinkSlack:
+		if cfg.Url == "" {
+			return nil, fmt.Errorf("slack sink without url")
+		}
+		return NewSlackSink(cfg.Url), nil
+	case SinkSmtp:
+		if cfg.Addr == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, fmt.Errorf("smtp sink without addr, from or to")
+		}
+		return NewSmtpSink(cfg.Addr, cfg.User, cfg.Password, cfg.From, cfg.To), nil
+	default:
+		return nil, fmt.Errorf("unknown alert sink %s", cfg.Type)
+	}
+}
+
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Send(alert *Alert) error {
+	switch alert.Level {
+	case LevelCritical:
+		logs.Error("[alert][%s] %s: %s", alert.Source, alert.Title, alert.Content)
+	case LevelWarn:
+		logs.Warn("[alert][%s] %s: %s", alert.Source, alert.Title, alert.Content)
+	default:
+		logs.Info("[alert][%s] %s: %s", alert.Source, alert.Title, alert.Content)
+	}
+	return nil
+}
+
+func (s *LogSink) Name() string {
+	return SinkLog
+}
+
+// WebhookSink posts the alert as json.
+type WebhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func NewWebhookSink(url string, headers map[string]string) *WebhookSink {
+	return &WebhookSink{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *WebhookSink) Send(alert *Alert) error {
+	_, err := postJson(s.client, s.url, s.headers, alert)
+	return err
+}
+
+func (s *WebhookSink) Name() string {
+	return SinkWebhook
+}
+
+// postJson posts the body as json and returns the response body, a response
+// other than 2xx is an error.
+func postJson(client *http.Client, url string, headers map[string]string, body interface{}) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s responds %s", url, resp.Status)
+	}
+	return respBody, nil
+}
+
+// formatText renders the alert for the chat and mail sinks.
+func formatText(alert *Alert) string {
+	return fmt.Sprintf("[%s][%s] %s\n%s\n%s", alert.Level, alert.Source, alert.Title, alert.Content,
+		time.Unix(alert.Time, 0).Format("2006-01-02 15:04:05"))
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package alert
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSink(t *testing.T) {
+	received := make([]*Alert, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := new(Alert)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(a))
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received = append(received, a)
+	}))
+	defer server.Close()
+
+	notifier, err := NewNotifierFromConfig([]*conf.AlertSinkConfig{
+		{Type: SinkWebhook, Url: server.URL, Headers: map[string]string{"X-Token": "secret"}},
+		{Type: SinkLog},
+	})
+	assert.NoError(t, err)
+	notifier.Notify(&Alert{Level: LevelCritical, Source: "test", Title: "title", Content: "content"})
+	assert.Equal(t, 1, len(received))
+	assert.Equal(t, "title", received[0].Title)
+	assert.NotZero(t, received[0].Time)
+
+	sink, err := NewSink(&conf.AlertSinkConfig{Type: SinkWebhook, Url: server.URL})
+	assert.NoError(t, err)
+	assert.Error(t, sink.Send(&Alert{Level: LevelInfo}))
+	assert.Equal(t, 1, len(received))
+
+	_, err = NewSink(&conf.AlertSinkConfig{Type: SinkWebhook})
+	assert.Error(t, err)
+	_, err = NewSink(&conf.AlertSinkConfig{Type: "pager"})
+	assert.Error(t, err)
+}
+
+func TestChatSinks(t *testing.T) {
+	texts := make([]string, 0)
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make(map[string]interface{})
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch r.URL.Path {
+		case "/dingtalk":
+			query = r.URL.RawQuery
+			assert.Equal(t, "text", body["msgtype"])
+			texts = append(texts, body["text"].(map[string]interface{})["content"].(string))
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case "/dingtalk/bad":
+			w.Write([]byte(`{"errcode":310000,"errmsg":"sign not match"}`))
+		case "/slack":
+			texts = append(texts, body["text"].(string))
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	alert := &Alert{Level: LevelWarn, Source: "rules", Title: "chain stalled", Content: "height 100", Time: 1}
+	assert.NoError(t, NewDingTalkSink(server.URL+"/dingtalk", "").Send(alert))
+	assert.Empty(t, query)
+	assert.NoError(t, NewDingTalkSink(server.URL+"/dingtalk", "secret").Send(alert))
+	assert.Contains(t, query, "timestamp=")
+	assert.Contains(t, query, "sign=")
+	assert.Error(t, NewDingTalkSink(server.URL+"/dingtalk/bad", "").Send(alert))
+	assert.NoError(t, NewSlackSink(server.URL+"/slack").Send(alert))
+	assert.Equal(t, 3, len(texts))
+	for _, text := range texts {
+		assert.True(t, strings.HasPrefix(text, "[warn][rules] chain stalled\nheight 100\n"), text)
+	}
+}
+
+// serveSmtp accepts one mail session on the listener and returns the data.
+func serveSmtp(t *testing.T, l net.Listener) <-chan string {
+	data := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var body strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					body.WriteString(line)
+				}
+				data <- body.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return data
+}
+
+func TestSmtpSink(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	data := serveSmtp(t, l)
+
+	sink, err := NewSink(&conf.AlertSinkConfig{Type: SinkSmtp, Addr: l.Addr().String(), From: "bridge@example.com",
+		To: []string{"ops@example.com", "dev@example.com"}})
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Send(&Alert{Level: LevelCritical, Source: "rules", Title: "chain stalled", Content: "height 100", Time: 1}))
+	mail := <-data
+	assert.Contains(t, mail, "To: ops@example.com, dev@example.com\r\n")
+	assert.Contains(t, mail, "Subject: [critical][rules] chain stalled\r\n")
+	assert.Contains(t, mail, "height 100\r\n")
+
+	_, err = NewSink(&conf.AlertSinkConfig{Type: SinkSmtp, Addr: l.Addr().String()})
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package alert
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SmtpSink mails the alert. Plain auth is used when a user is set, which
+// net/smtp allows only over tls or to localhost.
+type SmtpSink struct {
+	addr     string
+	user     string
+	password string
+	from     string
+	to       []string
+}
+
+func NewSmtpSink(addr, user, password, from string, to []string) *SmtpSink {
+	return &SmtpSink{
+		addr:     addr,
+		user:     user,
+		password: password,
+		from:     from,
+		to:       to,
+	}
+}
+
+func (s *SmtpSink) Send(alert *Alert) error {
+	var auth smtp.Auth
+	if s.user != "" {
+		host, _, err := net.SplitHostPort(s.addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.user, s.password, host)
+	}
+	return smtp.SendMail(s.addr, auth, s.from, s.to, s.message(alert))
+}
+
+func (s *SmtpSink) message(alert *Alert) []byte {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&msg, "Subject: [%s][%s] %s\r\n", alert.Level, alert.Source, alert.Title)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Unix(alert.Time, 0).Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(formatText(alert), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return []byte(msg.String())
+}
+
+func (s *SmtpSink) Name() string {
+	return SinkSmtp
+}
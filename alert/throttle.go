@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package alert
+
+import (
+	"github.com/astaxie/beego/logs"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+)
+
+const defaultThrottlePeriod = 60
+
+// throttle drops an alert whose key was sent within the dedup window, and the
+// alerts over the limit of the current period. Only the sent alerts count.
+// Critical alerts are never over the limit, the monitors raise most of them
+// only once and a dropped one would be lost.
+type throttle struct {
+	dedup  int64
+	limit  int
+	period int64
+	seen   map[string]int64 // key to the time it was sent
+	sent   []int64          // send times within the period
+}
+
+func newThrottle(cfg *conf.AlertThrottleConfig) *throttle {
+	period := cfg.Period
+	if period == 0 {
+		period = defaultThrottlePeriod
+	}
+	return &throttle{
+		dedup:  cfg.Dedup,
+		limit:  cfg.Limit,
+		period: period,
+		seen:   make(map[string]int64),
+		sent:   make([]int64, 0),
+	}
+}
+
+func (t *throttle) allow(alert *Alert, now int64) bool {
+	for key, last := range t.seen {
+		if now-last >= t.dedup {
+			delete(t.seen, key)
+		}
+	}
+	start := 0
+	for start < len(t.sent) && now-t.sent[start] >= t.period {
+		start++
+	}
+	t.sent = t.sent[start:]
+
+	key := alert.key()
+	if _, ok := t.seen[key]; ok {
+		logs.Debug("alert %s is duplicated", alert.Title)
+		return false
+	}
+	if t.limit > 0 && len(t.sent) >= t.limit && alert.Level != LevelCritical {
+		logs.Warn("alert %s is dropped, over %d alerts in %d seconds", alert.Title, t.limit, t.period)
+		return false
+	}
+	if t.dedup > 0 {
+		t.seen[key] = now
+	}
+	t.sent = append(t.sent, now)
+	return true
+}
@@ -31,5 +31,7 @@ type AddAssetConfig struct {
 	AssetBasics []*models.NFTAssetBasic
 	//AssetMaps       []*models.NFTAssetMap
 	RemoveAssetMaps []*models.NFTAssetMap
-	RemoveAssets    []string
+	// RestoreAssetMaps puts the directions removed before back into the mesh
+	RestoreAssetMaps []*models.NFTAssetMap
+	RemoveAssets     []string
 }
@@ -58,10 +58,33 @@ var (
 		},
 	}
 
+	CmdPendingAssets = cli.Command{
+		Name:   "pendingAssets",
+		Usage:  "list the NFT assets and maps found on chain which wait for approval.",
+		Action: handlePendingAssets,
+		Flags: []cli.Flag{
+			ConfigPathFlag,
+		},
+	}
+
+	CmdApproveAsset = cli.Command{
+		Name:   "approveAsset",
+		Usage:  "enable the pending NFT asset found on chain.",
+		Action: handleApproveAsset,
+		Flags: []cli.Flag{
+			ConfigPathFlag,
+			AssetFlag,
+		},
+	}
+
 	CmdDelAsset = cli.Command{
 		Name:   "delAsset",
-		Usage:  "remove NFT asset.",
+		Usage:  "remove NFT asset, it can be added again by addAsset.",
 		Action: handleDelAsset,
+		Flags: []cli.Flag{
+			ConfigPathFlag,
+			AssetFlag,
+		},
 	}
 )
 
@@ -47,6 +47,8 @@ func setupApp() *cli.App {
 	app.Commands = []cli.Command{
 		CmdAddAsset,
 		CmdDelAsset,
+		CmdPendingAssets,
+		CmdApproveAsset,
 	}
 	app.Before = beforeCommands
 	return app
@@ -103,14 +105,74 @@ func handleAddAsset(ctx *cli.Context) error {
 	if err := dao.RemoveAssetMaps(cfg.RemoveAssetMaps); err != nil {
 		return err
 	}
+	if err := dao.RestoreAssetMaps(cfg.RestoreAssetMaps); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func handleDelAsset(ctx *cli.Context) error {
+	asset := ctx.String(getFlagName(AssetFlag))
+	if asset == "" {
+		return fmt.Errorf("asset is required")
+	}
+	log.Info("start to remove NFT asset %s...", asset)
+
+	cfg := new(AddAssetConfig)
+	cfgPath := ctx.String(getFlagName(ConfigPathFlag))
+	if err := files.ReadJsonFile(cfgPath, cfg); err != nil {
+		return fmt.Errorf("read config json file, err: %v", err)
+	}
+
+	dao := crosschaindao.NewCrossChainDao(cfg.Server, cfg.Backup, cfg.DBConfig)
+	return dao.RemoveAssets([]string{asset})
+}
+
+func handlePendingAssets(ctx *cli.Context) error {
+	cfg := new(AddAssetConfig)
+	cfgPath := ctx.String(getFlagName(ConfigPathFlag))
+	if err := files.ReadJsonFile(cfgPath, cfg); err != nil {
+		return fmt.Errorf("read config json file, err: %v", err)
+	}
+
+	dao := crosschaindao.NewCrossChainDao(cfg.Server, cfg.Backup, cfg.DBConfig)
+	assets, maps, err := dao.GetPendingAssets()
+	if err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		fmt.Printf("asset %s, chain: %d, hash: %s, name: %s, base uri: %s\n",
+			asset.AssetBasicName, asset.ChainId, asset.Hash, asset.Name, asset.BaseUri)
+	}
+	for _, mp := range maps {
+		dst := "not found"
+		if mp.DstAsset != nil {
+			dst = mp.DstAsset.AssetBasicName
+		}
+		fmt.Printf("map chain %d %s -> chain %d %s (%s)\n",
+			mp.SrcChainId, mp.SrcAssetHash, mp.DstChainId, mp.DstAssetHash, dst)
+	}
 	return nil
 }
 
+func handleApproveAsset(ctx *cli.Context) error {
+	asset := ctx.String(getFlagName(AssetFlag))
+	if asset == "" {
+		return fmt.Errorf("asset is required")
+	}
+	log.Info("start to approve NFT asset %s...", asset)
+
+	cfg := new(AddAssetConfig)
+	cfgPath := ctx.String(getFlagName(ConfigPathFlag))
+	if err := files.ReadJsonFile(cfgPath, cfg); err != nil {
+		return fmt.Errorf("read config json file, err: %v", err)
+	}
+
+	dao := crosschaindao.NewCrossChainDao(cfg.Server, cfg.Backup, cfg.DBConfig)
+	return dao.ApproveAssets([]string{asset})
+}
+
 func slimHash(hash string) string {
 	data := strings.TrimPrefix(hash, "0x")
 	return strings.ToLower(data)
@@ -19,16 +19,21 @@ package main
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/astaxie/beego"
 	"github.com/astaxie/beego/context"
 	"github.com/astaxie/beego/logs"
 	"github.com/astaxie/beego/plugins/cors"
 	"github.com/polynetwork/poly-nft-bridge/conf"
-	_ "github.com/polynetwork/poly-nft-bridge/rpc"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
+	"github.com/polynetwork/poly-nft-bridge/rpc"
 	"github.com/polynetwork/poly-nft-bridge/rpc/controllers"
+	"github.com/polynetwork/poly-nft-bridge/rpc/filters"
 )
 
+const apiKeyReloadInterval = time.Minute
+
 func main() {
 	logs.SetLogger(logs.AdapterFile, `{"filename":"logs/rpc.log"}`)
 	mode := beego.AppConfig.String("runmode")
@@ -45,7 +50,11 @@ func main() {
 	if config == nil {
 		panic("startServer - read config failed!")
 	}
-	controllers.Initialize(config)
+	dao := bridgedao.NewBridgeDao(config.Server, config.DBConfig)
+	if dao == nil {
+		panic("startServer - server is invalid!")
+	}
+	rpc.Register(dao, controllers.NewNodes(config))
 
 	beego.InsertFilter("*", beego.BeforeRouter, cors.Allow(&cors.Options{
 		AllowAllOrigins:  true,
@@ -53,9 +62,34 @@ func main() {
 		AllowHeaders:     []string{"Origin", "Authorization", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type"},
 		ExposeHeaders:    []string{"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type"},
 		AllowCredentials: true}))
+	if config.HttpConfig != nil {
+		setFilterApi(config.HttpConfig, dao)
+	}
 	beego.Run()
 }
 
+func setFilterApi(cfg *conf.HttpConfig, dao bridgedao.BridgeDao) {
+	apiFilter := filters.NewApiFilter(cfg)
+	if cfg.KeysFromDB {
+		keys, err := dao.GetApiKeys()
+		if err != nil {
+			panic(err)
+		}
+		apiFilter.SetKeys(keys)
+		go func() {
+			for range time.Tick(apiKeyReloadInterval) {
+				keys, err := dao.GetApiKeys()
+				if err != nil {
+					logs.Error("reload api keys err: %v", err)
+					continue
+				}
+				apiFilter.SetKeys(keys)
+			}
+		}()
+	}
+	beego.InsertFilter("*", beego.BeforeRouter, apiFilter.Filter)
+}
+
 func setFilterLog() {
 	var FilterLog = func(ctx *context.Context) {
 		url, _ := json.Marshal(ctx.Input.Data()["RouterPattern"])
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/polynetwork/poly-nft-bridge/monitor"
+	"github.com/urfave/cli"
+)
+
+var auditCommand = cli.Command{
+	Name:   "audit",
+	Usage:  "audit the nft supply of the mapped collections once and print the report",
+	Action: handleAudit,
+}
+
+func handleAudit(ctx *cli.Context) error {
+	config, db, notifier := setupMonitor(ctx)
+	nodes, proxies := monitor.NewNFTNodes(config.ChainListenConfig)
+	auditor := monitor.NewSupplyAuditor(config.MonitorConfig.Supply, db, nodes, proxies, notifier)
+	audits, err := auditor.Audit()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "COLLECTION\tCHAINS\tTOKENS\tLOCKED\tDOUBLE\tMISSING\tTRUNCATED\tPARTIAL")
+	for _, audit := range audits {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%v\t%v\n", audit.AssetBasicName, audit.Chains, audit.Tokens,
+			audit.Locked, audit.Double, audit.Missing, audit.Truncated == 1, audit.Partial == 1)
+	}
+	w.Flush()
+	for _, audit := range audits {
+		for _, issue := range audit.Issues {
+			fmt.Printf("%s: token %s %s on chains %s\n", audit.AssetBasicName, issue.TokenId, issue.Kind, issue.Chains)
+		}
+	}
+	return nil
+}
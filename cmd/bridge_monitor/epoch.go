@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/polynetwork/poly-nft-bridge/go_abi/eccm_abi"
+	"github.com/polynetwork/poly-nft-bridge/monitor"
+	"github.com/polynetwork/poly-nft-bridge/utils/files"
+	"github.com/urfave/cli"
+)
+
+var payloadFlag = cli.StringFlag{
+	Name:  "payload",
+	Usage: "write the changeBookKeeper payloads of the side chains missing poly epochs to `<path>`",
+}
+
+var epochCommand = cli.Command{
+	Name:   "epoch",
+	Usage:  "compare the poly epoch kept by the eccd of each side chain with the current one once and print the report",
+	Action: handleEpoch,
+	Flags: []cli.Flag{
+		payloadFlag,
+	},
+}
+
+// epochPayload is what a side chain submits to its eccm to catch up with
+// poly, the changes are submitted in order.
+type epochPayload struct {
+	ChainId    uint64
+	ECCM       string
+	ECCDHeight uint64
+	Changes    []*epochChange
+}
+
+// epochChange is the arguments of changeBookKeeper, and Data is the input of
+// the transaction calling it, anyone may send it.
+type epochChange struct {
+	Height     uint64
+	RawHeader  string
+	PubKeyList string
+	SigList    string
+	Data       string
+}
+
+func handleEpoch(ctx *cli.Context) error {
+	config, _, _ := setupMonitor(ctx)
+	checker, err := monitor.NewEpochChecker(monitor.NewEpochNodes(config.ChainListenConfig))
+	if err != nil {
+		return err
+	}
+	epoch, states, err := checker.Check()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("poly epoch %d, keepers %d\n", epoch.Height, len(epoch.Keepers))
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CHAIN\tECCM\tECCD EPOCH\tKEEPERS\tSYNCED")
+	for _, state := range states {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%v\n", state.ChainId, state.ECCM.Hex(), state.Height, len(state.Keepers), state.Synced)
+	}
+	w.Flush()
+
+	path := ctx.String(getFlagName(payloadFlag))
+	if path == "" {
+		return nil
+	}
+	eccm, err := abi.JSON(strings.NewReader(eccm_abi.EthCrossChainManagerABI))
+	if err != nil {
+		return err
+	}
+	payloads := make([]*epochPayload, 0)
+	for _, state := range states {
+		if state.Synced {
+			continue
+		}
+		changes, err := checker.Changes(state.Height)
+		if err != nil {
+			return err
+		}
+		payload := &epochPayload{ChainId: state.ChainId, ECCM: state.ECCM.Hex(), ECCDHeight: state.Height}
+		for _, change := range changes {
+			data, err := eccm.Pack("changeBookKeeper", change.RawHeader, change.PubKeyList, change.SigList)
+			if err != nil {
+				return err
+			}
+			payload.Changes = append(payload.Changes, &epochChange{
+				Height:     change.Height,
+				RawHeader:  hex.EncodeToString(change.RawHeader),
+				PubKeyList: hex.EncodeToString(change.PubKeyList),
+				SigList:    hex.EncodeToString(change.SigList),
+				Data:       hexutil.Encode(data),
+			})
+		}
+		payloads = append(payloads, payload)
+	}
+	if err := files.WriteJsonFile(path, payloads, true); err != nil {
+		return err
+	}
+	fmt.Printf("payloads of %d side chains written to %s\n", len(payloads), path)
+	return nil
+}
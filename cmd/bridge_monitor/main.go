@@ -0,0 +1,198 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	"github.com/polynetwork/poly-nft-bridge/dao/monitordao"
+	"github.com/polynetwork/poly-nft-bridge/monitor"
+	"github.com/urfave/cli"
+)
+
+var (
+	unlockMonitor *monitor.UnlockMonitor
+	supplyAuditor *monitor.SupplyAuditor
+	ruleEngine    *monitor.RuleEngine
+)
+
+var (
+	logLevelFlag = cli.UintFlag{
+		Name:  "loglevel",
+		Usage: "Set the log level to `<level>` (0~6). 0:Trace 1:Debug 2:Info 3:Warn 4:Error 5:Fatal 6:MaxLevel",
+		Value: 1,
+	}
+
+	logDirFlag = cli.StringFlag{
+		Name:  "logdir",
+		Usage: "log directory",
+		Value: "logs",
+	}
+
+	configPathFlag = cli.StringFlag{
+		Name:  "config",
+		Usage: "Server config file `<path>`",
+		Value: "config.json",
+	}
+)
+
+// getFlagName deal with short flag, and return the flag name whether flag name have short name
+func getFlagName(flag cli.Flag) string {
+	name := flag.GetName()
+	if name == "" {
+		return ""
+	}
+	return strings.TrimSpace(strings.Split(name, ",")[0])
+}
+
+func setupApp() *cli.App {
+	app := cli.NewApp()
+	app.Usage = "bridge monitor Service"
+	app.Action = StartServer
+	app.Version = "1.0.0"
+	app.Copyright = "Copyright in 2019 The Ontology Authors"
+	app.Flags = []cli.Flag{
+		logLevelFlag,
+		configPathFlag,
+		logDirFlag,
+	}
+	app.Commands = []cli.Command{
+		auditCommand,
+		epochCommand,
+	}
+	app.Before = func(context *cli.Context) error {
+		runtime.GOMAXPROCS(runtime.NumCPU())
+		return nil
+	}
+	return app
+}
+
+func StartServer(ctx *cli.Context) {
+	for true {
+		startServer(ctx)
+		sig := waitSignal()
+		stopServer()
+		if sig != syscall.SIGHUP {
+			break
+		} else {
+			continue
+		}
+	}
+}
+
+func startServer(ctx *cli.Context) {
+	// instance beego log
+	loglevel := ctx.GlobalUint64(getFlagName(logLevelFlag))
+	logFormat := fmt.Sprintf(`{"filename":"logs/monitor.log", "level:":"%d"}`, loglevel)
+	logs.SetLogger(logs.AdapterFile, logFormat)
+
+	config, db, notifier := setupMonitor(ctx)
+	monitorConfig := config.MonitorConfig
+
+	unlockMonitor = monitor.NewUnlockMonitor(monitorConfig.Unlock, db, notifier)
+	unlockMonitor.Start()
+
+	nodes, proxies := monitor.NewNFTNodes(config.ChainListenConfig)
+	supplyAuditor = monitor.NewSupplyAuditor(monitorConfig.Supply, db, nodes, proxies, notifier)
+	supplyAuditor.Start()
+
+	ruleEngine = monitor.NewRuleEngine(monitorConfig.Rules, db, monitor.NewHeightNodes(config.ChainListenConfig), notifier)
+	if monitorConfig.Balance != nil {
+		balanceNodes, wrappers := monitor.NewBalanceNodes(config.ChainListenConfig)
+		balanceRule, err := monitor.NewBalanceRule(monitorConfig.Balance, db, balanceNodes, wrappers)
+		if err != nil {
+			panic(err)
+		}
+		ruleEngine.AddRule(balanceRule)
+	}
+	if monitorConfig.Epoch != nil {
+		checker, err := monitor.NewEpochChecker(monitor.NewEpochNodes(config.ChainListenConfig))
+		if err != nil {
+			panic(err)
+		}
+		ruleEngine.AddRule(monitor.NewEpochRule(monitorConfig.Epoch, checker))
+	}
+	ruleEngine.Start()
+}
+
+func setupMonitor(ctx *cli.Context) (*conf.Config, monitordao.MonitorDao, *alert.Notifier) {
+	// load configuration
+	configFile := ctx.GlobalString(getFlagName(configPathFlag))
+	config := conf.NewConfig(configFile)
+	if config == nil {
+		panic("startServer - read config failed!")
+	}
+	if config.MonitorConfig == nil {
+		config.MonitorConfig = &conf.MonitorConfig{}
+	}
+
+	// generate dao
+	db := monitordao.NewMonitorDao(config.Server, config.DBConfig)
+	if db == nil {
+		panic("server is invalid")
+	}
+
+	notifier, err := alert.NewNotifierFromConfig(config.MonitorConfig.Sinks)
+	if err != nil {
+		panic(err)
+	}
+	// the rules repeat their alerts on every evaluation
+	if config.MonitorConfig.Throttle == nil {
+		config.MonitorConfig.Throttle = &conf.AlertThrottleConfig{Dedup: 3600, Limit: 30, Period: 60}
+	}
+	notifier.SetThrottle(config.MonitorConfig.Throttle)
+	return config, db, notifier
+}
+
+func waitSignal() os.Signal {
+	exit := make(chan os.Signal, 0)
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sc)
+	go func() {
+		for sig := range sc {
+			logs.Info("bridge monitor received signal:(%s).", sig.String())
+			exit <- sig
+			close(exit)
+			break
+		}
+	}()
+	sig := <-exit
+	return sig
+}
+
+func stopServer() {
+	unlockMonitor.Stop()
+	supplyAuditor.Stop()
+	ruleEngine.Stop()
+}
+
+func main() {
+	if err := setupApp().Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/astaxie/beego/logs"
 	"github.com/polynetwork/poly-nft-bridge/conf"
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
 	"github.com/polynetwork/poly-nft-bridge/models"
@@ -103,3 +104,22 @@ func NewTransactionsConfig(filePath string) *TransactionsConfig {
 	}
 	return config
 }
+
+type MigrateConfig struct {
+	DBConfig *conf.DBConfig
+}
+
+func NewMigrateConfig(filePath string) *MigrateConfig {
+	fileContent, err := basedef.ReadFile(filePath)
+	if err != nil {
+		logs.Error("NewMigrateConfig: failed, err: %s", err)
+		return nil
+	}
+	config := &MigrateConfig{}
+	err = json.Unmarshal(fileContent, config)
+	if err != nil {
+		logs.Error("NewMigrateConfig: failed, err: %s", err)
+		return nil
+	}
+	return config
+}
@@ -21,28 +21,18 @@ import (
 	"fmt"
 
 	"github.com/polynetwork/poly-nft-bridge/conf"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
 	"github.com/polynetwork/poly-nft-bridge/models"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 func dumpStatus(dbCfg *conf.DBConfig) {
-	Logger := logger.Default
-	if dbCfg.Debug == true {
-		Logger = Logger.LogMode(logger.Info)
-	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
-	if err != nil {
-		panic(err)
-	}
+	db := dbopen.MustOpen(dbCfg)
 	{
 		chains := make([]*models.Chain, 0)
 		db.Find(&chains)
 		fmt.Printf("chain info:\nchainid\t\t\t\theight\t\t\t\t\n")
 		for _, chain := range chains {
-			fmt.Printf("%d\t\t\t\t%d\t\t\t\t\n", *chain.ChainId, chain.Height)
+			fmt.Printf("%d\t\t\t\t%d\t\t\t\t\n", chain.ChainId, chain.Height)
 		}
 	}
 	{
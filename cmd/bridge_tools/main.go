@@ -74,7 +74,10 @@ func setupApp() *cli.App {
 		logDirFlag,
 		cmdFlag,
 	}
-	app.Commands = []cli.Command{}
+	app.Commands = []cli.Command{
+		migrateCommand,
+		verifyCommand,
+	}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
 		return nil
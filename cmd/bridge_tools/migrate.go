@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polynetwork/poly-nft-bridge/cmd/bridge_tools/conf"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/dao/migration"
+	"github.com/urfave/cli"
+	"gorm.io/gorm"
+)
+
+var (
+	migrateToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "migrate up to version `<version>`, 0 means the latest",
+		Value: 0,
+	}
+
+	migrateStepsFlag = cli.IntFlag{
+		Name:  "steps",
+		Usage: "number of migrations to revert",
+		Value: 1,
+	}
+)
+
+var migrateCommand = cli.Command{
+	Name:  "migrate",
+	Usage: "manage the versioned database schema",
+	Subcommands: []cli.Command{
+		{
+			Name:   "up",
+			Usage:  "apply the pending migrations",
+			Flags:  []cli.Flag{migrateToFlag},
+			Action: migrateUp,
+		},
+		{
+			Name:   "down",
+			Usage:  "revert the last applied migrations",
+			Flags:  []cli.Flag{migrateStepsFlag},
+			Action: migrateDown,
+		},
+		{
+			Name:   "status",
+			Usage:  "show the applied and pending migrations",
+			Action: migrateStatus,
+		},
+	},
+}
+
+func migrateDB(ctx *cli.Context) (*gorm.DB, error) {
+	configFile := ctx.GlobalString(getFlagName(configPathFlag))
+	config := conf.NewMigrateConfig(configFile)
+	if config == nil || config.DBConfig == nil {
+		return nil, fmt.Errorf("read config %s failed", configFile)
+	}
+	return dbopen.Open(config.DBConfig)
+}
+
+func migrateUp(ctx *cli.Context) error {
+	db, err := migrateDB(ctx)
+	if err != nil {
+		return err
+	}
+	done, err := migration.Up(db, ctx.Uint64(getFlagName(migrateToFlag)))
+	for _, m := range done {
+		fmt.Printf("applied %d %s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(done) == 0 {
+		fmt.Printf("nothing to apply\n")
+	}
+	return nil
+}
+
+func migrateDown(ctx *cli.Context) error {
+	db, err := migrateDB(ctx)
+	if err != nil {
+		return err
+	}
+	done, err := migration.Down(db, ctx.Int(getFlagName(migrateStepsFlag)))
+	for _, m := range done {
+		fmt.Printf("reverted %d %s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(done) == 0 {
+		fmt.Printf("nothing to revert\n")
+	}
+	return nil
+}
+
+func migrateStatus(ctx *cli.Context) error {
+	db, err := migrateDB(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := migration.Status(db)
+	if err != nil {
+		return err
+	}
+	for _, s := range status {
+		if s.Applied {
+			fmt.Printf("%04d %-32s applied at %s\n", s.Version, s.Name, time.Unix(s.AppliedAt, 0).Format(time.RFC3339))
+		} else {
+			fmt.Printf("%04d %-32s pending\n", s.Version, s.Name)
+		}
+	}
+	return nil
+}
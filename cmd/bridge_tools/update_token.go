@@ -22,25 +22,18 @@ import (
 
 	"github.com/polynetwork/poly-nft-bridge/cmd/bridge_tools/conf"
 	"github.com/polynetwork/poly-nft-bridge/dao/crosschaindao"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/dao/migration"
 	"github.com/polynetwork/poly-nft-bridge/models"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 func startUpdateToken(cfg *conf.DeployConfig) {
 	dbCfg := cfg.DBConfig
-	Logger := logger.Default
-	if dbCfg.Debug == true {
-		Logger = Logger.LogMode(logger.Info)
-	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
+	db, err := dbopen.Open(dbCfg)
 	if err != nil {
 		panic(err)
 	}
-	err = db.AutoMigrate(&models.Chain{}, &models.WrapperTransaction{}, &models.ChainFee{}, &models.TokenBasic{}, &models.Token{}, &models.PriceMarket{},
-		&models.TokenMap{}, &models.SrcTransaction{}, &models.SrcTransfer{}, &models.PolyTransaction{}, &models.DstTransaction{}, &models.DstTransfer{})
+	_, err = migration.Up(db, 0)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,273 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/urfave/cli"
+)
+
+var verifyCommand = cli.Command{
+	Name:   "verify",
+	Usage:  "check the contracts of the listened chains and the asset maps of the db are wired on chain",
+	Action: verify,
+}
+
+const (
+	verifyPass = "PASS"
+	verifyFail = "FAIL"
+	verifyNone = "-"
+)
+
+// verifyChain is a chain of the listener config with the sdk of its first
+// working node.
+type verifyChain struct {
+	cfg *conf.ChainListenConfig
+	sdk *eth_sdk.EthereumSdk
+	err error
+}
+
+func (c *verifyChain) name() string {
+	return fmt.Sprintf("%s(%d)", c.cfg.ChainName, c.cfg.ChainId)
+}
+
+type verifyCheck struct {
+	name string
+	run  func(c *verifyChain) error
+}
+
+// verifyReport keeps the failures in the order they are found, a chain
+// without working node is reported once.
+type verifyReport struct {
+	failures []string
+	seen     map[string]bool
+}
+
+func (r *verifyReport) cell(what string, err error) string {
+	if err == nil {
+		return verifyPass
+	}
+	failure := fmt.Sprintf("%s: %v", what, err)
+	if !r.seen[failure] {
+		r.seen[failure] = true
+		r.failures = append(r.failures, failure)
+	}
+	return verifyFail
+}
+
+func verify(ctx *cli.Context) error {
+	configFile := ctx.GlobalString(getFlagName(configPathFlag))
+	config := conf.NewConfig(configFile)
+	if config == nil || config.DBConfig == nil {
+		return fmt.Errorf("read config %s failed", configFile)
+	}
+	db, err := dbopen.Open(config.DBConfig)
+	if err != nil {
+		return err
+	}
+	assetMaps := make([]*models.NFTAssetMap, 0)
+	if err := db.Where("disable = ?", basedef.ASSET_ENABLE).Find(&assetMaps).Error; err != nil {
+		return err
+	}
+
+	chains := make([]*verifyChain, 0)
+	for _, cfg := range config.ChainListenConfig {
+		if cfg.ChainId == basedef.POLY_CROSSCHAIN_ID || cfg.ProxyContract == "" {
+			continue
+		}
+		chain := &verifyChain{cfg: cfg, err: fmt.Errorf("no node")}
+		for _, url := range cfg.GetNodesUrl() {
+			if chain.sdk, chain.err = eth_sdk.NewEthereumSdk(url); chain.err == nil {
+				break
+			}
+		}
+		chains = append(chains, chain)
+	}
+
+	report := &verifyReport{seen: make(map[string]bool)}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	header := []string{"CHECK"}
+	for _, chain := range chains {
+		header = append(header, chain.name())
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, check := range verifyChecks(chains) {
+		row := []string{check.name}
+		for _, chain := range chains {
+			if chain.err != nil {
+				row = append(row, report.cell(chain.name(), chain.err))
+				continue
+			}
+			if err := check.run(chain); err == errVerifySkip {
+				row = append(row, verifyNone)
+			} else {
+				row = append(row, report.cell(fmt.Sprintf("%s %s", chain.name(), check.name), err))
+			}
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "ASSET MAP\tFORWARD\tBACKWARD")
+	for _, pair := range verifyAssetPairs(assetMaps) {
+		src, dst := findVerifyChain(chains, pair.SrcChainId), findVerifyChain(chains, pair.DstChainId)
+		what := fmt.Sprintf("%d:%s <-> %d:%s", pair.SrcChainId, pair.SrcAssetHash, pair.DstChainId, pair.DstAssetHash)
+		forward := report.cell(what+" forward", verifyAssetBind(src, pair.SrcAssetHash, dst, pair.DstAssetHash))
+		backward := report.cell(what+" backward", verifyAssetBind(dst, pair.DstAssetHash, src, pair.SrcAssetHash))
+		fmt.Fprintf(w, "%s\t%s\t%s\n", what, forward, backward)
+	}
+	w.Flush()
+
+	if len(report.failures) == 0 {
+		fmt.Printf("\nall checks passed\n")
+		return nil
+	}
+	fmt.Printf("\nfailures:\n")
+	for _, failure := range report.failures {
+		fmt.Printf("  %s\n", failure)
+	}
+	return fmt.Errorf("%d checks failed", len(report.failures))
+}
+
+var errVerifySkip = fmt.Errorf("skip")
+
+func verifyChecks(chains []*verifyChain) []*verifyCheck {
+	checks := []*verifyCheck{
+		{"eccd owner is eccm", func(c *verifyChain) error {
+			eccm := common.HexToAddress(c.cfg.ECCMContract)
+			eccd, err := c.sdk.GetECCMData(eccm)
+			if err != nil {
+				return err
+			}
+			owner, err := c.sdk.GetECCDOwnership(eccd)
+			return expectVerifyAddress(owner, err, eccm)
+		}},
+		{"eccm owner is ccmp", func(c *verifyChain) error {
+			owner, err := c.sdk.GetECCMOwnership(common.HexToAddress(c.cfg.ECCMContract))
+			return expectVerifyAddress(owner, err, common.HexToAddress(c.cfg.CCMPContract))
+		}},
+		{"ccmp manager is eccm", func(c *verifyChain) error {
+			eccm, err := c.sdk.GetCCMPManager(common.HexToAddress(c.cfg.CCMPContract))
+			return expectVerifyAddress(eccm, err, common.HexToAddress(c.cfg.ECCMContract))
+		}},
+		{"proxy managerProxy is ccmp", func(c *verifyChain) error {
+			ccmp, err := c.sdk.GetLockProxyNFTCCMP(common.HexToAddress(c.cfg.ProxyContract))
+			return expectVerifyAddress(ccmp, err, common.HexToAddress(c.cfg.CCMPContract))
+		}},
+		{"wrapper lockProxy is proxy", func(c *verifyChain) error {
+			proxy, err := c.sdk.GetWrapLockProxy(common.HexToAddress(c.cfg.WrapperContract))
+			return expectVerifyAddress(proxy, err, common.HexToAddress(c.cfg.ProxyContract))
+		}},
+		{"wrapper feeCollector is set", func(c *verifyChain) error {
+			collector, err := c.sdk.GetWrapFeeCollector(common.HexToAddress(c.cfg.WrapperContract))
+			if err != nil {
+				return err
+			}
+			if collector == eth_sdk.EmptyAddress {
+				return fmt.Errorf("no fee collector")
+			}
+			return nil
+		}},
+		{"wrapper not paused", func(c *verifyChain) error {
+			paused, err := c.sdk.GetWrapPaused(common.HexToAddress(c.cfg.WrapperContract))
+			if err != nil {
+				return err
+			}
+			if paused {
+				return fmt.Errorf("paused")
+			}
+			return nil
+		}},
+	}
+	for _, target := range chains {
+		target := target
+		checks = append(checks, &verifyCheck{
+			name: fmt.Sprintf("proxy bound to %s", target.name()),
+			run: func(c *verifyChain) error {
+				if c == target {
+					return errVerifySkip
+				}
+				bound, err := c.sdk.GetBoundNFTProxy(common.HexToAddress(c.cfg.ProxyContract), target.cfg.ChainId)
+				return expectVerifyAddress(bound, err, common.HexToAddress(target.cfg.ProxyContract))
+			},
+		})
+	}
+	return checks
+}
+
+// verifyAssetPairs keeps one of the two maps between a pair of assets, both
+// ways of it are checked.
+func verifyAssetPairs(assetMaps []*models.NFTAssetMap) []*models.NFTAssetMap {
+	pairs := make([]*models.NFTAssetMap, 0)
+	seen := make(map[string]bool)
+	key := func(chainId uint64, hash string) string {
+		return fmt.Sprintf("%d:%s", chainId, strings.ToLower(common.HexToAddress(hash).Hex()))
+	}
+	for _, m := range assetMaps {
+		if m.SrcChainId == m.DstChainId {
+			continue
+		}
+		src, dst := key(m.SrcChainId, m.SrcAssetHash), key(m.DstChainId, m.DstAssetHash)
+		if seen[src+dst] || seen[dst+src] {
+			continue
+		}
+		seen[src+dst] = true
+		pairs = append(pairs, m)
+	}
+	return pairs
+}
+
+func findVerifyChain(chains []*verifyChain, chainId uint64) *verifyChain {
+	for _, chain := range chains {
+		if chain.cfg.ChainId == chainId {
+			return chain
+		}
+	}
+	return nil
+}
+
+func verifyAssetBind(src *verifyChain, srcAsset string, dst *verifyChain, dstAsset string) error {
+	if src == nil || dst == nil {
+		return fmt.Errorf("chain not listened")
+	}
+	if src.err != nil {
+		return src.err
+	}
+	bound, err := src.sdk.GetBoundNFTAsset(common.HexToAddress(src.cfg.ProxyContract), common.HexToAddress(srcAsset), dst.cfg.ChainId)
+	return expectVerifyAddress(bound, err, common.HexToAddress(dstAsset))
+}
+
+func expectVerifyAddress(got common.Address, err error, expect common.Address) error {
+	if err != nil {
+		return err
+	}
+	if got != expect {
+		return fmt.Errorf("got %s, expect %s", got.Hex(), expect.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/utils/files"
+	"github.com/urfave/cli"
+)
+
+// Plan describes the contracts of one side chain. `apply` runs its steps in
+// order and keeps what is done in the state file, so a failed apply is resumed
+// by running it again.
+type Plan struct {
+	ChainId uint64
+
+	// State is the state file, `deploy_state_<chain>.json` by default.
+	State string
+
+	// FeeCollector of the wrapper, the one of the chain config by default.
+	FeeCollector string
+
+	// BindProxies are the lock proxies of the other chains, an empty Proxy
+	// is taken from the config of that chain.
+	BindProxies []*PlanProxy
+
+	// Steps run in order, all of them by default.
+	Steps []string
+}
+
+type PlanProxy struct {
+	ChainId uint64
+	Proxy   string
+}
+
+// DeployState records the steps which are done, by step name. The bindings
+// of the lock proxy are named `bindProxy:<chain>`.
+type DeployState struct {
+	ChainId uint64
+	Steps   map[string]*StepState
+}
+
+type StepState struct {
+	Address string `json:",omitempty"`
+	TxHash  string `json:",omitempty"`
+	Time    int64
+}
+
+const (
+	stepDeployECCD            = "deployECCD"
+	stepDeployECCM            = "deployECCM"
+	stepDeployCCMP            = "deployCCMP"
+	stepTransferECCDOwnership = "transferECCDOwnership"
+	stepTransferECCMOwnership = "transferECCMOwnership"
+	stepDeployNFTLockProxy    = "deployNFTLockProxy"
+	stepProxySetCCMP          = "proxySetCCMP"
+	stepBindProxy             = "bindProxy"
+	stepDeployNFTWrapper      = "deployNFTWrapper"
+	stepSetWrapLockProxy      = "setWrapLockProxy"
+	stepSetFeeCollector       = "setFeeCollector"
+)
+
+var defaultPlanSteps = []string{
+	stepDeployECCD,
+	stepDeployECCM,
+	stepDeployCCMP,
+	stepTransferECCDOwnership,
+	stepTransferECCMOwnership,
+	stepDeployNFTLockProxy,
+	stepProxySetCCMP,
+	stepBindProxy,
+	stepDeployNFTWrapper,
+	stepSetWrapLockProxy,
+	stepSetFeeCollector,
+}
+
+// planStep is a step of the plan. A deploy step returns the address of the
+// contract, the others the hash of their transaction. verify checks the
+// post condition of the step on chain. field is the chain config field a
+// deploy step keeps the address in.
+type planStep struct {
+	name   string
+	deploy bool
+	field  *string
+	run    func() (common.Address, common.Hash, error)
+	verify func() error
+}
+
+func handleCmdApply(ctx *cli.Context) error {
+	path := ctx.Args().First()
+	if path == "" {
+		return fmt.Errorf("usage: deploy_tool apply plan.json")
+	}
+	plan := new(Plan)
+	if err := files.ReadJsonFile(path, plan); err != nil {
+		return fmt.Errorf("read plan %s failed, err: %v", path, err)
+	}
+	if plan.ChainId != cc.SideChainID {
+		return fmt.Errorf("plan is for chain %d, but chain %d is selected", plan.ChainId, cc.SideChainID)
+	}
+	if plan.State == "" {
+		plan.State = fmt.Sprintf("deploy_state_%d.json", plan.ChainId)
+	}
+	if plan.FeeCollector != "" {
+		cc.FeeCollector = plan.FeeCollector
+	}
+	names := plan.Steps
+	if len(names) == 0 {
+		names = defaultPlanSteps
+	}
+
+	state, err := loadDeployState(plan.State, plan.ChainId)
+	if err != nil {
+		return err
+	}
+	steps := make([]*planStep, 0, len(names))
+	for _, name := range names {
+		planSteps, err := makePlanSteps(plan, name)
+		if err != nil {
+			return err
+		}
+		steps = append(steps, planSteps...)
+	}
+
+	log.Info("apply plan %s on chain %d, %d steps, state %s", path, plan.ChainId, len(steps), plan.State)
+	for _, step := range steps {
+		if err := applyStep(step, state, plan.State); err != nil {
+			return fmt.Errorf("apply step %s on chain %d failed, err: %v", step.name, plan.ChainId, err)
+		}
+	}
+	log.Info("apply plan %s on chain %d success!", path, plan.ChainId)
+	return updateConfig()
+}
+
+// applyStep skips a step which is recorded, a recorded step which does not
+// hold on chain any more is an error rather than done again. A step which is
+// not a deploy and holds already is recorded without a transaction.
+func applyStep(step *planStep, state *DeployState, statePath string) error {
+	if done, ok := state.Steps[step.name]; ok {
+		if step.deploy {
+			if err := restoreAddress(step, done); err != nil {
+				return err
+			}
+		}
+		if err := step.verify(); err != nil {
+			return fmt.Errorf("recorded at %s but %v", time.Unix(done.Time, 0).Format(time.RFC3339), err)
+		}
+		log.Info("step %s is done, skip", step.name)
+		return nil
+	}
+	if !step.deploy && step.verify() == nil {
+		log.Info("step %s holds already, record it", step.name)
+		state.Steps[step.name] = &StepState{Time: time.Now().Unix()}
+		return saveDeployState(statePath, state)
+	}
+
+	log.Info("start to run step %s...", step.name)
+	addr, hash, err := step.run()
+	if err != nil {
+		return err
+	}
+	done := &StepState{TxHash: hash.Hex(), Time: time.Now().Unix()}
+	if step.deploy {
+		done.Address = addr.Hex()
+	}
+	// the state is saved before the check, a step which is sent is never
+	// sent again
+	state.Steps[step.name] = done
+	if err := saveDeployState(statePath, state); err != nil {
+		return err
+	}
+	if step.deploy {
+		if err := updateConfig(); err != nil {
+			return err
+		}
+	}
+	if err := step.verify(); err != nil {
+		return err
+	}
+	log.Info("step %s success, address %s, txhash %s", step.name, done.Address, done.TxHash)
+	return nil
+}
+
+// restoreAddress puts the recorded address of a deploy step back into the
+// chain config, which misses it when the config was not written after the
+// deployment. A config which has another address is not overwritten.
+func restoreAddress(step *planStep, done *StepState) error {
+	if done.Address == "" {
+		return fmt.Errorf("no address recorded in the state")
+	}
+	recorded := common.HexToAddress(done.Address)
+	if *step.field == "" {
+		log.Info("restore address %s of step %s to the chain config", recorded.Hex(), step.name)
+		*step.field = recorded.Hex()
+		return updateConfig()
+	}
+	if common.HexToAddress(*step.field) != recorded {
+		return fmt.Errorf("chain config has %s but the state recorded %s, fix one of them", *step.field, done.Address)
+	}
+	return nil
+}
+
+func makePlanSteps(plan *Plan, name string) ([]*planStep, error) {
+	switch name {
+	case stepDeployECCD:
+		return []*planStep{deployPlanStep(name, &cc.ECCD, func() (common.Address, common.Hash, error) {
+			return sdk.DeployECCDContract(adm)
+		})}, nil
+	case stepDeployECCM:
+		return []*planStep{deployPlanStep(name, &cc.ECCM, func() (common.Address, common.Hash, error) {
+			return sdk.DeployECCMContract(adm, common.HexToAddress(cc.ECCD), cc.SideChainID)
+		})}, nil
+	case stepDeployCCMP:
+		return []*planStep{deployPlanStep(name, &cc.CCMP, func() (common.Address, common.Hash, error) {
+			return sdk.DeployECCMPContract(adm, common.HexToAddress(cc.ECCM))
+		})}, nil
+	case stepDeployNFTLockProxy:
+		return []*planStep{deployPlanStep(name, &cc.NFTLockProxy, func() (common.Address, common.Hash, error) {
+			return sdk.DeployNFTLockProxy(adm)
+		})}, nil
+	case stepDeployNFTWrapper:
+		return []*planStep{deployPlanStep(name, &cc.NFTWrap, func() (common.Address, common.Hash, error) {
+			return sdk.DeployWrapContract(adm, cc.SideChainID)
+		})}, nil
+	case stepTransferECCDOwnership:
+		return []*planStep{{
+			name: name,
+			run: func() (common.Address, common.Hash, error) {
+				hash, err := sdk.TransferECCDOwnership(adm, common.HexToAddress(cc.ECCD), common.HexToAddress(cc.ECCM))
+				return eth_sdk.EmptyAddress, hash, err
+			},
+			verify: func() error {
+				owner, err := sdk.GetECCDOwnership(common.HexToAddress(cc.ECCD))
+				return expectAddress("eccd owner", owner, err, cc.ECCM)
+			},
+		}}, nil
+	case stepTransferECCMOwnership:
+		return []*planStep{{
+			name: name,
+			run: func() (common.Address, common.Hash, error) {
+				hash, err := sdk.TransferECCMOwnership(adm, common.HexToAddress(cc.ECCM), common.HexToAddress(cc.CCMP))
+				return eth_sdk.EmptyAddress, hash, err
+			},
+			verify: func() error {
+				owner, err := sdk.GetECCMOwnership(common.HexToAddress(cc.ECCM))
+				return expectAddress("eccm owner", owner, err, cc.CCMP)
+			},
+		}}, nil
+	case stepProxySetCCMP:
+		return []*planStep{{
+			name: name,
+			run: func() (common.Address, common.Hash, error) {
+				hash, err := sdk.NFTLockProxySetCCMP(adm, common.HexToAddress(cc.NFTLockProxy), common.HexToAddress(cc.CCMP))
+				return eth_sdk.EmptyAddress, hash, err
+			},
+			verify: func() error {
+				ccmp, err := sdk.GetLockProxyNFTCCMP(common.HexToAddress(cc.NFTLockProxy))
+				return expectAddress("lock proxy ccmp", ccmp, err, cc.CCMP)
+			},
+		}}, nil
+	case stepBindProxy:
+		if len(plan.BindProxies) == 0 {
+			return nil, fmt.Errorf("step %s needs BindProxies", name)
+		}
+		steps := make([]*planStep, 0, len(plan.BindProxies))
+		for _, bind := range plan.BindProxies {
+			step, err := bindProxyPlanStep(bind)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		}
+		return steps, nil
+	case stepSetWrapLockProxy:
+		return []*planStep{{
+			name: name,
+			run: func() (common.Address, common.Hash, error) {
+				hash, err := sdk.SetWrapLockProxy(adm, common.HexToAddress(cc.NFTWrap), common.HexToAddress(cc.NFTLockProxy))
+				return eth_sdk.EmptyAddress, hash, err
+			},
+			verify: func() error {
+				proxy, err := sdk.GetWrapLockProxy(common.HexToAddress(cc.NFTWrap))
+				return expectAddress("wrapper lock proxy", proxy, err, cc.NFTLockProxy)
+			},
+		}}, nil
+	case stepSetFeeCollector:
+		if cc.FeeCollector == "" {
+			return nil, fmt.Errorf("step %s needs a FeeCollector", name)
+		}
+		return []*planStep{{
+			name: name,
+			run: func() (common.Address, common.Hash, error) {
+				hash, err := sdk.SetWrapFeeCollector(adm, common.HexToAddress(cc.NFTWrap), common.HexToAddress(cc.FeeCollector))
+				return eth_sdk.EmptyAddress, hash, err
+			},
+			verify: func() error {
+				collector, err := sdk.GetWrapFeeCollector(common.HexToAddress(cc.NFTWrap))
+				return expectAddress("wrapper fee collector", collector, err, cc.FeeCollector)
+			},
+		}}, nil
+	}
+	return nil, fmt.Errorf("unknown step %s, steps are %s", name, strings.Join(defaultPlanSteps, ", "))
+}
+
+// deployPlanStep deploys a contract and keeps its address in the chain config
+// field, it holds when there is code at the address.
+func deployPlanStep(name string, field *string, deploy func() (common.Address, common.Hash, error)) *planStep {
+	return &planStep{
+		name:   name,
+		deploy: true,
+		field:  field,
+		run: func() (common.Address, common.Hash, error) {
+			addr, hash, err := deploy()
+			if err != nil {
+				return addr, hash, err
+			}
+			*field = addr.Hex()
+			return addr, hash, nil
+		},
+		verify: func() error {
+			if *field == "" {
+				return fmt.Errorf("no address in the chain config")
+			}
+			exist, err := sdk.HasCode(common.HexToAddress(*field))
+			if err != nil {
+				return err
+			}
+			if !exist {
+				return fmt.Errorf("no contract at %s", *field)
+			}
+			return nil
+		},
+	}
+}
+
+func bindProxyPlanStep(bind *PlanProxy) (*planStep, error) {
+	dstProxy := bind.Proxy
+	if dstProxy == "" {
+		dstProxy = customSelectChainConfig(bind.ChainId).NFTLockProxy
+	}
+	if dstProxy == "" {
+		return nil, fmt.Errorf("lock proxy of chain %d is unknown, apply the plan of that chain first", bind.ChainId)
+	}
+	return &planStep{
+		name: fmt.Sprintf("%s:%d", stepBindProxy, bind.ChainId),
+		run: func() (common.Address, common.Hash, error) {
+			hash, err := sdk.BindLockProxy(adm, common.HexToAddress(cc.NFTLockProxy), common.HexToAddress(dstProxy), bind.ChainId)
+			return eth_sdk.EmptyAddress, hash, err
+		},
+		verify: func() error {
+			bound, err := sdk.GetBoundNFTProxy(common.HexToAddress(cc.NFTLockProxy), bind.ChainId)
+			return expectAddress(fmt.Sprintf("lock proxy bound to chain %d", bind.ChainId), bound, err, dstProxy)
+		},
+	}, nil
+}
+
+func expectAddress(what string, got common.Address, err error, expect string) error {
+	if err != nil {
+		return err
+	}
+	if got != common.HexToAddress(expect) {
+		return fmt.Errorf("%s is %s, expect %s", what, got.Hex(), expect)
+	}
+	return nil
+}
+
+func loadDeployState(path string, chainId uint64) (*DeployState, error) {
+	state := &DeployState{ChainId: chainId, Steps: make(map[string]*StepState)}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return state, nil
+	}
+	if err := files.ReadJsonFile(path, state); err != nil {
+		return nil, fmt.Errorf("read state %s failed, err: %v", path, err)
+	}
+	if state.ChainId != chainId {
+		return nil, fmt.Errorf("state %s is for chain %d", path, state.ChainId)
+	}
+	if state.Steps == nil {
+		state.Steps = make(map[string]*StepState)
+	}
+	return state, nil
+}
+
+func saveDeployState(path string, state *DeployState) error {
+	return files.WriteJsonFile(path, state, true)
+}
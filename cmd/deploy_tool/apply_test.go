@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/test/simulation"
+	"github.com/polynetwork/poly-nft-bridge/utils/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+)
+
+func runApply(planPath string) error {
+	set := flag.NewFlagSet("apply", flag.ContinueOnError)
+	if err := set.Parse([]string{planPath}); err != nil {
+		return err
+	}
+	return handleCmdApply(cli.NewContext(nil, set, nil))
+}
+
+func TestApplyResume(t *testing.T) {
+	chain, err := simulation.NewChain(basedef.ETHEREUM_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "deploy-apply")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cc = &ChainConfig{SideChainID: chain.ChainId}
+	cfg = &Config{Ethereum: cc}
+	sdk, adm = chain.Sdk, chain.Admin
+	planPath, statePath := filepath.Join(dir, "plan.json"), filepath.Join(dir, "state.json")
+	assert.NoError(t, files.WriteJsonFile(planPath, &Plan{
+		ChainId: chain.ChainId,
+		State:   statePath,
+		Steps:   []string{stepDeployECCD, stepDeployECCM, stepTransferECCDOwnership},
+	}, true))
+
+	// the config can not be written, the apply stops after the eccd is deployed
+	cfgPath = filepath.Join(dir, "missing", "config.json")
+	assert.Error(t, runApply(planPath))
+	state, err := loadDeployState(statePath, chain.ChainId)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(state.Steps))
+	eccd := state.Steps[stepDeployECCD].Address
+	assert.NotEmpty(t, eccd)
+
+	// the next run reads a config without the eccd
+	cc.ECCD = ""
+	cfgPath = filepath.Join(dir, "config.json")
+	assert.NoError(t, runApply(planPath))
+	assert.Equal(t, eccd, cc.ECCD)
+	saved := new(Config)
+	assert.NoError(t, files.ReadJsonFile(cfgPath, saved))
+	assert.Equal(t, eccd, saved.Ethereum.ECCD)
+	assert.Equal(t, cc.ECCM, saved.Ethereum.ECCM)
+	owner, err := sdk.GetECCDOwnership(common.HexToAddress(eccd))
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(cc.ECCM), owner)
+
+	// a config which has another address is not overwritten
+	eccm := cc.ECCM
+	cc.ECCM = common.HexToAddress("01").Hex()
+	err = runApply(planPath)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "the state recorded "+eccm)
+	}
+	assert.Equal(t, common.HexToAddress("01").Hex(), cc.ECCM)
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+)
+
+// batchTx is one record of the batch file, counted without the comments.
+type batchTx struct {
+	record int
+	desc   string
+	send   func() (common.Hash, error)
+}
+
+func handleCmdBatch(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("usage: batch <batch.csv>")
+	}
+	txs, err := readBatch(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	concurrency := int(ctx.Uint(getFlagName(ConcurrencyFlag)))
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	log.Info("start to send %d transactions on chain %d, concurrency %d", len(txs), cc.SideChainID, concurrency)
+
+	// the nonce manager of the sdk orders the transactions of the admin,
+	// each worker waits for its own one
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		jobs   = make(chan *batchTx)
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tx := range jobs {
+				hash, err := tx.send()
+				if err != nil {
+					log.Error("record %d %s failed, err: %v", tx.record, tx.desc, err)
+					mu.Lock()
+					failed++
+					mu.Unlock()
+					continue
+				}
+				log.Info("record %d %s success, txhash %s", tx.record, tx.desc, hash.Hex())
+			}
+		}()
+	}
+	for _, tx := range txs {
+		jobs <- tx
+	}
+	close(jobs)
+	wg.Wait()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d transactions failed", failed, len(txs))
+	}
+	log.Info("all %d transactions success", len(txs))
+	return nil
+}
+
+// readBatch parses the whole file before anything is sent, lines starting
+// with `#` are comments.
+func readBatch(path string) ([]*batchTx, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*batchTx, 0, len(records))
+	for i, record := range records {
+		tx, err := parseBatchTx(record)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %v", i+1, err)
+		}
+		tx.record = i + 1
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func parseBatchTx(record []string) (*batchTx, error) {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+	args := record[1:]
+	switch record[0] {
+	case CmdNFTMint.Name:
+		if len(args) != 3 {
+			return nil, fmt.Errorf("usage: %s,<asset>,<to>,<tokenId>", record[0])
+		}
+		asset, err := batchAddress(args[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := batchAddress(args[1])
+		if err != nil {
+			return nil, err
+		}
+		tokenID, ok := new(big.Int).SetString(args[2], 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid token id %s", args[2])
+		}
+		uri := cfg.OSS + tokenID.String()
+		return &batchTx{
+			desc: fmt.Sprintf("mint nft %s of %s to %s", tokenID.String(), asset.Hex(), to.Hex()),
+			send: func() (common.Hash, error) {
+				return sdk.MintNFT(adm, asset, to, tokenID, uri)
+			},
+		}, nil
+
+	case CmdBindNFTAsset.Name:
+		if len(args) != 3 {
+			return nil, fmt.Errorf("usage: %s,<asset>,<dstChain>,<dstAsset>", record[0])
+		}
+		asset, err := batchAddress(args[0])
+		if err != nil {
+			return nil, err
+		}
+		dstChainId, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chain id %s", args[1])
+		}
+		dstAsset, err := batchAddress(args[2])
+		if err != nil {
+			return nil, err
+		}
+		proxy := common.HexToAddress(cc.NFTLockProxy)
+		return &batchTx{
+			desc: fmt.Sprintf("bind nft %s to %s of chain %d", asset.Hex(), dstAsset.Hex(), dstChainId),
+			send: func() (common.Hash, error) {
+				return sdk.BindNFTAsset(adm, proxy, asset, dstAsset, dstChainId)
+			},
+		}, nil
+
+	case CmdNativeTransfer.Name:
+		if len(args) != 2 {
+			return nil, fmt.Errorf("usage: %s,<to>,<amount>", record[0])
+		}
+		to, err := batchAddress(args[0])
+		if err != nil {
+			return nil, err
+		}
+		amount, ok := new(big.Int).SetString(args[1], 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %s", args[1])
+		}
+		return &batchTx{
+			desc: fmt.Sprintf("transfer %s to %s", amount.String(), to.Hex()),
+			send: func() (common.Hash, error) {
+				return sdk.TransferNative(adm, to, amount)
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("command %s can not be batched", record[0])
+}
+
+func batchAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("invalid address %s", s)
+	}
+	return common.HexToAddress(s), nil
+}
@@ -17,6 +17,13 @@
 
 package main
 
+import (
+	"math/big"
+	"time"
+
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+)
+
 type Config struct {
 	Ethereum *ChainConfig
 	Bsc      *ChainConfig
@@ -26,6 +33,9 @@ type Config struct {
 	// leveldb direction
 	LevelDB string
 
+	// password session of the keystore accounts cached in leveldb
+	Session *SessionConfig `json:",omitempty"`
+
 	// oss
 	OSS string
 }
@@ -36,6 +46,13 @@ type ChainConfig struct {
 	RPC           string
 	Admin         string
 	Keystore      string
+	// AdminSigner signs for the admin instead of its keystore file, it is the
+	// url of an external signer such as clef, or `env:<NAME>` for a raw key
+	// in the environment on test networks.
+	AdminSigner string `json:",omitempty"`
+	// Gas prices the transactions of the chain, the gas price suggested by
+	// the node without replacement when it is empty.
+	Gas *GasConfig `json:",omitempty"`
 
 	ECCD string
 	ECCM string
@@ -47,8 +64,53 @@ type ChainConfig struct {
 	FeeCollector string
 }
 
+type GasConfig struct {
+	// Mode is `legacy` or `basefee`.
+	Mode string
+	// MaxGasPriceGwei caps the gas price, including the raised ones, 0 for
+	// no cap.
+	MaxGasPriceGwei uint64
+	// TipCapGwei is the priority fee in basefee mode, 0 for the one suggested
+	// by the node.
+	TipCapGwei uint64
+	// BumpAfter is how many seconds a transaction is pending before it is
+	// replaced with a higher gas price, 0 never replaces.
+	BumpAfter uint64
+	// BumpPercent raises the gas price of a replacement, 10 by default.
+	BumpPercent uint64
+}
+
+func (c *GasConfig) strategy() *eth_sdk.GasStrategy {
+	strategy := &eth_sdk.GasStrategy{
+		Mode:        eth_sdk.FeeMode(c.Mode),
+		BumpAfter:   time.Duration(c.BumpAfter) * time.Second,
+		BumpPercent: c.BumpPercent,
+	}
+	if c.MaxGasPriceGwei > 0 {
+		strategy.MaxGasPrice = gwei(c.MaxGasPriceGwei)
+	}
+	if c.TipCapGwei > 0 {
+		strategy.TipCap = gwei(c.TipCapGwei)
+	}
+	return strategy
+}
+
+func gwei(n uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(n), big.NewInt(1e9))
+}
+
 type PolyConfig struct {
 	RPC        string
 	Keystore   string
 	Passphrase string
 }
+
+type SessionConfig struct {
+	// Keyring is the file holding the key the sessions are encrypted with,
+	// it must not be kept in the leveldb direction. The sessions only last
+	// for one run when it is empty.
+	Keyring string
+	// TTL is how many seconds a session lasts, the default is 30 minutes
+	// and a negative one caches nothing.
+	TTL int64
+}
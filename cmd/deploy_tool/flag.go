@@ -19,6 +19,7 @@ package main
 
 import (
 	"strings"
+	"time"
 
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
 	"github.com/urfave/cli"
@@ -31,6 +32,12 @@ var (
 		Value: 1,
 	}
 
+	ConcurrencyFlag = cli.UintFlag{
+		Name:  "concurrency",
+		Usage: "number of transactions sent at the same time",
+		Value: 4,
+	}
+
 	LogDirFlag = cli.StringFlag{
 		Name:  "logdir",
 		Usage: "log directory",
@@ -117,6 +124,11 @@ var (
 		Usage: "wrap lock nft item id",
 	}
 
+	UnsignedFlag = cli.StringFlag{
+		Name:  "unsigned",
+		Usage: "write the transaction of an admin command unsigned to `<path>` instead of sending it",
+	}
+
 	StartFlag = cli.Uint64Flag{
 		Name: "start",
 		Usage: "batch get user tokens info with index start",
@@ -126,6 +138,37 @@ var (
 		Name: "length",
 		Usage: "batch get user tokens info with length",
 	}
+
+	AllChainsFlag = cli.BoolFlag{
+		Name:  "all",
+		Usage: "inspect all the side chains in config instead of the selected one",
+	}
+
+	JSONFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "print the report in json",
+	}
+
+	ProgressFlag = cli.StringFlag{
+		Name:  "progress",
+		Usage: "batch lock progress file `<path>`, default `<tokens.csv>.progress.json`",
+	}
+
+	BridgeURLFlag = cli.StringFlag{
+		Name:  "bridge",
+		Usage: "nft bridge api `<url>` such as http://127.0.0.1:30330/nft/v1/, track the locks until finished",
+	}
+
+	ApiKeyFlag = cli.StringFlag{
+		Name:  "apikey",
+		Usage: "api `<key>` sent to the nft bridge, needed when it requires auth",
+	}
+
+	TrackTimeoutFlag = cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "how long to track the locks on the bridge",
+		Value: 30 * time.Minute,
+	}
 )
 
 var (
@@ -151,6 +194,54 @@ var (
 		},
 	}
 
+	CmdApply = cli.Command{
+		Name:      "apply",
+		Usage:     "admin account deploy and wire the contracts of a chain from a plan, steps done before are skipped.",
+		ArgsUsage: "<plan.json>",
+		Action:    handleCmdApply,
+	}
+
+	CmdBatch = cli.Command{
+		Name:      "batch",
+		Usage:     "admin account send the transactions of a csv file concurrently, one `mintNFT,<asset>,<to>,<tokenId>`, `bindNFT,<asset>,<dstChain>,<dstAsset>` or `transferNative,<to>,<amount>` a line.",
+		ArgsUsage: "<batch.csv>",
+		Action:    handleCmdBatch,
+		Flags: []cli.Flag{
+			ConcurrencyFlag,
+		},
+	}
+
+	CmdSign = cli.Command{
+		Name:      "sign",
+		Usage:     "admin account sign a transaction written by --unsigned, works without network.",
+		ArgsUsage: "<unsigned.json> <signed.json>",
+		Action:    handleCmdSign,
+	}
+
+	CmdWallet = cli.Command{
+		Name:  "wallet",
+		Usage: "manage the password sessions of the keystore accounts.",
+		Subcommands: []cli.Command{
+			{
+				Name:   "unlock",
+				Usage:  "input the passwords of the admin accounts and poly validators, they are cached until the session expires.",
+				Action: handleCmdWalletUnlock,
+			},
+			{
+				Name:   "lock",
+				Usage:  "remove the cached passwords of all accounts.",
+				Action: handleCmdWalletLock,
+			},
+		},
+	}
+
+	CmdBroadcast = cli.Command{
+		Name:      "broadcast",
+		Usage:     "send a transaction signed by sign and wait until it is confirmed.",
+		ArgsUsage: "<signed.json>",
+		Action:    handleCmdBroadcast,
+	}
+
 	CmdDeployECCDContract = cli.Command{
 		Name:   "deployECCD",
 		Usage:  "admin account deploy ethereum cross chain data contract.",
@@ -265,6 +356,16 @@ var (
 		Action: handleCmdSyncSideChainGenesis2Poly,
 	}
 
+	CmdInspectSideChain = cli.Command{
+		Name:   "inspectSideChain",
+		Usage:  "report the registration, genesis header and eccd epoch of side chain in poly, read only.",
+		Action: handleCmdInspectSideChain,
+		Flags: []cli.Flag{
+			AllChainsFlag,
+			JSONFlag,
+		},
+	}
+
 	CmdSyncPolyGenesis2SideChain = cli.Command{
 		Name:   "syncPolyGenesis",
 		Usage:  "sync poly genesis header to side chain.",
@@ -331,6 +432,25 @@ var (
 		},
 	}
 
+	CmdNFTWrapLockBatch = cli.Command{
+		Name:      "lockNFTBatch",
+		Usage:     "lock the nft tokens of a csv file on wrap contract concurrently, one `<tokenId>,<to>` a line, resumable with the progress file.",
+		ArgsUsage: "<tokens.csv>",
+		Action:    handleCmdNFTWrapLockBatch,
+		Flags: []cli.Flag{
+			SrcAccountFlag,
+			AssetFlag,
+			DstChainFlag,
+			AmountFlag,
+			LockIdFlag,
+			ConcurrencyFlag,
+			ProgressFlag,
+			BridgeURLFlag,
+			ApiKeyFlag,
+			TrackTimeoutFlag,
+		},
+	}
+
 	CmdMintFee = cli.Command{
 		Name:   "mintFee",
 		Usage:  "admin account mint fee token.",
@@ -0,0 +1,261 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/sdk/poly_sdk"
+	scm "github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	polyutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/urfave/cli"
+)
+
+const (
+	sideChainRegistered    = "registered"
+	sideChainPending       = "pending approval"
+	sideChainNotRegistered = "not registered"
+)
+
+// sideChainReport is what poly and the eccd of a side chain keep for the
+// cross chain of it.
+type sideChainReport struct {
+	ChainID uint64
+	Name    string
+	// Status is the registration in the side chain manager of poly, the
+	// fields of it are the pending ones before the approval.
+	Status       string
+	Owner        string `json:",omitempty"`
+	Router       uint64
+	CCMAddress   string `json:",omitempty"`
+	BlocksToWait uint64
+	// GenesisHeight and GenesisHash are the side chain genesis header synced
+	// to the header sync contract of poly, SyncedHeight is the latest header
+	// synced after it.
+	GenesisSynced bool
+	GenesisHeight uint64 `json:",omitempty"`
+	GenesisHash   string `json:",omitempty"`
+	SyncedHeight  uint64 `json:",omitempty"`
+	// EpochStartHeight and EpochKeepers are the poly epoch kept in the eccd,
+	// there are no keepers before the poly genesis header is synced.
+	ECCD             string
+	EpochStartHeight uint32
+	EpochKeepers     []string
+	// Problems are the differences to the chain config, Error is the query
+	// which failed.
+	Problems []string `json:",omitempty"`
+	Error    string   `json:",omitempty"`
+}
+
+type inspectReport struct {
+	PolyHeight uint64
+	SideChains []*sideChainReport
+}
+
+func handleCmdInspectSideChain(ctx *cli.Context) error {
+	polySdk := poly_sdk.NewPolySDK(cfg.Poly.RPC)
+	polyHeight, err := polySdk.GetCurrentBlockHeight()
+	if err != nil {
+		return fmt.Errorf("get poly height failed, err: %v", err)
+	}
+
+	chains := []*ChainConfig{cc}
+	if ctx.Bool(getFlagName(AllChainsFlag)) {
+		chains = []*ChainConfig{}
+		for _, c := range []*ChainConfig{cfg.Ethereum, cfg.Bsc, cfg.Heco} {
+			if c != nil {
+				chains = append(chains, c)
+			}
+		}
+	}
+
+	report := &inspectReport{PolyHeight: polyHeight}
+	failed := 0
+	for _, c := range chains {
+		r := inspectSideChain(polySdk, c)
+		if r.Error != "" {
+			failed++
+		}
+		report.SideChains = append(report.SideChains, r)
+	}
+
+	if ctx.Bool(getFlagName(JSONFlag)) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printInspectReport(report)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to inspect %d side chains", failed)
+	}
+	return nil
+}
+
+func inspectSideChain(polySdk *poly_sdk.PolySDK, c *ChainConfig) *sideChainReport {
+	r := &sideChainReport{
+		ChainID: c.SideChainID,
+		Name:    c.SideChainName,
+		Status:  sideChainNotRegistered,
+		ECCD:    c.ECCD,
+	}
+	if err := r.inspectPoly(polySdk, c); err != nil {
+		r.Error = fmt.Sprintf("poly: %v", err)
+		return r
+	}
+	if err := r.inspectECCD(c); err != nil {
+		r.Error = fmt.Sprintf("side chain: %v", err)
+	}
+	return r
+}
+
+func (r *sideChainReport) inspectPoly(polySdk *poly_sdk.PolySDK, c *ChainConfig) error {
+	sideChain, err := polySdk.GetSideChain(c.SideChainID)
+	if err != nil {
+		return err
+	}
+	if sideChain != nil {
+		r.Status = sideChainRegistered
+	} else if sideChain, err = polySdk.GetSideChainApply(c.SideChainID); err != nil {
+		return err
+	} else if sideChain != nil {
+		r.Status = sideChainPending
+	}
+	if sideChain == nil {
+		r.problem("side chain is not registered in poly")
+	} else {
+		r.fillSideChain(sideChain, c)
+	}
+
+	genesis, err := polySdk.GetGenesisHeader(c.SideChainID)
+	if err != nil {
+		return err
+	}
+	if genesis == nil {
+		r.problem("side chain genesis header is not synced to poly")
+		return nil
+	}
+	r.GenesisSynced = true
+	r.GenesisHeight = genesis.Number.Uint64()
+	r.GenesisHash = genesis.Hash.Hex()
+	if r.SyncedHeight, _, err = polySdk.GetSideChainHeight(c.SideChainID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *sideChainReport) fillSideChain(sideChain *scm.SideChain, c *ChainConfig) {
+	r.Owner = sideChain.Address.ToBase58()
+	r.Router = sideChain.Router
+	r.CCMAddress = common.BytesToAddress(sideChain.CCMCAddress).Hex()
+	r.BlocksToWait = sideChain.BlocksToWait
+	if sideChain.Name != c.SideChainName {
+		r.problem("name %s, config %s", sideChain.Name, c.SideChainName)
+	}
+	if router, ok := sideChainRouter(c.SideChainID); ok && router != sideChain.Router {
+		r.problem("router %d, expect %d", sideChain.Router, router)
+	}
+	// poly proves the cross chain txs of an eth like chain with the storage
+	// of the eccd
+	if common.BytesToAddress(sideChain.CCMCAddress) != common.HexToAddress(c.ECCD) {
+		r.problem("ccm address %s, config eccd %s", r.CCMAddress, c.ECCD)
+	}
+}
+
+func (r *sideChainReport) inspectECCD(c *ChainConfig) error {
+	if c.ECCD == "" {
+		r.problem("eccd is not deployed")
+		return nil
+	}
+	s, err := eth_sdk.NewEthereumSdk(c.RPC)
+	if err != nil {
+		return err
+	}
+	height, keepers, err := s.GetECCDEpoch(common.HexToAddress(c.ECCD))
+	if err != nil {
+		return err
+	}
+	r.EpochStartHeight = height
+	r.EpochKeepers = make([]string, 0, len(keepers))
+	for _, keeper := range keepers {
+		r.EpochKeepers = append(r.EpochKeepers, keeper.Hex())
+	}
+	if len(keepers) == 0 {
+		r.problem("poly genesis header is not synced to side chain")
+	}
+	return nil
+}
+
+func (r *sideChainReport) problem(format string, args ...interface{}) {
+	r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+}
+
+// sideChainRouter is the router the side chain registers with, see
+// handleCmdRegisterSideChain.
+func sideChainRouter(chainID uint64) (uint64, bool) {
+	switch chainID {
+	case basedef.ETHEREUM_CROSSCHAIN_ID:
+		return polyutils.ETH_ROUTER, true
+	case basedef.BSC_CROSSCHAIN_ID:
+		return polyutils.BSC_ROUTER, true
+	case basedef.HECO_CROSSCHAIN_ID:
+		return polyutils.HECO_ROUTER, true
+	}
+	return 0, false
+}
+
+func printInspectReport(report *inspectReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "poly height\t%d\n", report.PolyHeight)
+	for _, r := range report.SideChains {
+		fmt.Fprintf(w, "\nside chain %d\t%s\n", r.ChainID, r.Name)
+		if r.Error != "" {
+			fmt.Fprintf(w, "  error\t%s\n", r.Error)
+		}
+		fmt.Fprintf(w, "  registration\t%s\n", r.Status)
+		if r.Status != sideChainNotRegistered {
+			fmt.Fprintf(w, "  owner\t%s\n", r.Owner)
+			fmt.Fprintf(w, "  router\t%d\n", r.Router)
+			fmt.Fprintf(w, "  ccm address\t%s\n", r.CCMAddress)
+			fmt.Fprintf(w, "  blocks to wait\t%d\n", r.BlocksToWait)
+		}
+		if r.GenesisSynced {
+			fmt.Fprintf(w, "  genesis header\t%d %s\n", r.GenesisHeight, r.GenesisHash)
+			fmt.Fprintf(w, "  synced height\t%d\n", r.SyncedHeight)
+		} else {
+			fmt.Fprintf(w, "  genesis header\tnot synced\n")
+		}
+		fmt.Fprintf(w, "  eccd\t%s\n", r.ECCD)
+		fmt.Fprintf(w, "  epoch start height\t%d\n", r.EpochStartHeight)
+		for i, keeper := range r.EpochKeepers {
+			fmt.Fprintf(w, "  keeper %d\t%s\n", i, keeper)
+		}
+		for _, problem := range r.Problems {
+			fmt.Fprintf(w, "  problem\t%s\n", problem)
+		}
+	}
+	w.Flush()
+}
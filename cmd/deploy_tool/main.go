@@ -18,19 +18,25 @@
 package main
 
 import (
-	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
 	"runtime"
+	"strings"
 
 	log "github.com/astaxie/beego/logs"
 	"github.com/ethereum/go-ethereum/common"
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/go_abi/eccd_abi"
+	"github.com/polynetwork/poly-nft-bridge/go_abi/eccm_abi"
+	"github.com/polynetwork/poly-nft-bridge/go_abi/eccmp_abi"
+	erc20 "github.com/polynetwork/poly-nft-bridge/go_abi/mintable_erc20_abi"
+	nftlp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_lock_proxy_abi"
+	nftmapping "github.com/polynetwork/poly-nft-bridge/go_abi/nft_mapping_abi"
+	nftwrap "github.com/polynetwork/poly-nft-bridge/go_abi/nft_wrap_abi"
 	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
 	"github.com/polynetwork/poly-nft-bridge/sdk/poly_sdk"
-	xecdsa "github.com/polynetwork/poly-nft-bridge/utils/ecdsa"
 	"github.com/polynetwork/poly-nft-bridge/utils/files"
 	"github.com/polynetwork/poly-nft-bridge/utils/leveldb"
 	"github.com/polynetwork/poly-nft-bridge/utils/math"
@@ -46,10 +52,15 @@ var (
 	cc      *ChainConfig
 	storage *leveldb.LevelDBImpl
 	sdk     *eth_sdk.EthereumSdk
-	adm     *ecdsa.PrivateKey
+	adm     eth_sdk.Signer
 )
 
-const defaultAccPwd = "111111"
+const (
+	defaultAccPwd = "111111"
+	// envSignerPrefix marks an AdminSigner which is the environment variable
+	// holding the raw admin key, only for test networks.
+	envSignerPrefix = "env:"
+)
 
 func setupApp() *cli.App {
 	app := cli.NewApp()
@@ -73,9 +84,15 @@ func setupApp() *cli.App {
 		//NativeTokenFlag,
 		AmountFlag,
 		TokenIdFlag,
+		UnsignedFlag,
 	}
 	app.Commands = []cli.Command{
 		CmdSample,
+		CmdApply,
+		CmdBatch,
+		CmdSign,
+		CmdBroadcast,
+		CmdWallet,
 		CmdDeployECCDContract,
 		CmdDeployECCMContract,
 		CmdDeployCCMPContract,
@@ -93,12 +110,14 @@ func setupApp() *cli.App {
 		CmdApproveSideChain,
 		CmdSyncSideChainGenesis2Poly,
 		CmdSyncPolyGenesis2SideChain,
+		CmdInspectSideChain,
 		CmdNFTWrapSetFeeCollector,
 		CmdNFTWrapSetLockProxy,
 		CmdNFTMint,
 		CmdNFTApprove,
 		CmdNFTOwner,
 		CmdNFTWrapLock,
+		CmdNFTWrapLockBatch,
 		CmdMintFee,
 		CmdTransferFee,
 		CmdGetFeeBalance,
@@ -144,16 +163,41 @@ func beforeCommands(ctx *cli.Context) (err error) {
 
 	// prepare storage for persist account passphrase
 	storage = leveldb.NewLevelDBInstance(cfg.LevelDB)
+	setSession(cfg.Session)
 
 	// select src chainID and prepare config and accounts
 	chainID := ctx.GlobalUint64(getFlagName(ChainIDFlag))
 	selectChainConfig(chainID)
 
+	// sign works without network and loads the key of the transaction itself,
+	// wallet loads the keys of all the chains
+	command := ctx.Args().First()
+	if command == CmdSign.Name || command == CmdWallet.Name {
+		return nil
+	}
+
 	if sdk, err = eth_sdk.NewEthereumSdk(cc.RPC); err != nil {
 		return fmt.Errorf("generate sdk for chain %d faild, err: %v", cc.SideChainID, err)
 	}
+	if cc.Gas != nil {
+		if err = sdk.SetGasStrategy(cc.Gas.strategy()); err != nil {
+			return fmt.Errorf("set gas strategy for chain %d failed, err: %v", cc.SideChainID, err)
+		}
+	}
+
+	// the admin key never gets to the machine which builds or sends the
+	// transactions signed offline
+	if unsigned(ctx) {
+		if !unsignedCommands[command] {
+			return fmt.Errorf("command %s can not be written unsigned", command)
+		}
+		return nil
+	}
+	if command == CmdBroadcast.Name || command == CmdInspectSideChain.Name {
+		return nil
+	}
 
-	if adm, err = wallet.LoadEthAccount(storage, cc.Keystore, cc.Admin, defaultAccPwd); err != nil {
+	if adm, err = loadSigner(cc, cc.Admin); err != nil {
 		return fmt.Errorf("load eth account for chain %d faild, err: %v", cc.SideChainID, err)
 	}
 
@@ -171,14 +215,17 @@ func handleSample(ctx *cli.Context) error {
 
 func handleCmdDeployECCDContract(ctx *cli.Context) error {
 	log.Info("start to deploy eccd contract...")
+	if unsigned(ctx) {
+		return buildDeployTx(ctx, "ECCD", eccd_abi.EthCrossChainDataABI, eccd_abi.EthCrossChainDataBin)
+	}
 
-	addr, err := sdk.DeployECCDContract(adm)
+	addr, hash, err := sdk.DeployECCDContract(adm)
 	if err != nil {
 		return fmt.Errorf("deploy eccd for chain %d failed, err: %v", cc.SideChainID, err)
 	}
 
 	cc.ECCD = addr.Hex()
-	log.Info("deploy eccd for chain %d success %s", cc.SideChainID, addr.Hex())
+	log.Info("deploy eccd for chain %d success %s, txhash %s", cc.SideChainID, addr.Hex(), hash.Hex())
 	return updateConfig()
 }
 
@@ -186,12 +233,15 @@ func handleCmdDeployECCMContract(ctx *cli.Context) error {
 	log.Info("start to deploy eccm contract...")
 
 	eccd := common.HexToAddress(cc.ECCD)
-	addr, err := sdk.DeployECCMContract(adm, eccd, cc.SideChainID)
+	if unsigned(ctx) {
+		return buildDeployTx(ctx, "ECCM", eccm_abi.EthCrossChainManagerABI, eccm_abi.EthCrossChainManagerBin, eccd, cc.SideChainID)
+	}
+	addr, hash, err := sdk.DeployECCMContract(adm, eccd, cc.SideChainID)
 	if err != nil {
 		return fmt.Errorf("deploy eccm for chain %d failed, err: %v", cc.SideChainID, err)
 	}
 	cc.ECCM = addr.Hex()
-	log.Info("deploy eccm for chain %d success %s", cc.SideChainID, addr.Hex())
+	log.Info("deploy eccm for chain %d success %s, txhash %s", cc.SideChainID, addr.Hex(), hash.Hex())
 	return updateConfig()
 }
 
@@ -199,12 +249,15 @@ func handleCmdDeployCCMPContract(ctx *cli.Context) error {
 	log.Info("start to deploy ccmp contract...")
 
 	eccm := common.HexToAddress(cc.ECCM)
-	addr, err := sdk.DeployECCMPContract(adm, eccm)
+	if unsigned(ctx) {
+		return buildDeployTx(ctx, "CCMP", eccmp_abi.EthCrossChainManagerProxyABI, eccmp_abi.EthCrossChainManagerProxyBin, eccm)
+	}
+	addr, hash, err := sdk.DeployECCMPContract(adm, eccm)
 	if err != nil {
 		return fmt.Errorf("deploy ccmp for chain %d failed, err: %v", cc.SideChainID, err)
 	}
 	cc.CCMP = addr.Hex()
-	log.Info("deploy ccmp for chain %d success %s", cc.SideChainID, addr.Hex())
+	log.Info("deploy ccmp for chain %d success %s, txhash %s", cc.SideChainID, addr.Hex(), hash.Hex())
 	return updateConfig()
 }
 
@@ -213,8 +266,11 @@ func handleCmdDeployNFTContract(ctx *cli.Context) error {
 
 	name := flag2string(ctx, NFTNameFlag)
 	symbol := flag2string(ctx, NFTSymbolFlag)
-	owner := xecdsa.Key2address(adm)
 	proxy := common.HexToAddress(cc.NFTLockProxy)
+	if unsigned(ctx) {
+		return buildDeployTx(ctx, "", nftmapping.CrossChainNFTMappingABI, nftmapping.CrossChainNFTMappingBin, proxy, name, symbol)
+	}
+	owner := adm.Address()
 	if addr, err := sdk.DeployNFT(adm, proxy, name, symbol); err != nil {
 		return fmt.Errorf("deploy nft contract for owner %s on chain %d failed, err: %v", owner.Hex(), cc.SideChainID, err)
 	} else {
@@ -225,6 +281,9 @@ func handleCmdDeployNFTContract(ctx *cli.Context) error {
 
 func handleCmdDeployFeeContract(ctx *cli.Context) error {
 	log.Info("start to deploy erc20 token......")
+	if unsigned(ctx) {
+		return buildDeployTx(ctx, "FeeToken", erc20.ERC20MintableABI, erc20.ERC20MintableBin)
+	}
 
 	addr, err := sdk.DeployERC20(adm)
 	if err != nil {
@@ -238,26 +297,33 @@ func handleCmdDeployFeeContract(ctx *cli.Context) error {
 
 func handleCmdDeployLockProxyContract(ctx *cli.Context) error {
 	log.Info("start to deploy nft lock proxy contract...")
+	if unsigned(ctx) {
+		return buildDeployTx(ctx, "NFTLockProxy", nftlp.PolyNFTLockProxyABI, nftlp.PolyNFTLockProxyBin)
+	}
 
-	addr, err := sdk.DeployNFTLockProxy(adm)
+	addr, hash, err := sdk.DeployNFTLockProxy(adm)
 	if err != nil {
 		return fmt.Errorf("deploy nft lock proxy for chain %d failed, err: %v", cc.SideChainID, err)
 	}
 	cc.NFTLockProxy = addr.Hex()
-	log.Info("deploy nft lock proxy for chain %d success %s", cc.SideChainID, addr.Hex())
+	log.Info("deploy nft lock proxy for chain %d success %s, txhash %s", cc.SideChainID, addr.Hex(), hash.Hex())
 	return updateConfig()
 }
 
 func handleCmdDeployNFTWrapContract(ctx *cli.Context) error {
 	log.Info("start to deploy nft wrap contract...")
+	if unsigned(ctx) {
+		chainId := new(big.Int).SetUint64(cc.SideChainID)
+		return buildDeployTx(ctx, "NFTWrap", nftwrap.PolyNFTWrapperABI, nftwrap.PolyNFTWrapperBin, adminAddress(), chainId)
+	}
 
-	addr, err := sdk.DeployWrapContract(adm, cc.SideChainID)
+	addr, hash, err := sdk.DeployWrapContract(adm, cc.SideChainID)
 	if err != nil {
 		return err
 	}
 
 	cc.NFTWrap = addr.Hex()
-	log.Info("deploy wrap contract %s success!", addr.Hex())
+	log.Info("deploy wrap contract %s success! txhash %s", addr.Hex(), hash.Hex())
 	return updateConfig()
 }
 
@@ -266,6 +332,9 @@ func handleCmdLockProxySetCCMP(ctx *cli.Context) error {
 
 	proxy := common.HexToAddress(cc.NFTLockProxy)
 	ccmp := common.HexToAddress(cc.CCMP)
+	if unsigned(ctx) {
+		return buildCallTx(ctx, proxy, nftlp.PolyNFTLockProxyABI, "setManagerProxy", ccmp)
+	}
 	hash, err := sdk.NFTLockProxySetCCMP(adm, proxy, ccmp)
 	if err != nil {
 		return fmt.Errorf("nft lock proxy set ccmp for chain %d failed, err: %v", cc.SideChainID, err)
@@ -281,6 +350,9 @@ func handleCmdBindLockProxy(ctx *cli.Context) error {
 	dstChainCfg := customSelectChainConfig(dstChainId)
 	proxy := common.HexToAddress(cc.NFTLockProxy)
 	dstProxy := common.HexToAddress(dstChainCfg.NFTLockProxy)
+	if unsigned(ctx) {
+		return buildCallTx(ctx, proxy, nftlp.PolyNFTLockProxyABI, "bindProxyHash", dstChainId, dstProxy.Bytes())
+	}
 
 	hash, err := sdk.BindLockProxy(adm, proxy, dstProxy, dstChainId)
 	if err != nil {
@@ -315,8 +387,11 @@ func handleCmdBindNFTAsset(ctx *cli.Context) error {
 	dstAsset := flag2address(ctx, DstAssetFlag)
 	dstChainId := flag2Uint64(ctx, DstChainFlag)
 	dstChainCfg := customSelectChainConfig(dstChainId)
-	owner := xecdsa.Key2address(adm)
 	proxy := common.HexToAddress(cc.NFTLockProxy)
+	if unsigned(ctx) {
+		return buildCallTx(ctx, proxy, nftlp.PolyNFTLockProxyABI, "bindAssetHash", srcAsset, dstChainId, dstAsset.Bytes())
+	}
+	owner := adm.Address()
 
 	hash, err := sdk.BindNFTAsset(
 		adm,
@@ -348,6 +423,9 @@ func handleCmdTransferECCDOwnership(ctx *cli.Context) error {
 
 	eccd := common.HexToAddress(cc.ECCD)
 	eccm := common.HexToAddress(cc.ECCM)
+	if unsigned(ctx) {
+		return buildCallTx(ctx, eccd, eccd_abi.EthCrossChainDataABI, "transferOwnership", eccm)
+	}
 
 	if hash, err := sdk.TransferECCDOwnership(adm, eccd, eccm); err != nil {
 		return fmt.Errorf("transfer eccd %s ownership to eccm %s on chain %d failed, err: %v",
@@ -364,6 +442,9 @@ func handleCmdTransferECCMOwnership(ctx *cli.Context) error {
 
 	eccm := common.HexToAddress(cc.ECCM)
 	ccmp := common.HexToAddress(cc.CCMP)
+	if unsigned(ctx) {
+		return buildCallTx(ctx, eccm, eccm_abi.EthCrossChainManagerABI, "transferOwnership", ccmp)
+	}
 
 	if hash, err := sdk.TransferECCMOwnership(adm, eccm, ccmp); err != nil {
 		return fmt.Errorf("transfer eccm %s ownership to ccmp %s on chain %d failed, err: %v",
@@ -377,7 +458,7 @@ func handleCmdTransferECCMOwnership(ctx *cli.Context) error {
 }
 
 func handleCmdRegisterSideChain(ctx *cli.Context) error {
-	validators, err := wallet.LoadPolyAccountList(cfg.Poly.Keystore, cfg.Poly.Passphrase)
+	validators, err := wallet.LoadPolyAccountList(storage, cfg.Poly.Keystore, cfg.Poly.Passphrase)
 	if err != nil {
 		return err
 	}
@@ -419,7 +500,7 @@ func handleCmdRegisterSideChain(ctx *cli.Context) error {
 }
 
 func handleCmdApproveSideChain(ctx *cli.Context) error {
-	validators, err := wallet.LoadPolyAccountList(cfg.Poly.Keystore, cfg.Poly.Passphrase)
+	validators, err := wallet.LoadPolyAccountList(storage, cfg.Poly.Keystore, cfg.Poly.Passphrase)
 	if err != nil {
 		return err
 	}
@@ -443,7 +524,7 @@ func handleCmdSyncSideChainGenesis2Poly(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	validators, err := wallet.LoadPolyAccountList(cfg.Poly.Keystore, cfg.Poly.Passphrase)
+	validators, err := wallet.LoadPolyAccountList(storage, cfg.Poly.Keystore, cfg.Poly.Passphrase)
 	if err != nil {
 		return err
 	}
@@ -474,6 +555,13 @@ func handleCmdSyncPolyGenesis2SideChain(ctx *cli.Context) error {
 		return err
 	}
 	eccm := common.HexToAddress(cc.ECCM)
+	if unsigned(ctx) {
+		headerEnc, bookeepersEnc, err := PolyGenesisHeader(polySdk)
+		if err != nil {
+			return err
+		}
+		return buildCallTx(ctx, eccm, eccm_abi.EthCrossChainManagerABI, "initGenesisBlock", headerEnc, bookeepersEnc)
+	}
 
 	if err := SyncPolyGenesisHeader2Eth(
 		polySdk,
@@ -492,6 +580,9 @@ func handleCmdNFTWrapSetFeeCollector(ctx *cli.Context) error {
 
 	wrapper := common.HexToAddress(cc.NFTWrap)
 	feeCollector := common.HexToAddress(cc.FeeCollector)
+	if unsigned(ctx) {
+		return buildCallTx(ctx, wrapper, nftwrap.PolyNFTWrapperABI, "setFeeCollector", feeCollector)
+	}
 
 	if tx, err := sdk.SetWrapFeeCollector(adm, wrapper, feeCollector); err != nil {
 		return fmt.Errorf("set fee collector failed, err: %v", err)
@@ -506,6 +597,9 @@ func handleCmdNFTWrapSetLockProxy(ctx *cli.Context) error {
 
 	wrapper := common.HexToAddress(cc.NFTWrap)
 	proxy := common.HexToAddress(cc.NFTLockProxy)
+	if unsigned(ctx) {
+		return buildCallTx(ctx, wrapper, nftwrap.PolyNFTWrapperABI, "setLockProxy", proxy)
+	}
 
 	if tx, err := sdk.SetWrapLockProxy(adm, wrapper, proxy); err != nil {
 		return fmt.Errorf("set lock proxy for wrap failed, err: %v", err)
@@ -522,6 +616,9 @@ func handleCmdNFTMint(ctx *cli.Context) error {
 	to := flag2address(ctx, DstAccountFlag)
 	tokenID := flag2big(ctx, TokenIdFlag)
 	uri := cfg.OSS + tokenID.String()
+	if unsigned(ctx) {
+		return buildCallTx(ctx, asset, nftmapping.CrossChainNFTMappingABI, "mintWithURI", to, tokenID, uri)
+	}
 	tx, err := sdk.MintNFT(adm, asset, to, tokenID, uri)
 	if err != nil {
 		return err
@@ -536,7 +633,7 @@ func handleCmdNFTApprove(ctx *cli.Context) error {
 	asset := flag2address(ctx, AssetFlag)
 	tokenID := flag2big(ctx, TokenIdFlag)
 	owner := flag2address(ctx, SrcAccountFlag)
-	key, err := wallet.LoadEthAccount(storage, cc.Keystore, owner.Hex(), defaultAccPwd)
+	key, err := loadSigner(cc, owner.Hex())
 	if err != nil {
 		return err
 	}
@@ -579,7 +676,7 @@ func handleCmdNFTWrapLock(ctx *cli.Context) error {
 	log.Info("start to lock nft...")
 
 	from := flag2address(ctx, SrcAccountFlag)
-	key, err := wallet.LoadEthAccount(storage, cc.Keystore, from.Hex(), defaultAccPwd)
+	key, err := loadSigner(cc, from.Hex())
 	if err != nil {
 		return err
 	}
@@ -649,6 +746,9 @@ func handleCmdMintFee(ctx *cli.Context) error {
 	to := flag2address(ctx, DstAccountFlag)
 	amount := flag2big(ctx, AmountFlag)
 	log.Debug("mint to %s %s", to.Hex(), amount.String())
+	if unsigned(ctx) {
+		return buildCallTx(ctx, asset, erc20.ERC20MintableABI, "mint", to, amount)
+	}
 
 	tx, err := sdk.MintERC20Token(adm, asset, to, amount)
 	if err != nil {
@@ -663,7 +763,7 @@ func handleCmdTransferFee(ctx *cli.Context) error {
 
 	asset := common.HexToAddress(cc.FeeToken) //getFeeTokenOrERC20Asset(ctx)
 	from := flag2address(ctx, SrcAccountFlag)
-	key, err := wallet.LoadEthAccount(storage, cc.Keystore, from.Hex(), defaultAccPwd)
+	key, err := loadSigner(cc, from.Hex())
 	if err != nil {
 		return err
 	}
@@ -704,7 +804,7 @@ func handleGetNativeBalance(ctx *cli.Context) error {
 func handleCmdApprove(ctx *cli.Context) error {
 	asset := common.HexToAddress(cc.FeeToken) //getFeeTokenOrERC20Asset(ctx)
 	sender := flag2address(ctx, SrcAccountFlag)
-	key, err := wallet.LoadEthAccount(storage, cc.Keystore, sender.Hex(), defaultAccPwd)
+	key, err := loadSigner(cc, sender.Hex())
 	if err != nil {
 		return err
 	}
@@ -736,7 +836,7 @@ func handleCmdNativeTransfer(ctx *cli.Context) error {
 	log.Info("start to transfer native token on chain %s...", cc.SideChainName)
 
 	from := flag2address(ctx, SrcAccountFlag)
-	key, err := wallet.LoadEthAccount(storage, cc.Keystore, from.Hex(), defaultAccPwd)
+	key, err := loadSigner(cc, from.Hex())
 	if err != nil {
 		return err
 	}
@@ -806,6 +906,22 @@ func updateConfig() error {
 	return nil
 }
 
+// loadSigner loads the signer of the account from the keystore, the admin
+// signs with the AdminSigner of the chain config when it is set.
+func loadSigner(c *ChainConfig, account string) (eth_sdk.Signer, error) {
+	if c.AdminSigner == "" || common.HexToAddress(account) != common.HexToAddress(c.Admin) {
+		key, err := wallet.LoadEthAccount(storage, c.Keystore, account, defaultAccPwd)
+		if err != nil {
+			return nil, err
+		}
+		return eth_sdk.NewKeySigner(key), nil
+	}
+	if strings.HasPrefix(c.AdminSigner, envSignerPrefix) {
+		return eth_sdk.NewEnvSigner(strings.TrimPrefix(c.AdminSigner, envSignerPrefix))
+	}
+	return eth_sdk.NewRemoteSigner(c.AdminSigner, common.HexToAddress(c.Admin))
+}
+
 func selectChainConfig(chainID uint64) {
 	cc = customSelectChainConfig(chainID)
 }
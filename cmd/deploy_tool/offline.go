@@ -0,0 +1,222 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+
+	log "github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/utils/files"
+	"github.com/urfave/cli"
+)
+
+// OfflineTx is written by an admin command run with --unsigned, filled with
+// the signature by sign on the air-gapped machine and sent by broadcast.
+type OfflineTx struct {
+	Command string
+	ChainId uint64
+	// Config is the field of the chain config set to the created contract
+	// once the transaction is confirmed.
+	Config string `json:",omitempty"`
+	Tx     *eth_sdk.UnsignedTx
+	Signed string `json:",omitempty"`
+	Hash   string `json:",omitempty"`
+}
+
+// unsignedCommands are the admin commands which can be written by --unsigned,
+// the others refuse the flag rather than send with another key.
+var unsignedCommands = map[string]bool{
+	CmdDeployECCDContract.Name:        true,
+	CmdDeployECCMContract.Name:        true,
+	CmdDeployCCMPContract.Name:        true,
+	CmdDeployNFTContract.Name:         true,
+	CmdDeployFeeContract.Name:         true,
+	CmdDeployLockProxyContract.Name:   true,
+	CmdDeployNFTWrapContract.Name:     true,
+	CmdLockProxySetCCMP.Name:          true,
+	CmdBindLockProxy.Name:             true,
+	CmdBindNFTAsset.Name:              true,
+	CmdTransferECCDOwnership.Name:     true,
+	CmdTransferECCMOwnership.Name:     true,
+	CmdSyncPolyGenesis2SideChain.Name: true,
+	CmdNFTWrapSetFeeCollector.Name:    true,
+	CmdNFTWrapSetLockProxy.Name:       true,
+	CmdNFTMint.Name:                   true,
+	CmdMintFee.Name:                   true,
+}
+
+func unsigned(ctx *cli.Context) bool {
+	return ctx.GlobalString(getFlagName(UnsignedFlag)) != ""
+}
+
+// adminAddress is the sender of the admin commands, the key is not loaded
+// when they are written unsigned.
+func adminAddress() common.Address {
+	if adm != nil {
+		return adm.Address()
+	}
+	return common.HexToAddress(cc.Admin)
+}
+
+func buildDeployTx(ctx *cli.Context, config, contractAbi, bin string, params ...interface{}) error {
+	tx, err := sdk.BuildDeployTx(adminAddress(), contractAbi, bin, params...)
+	if err != nil {
+		return err
+	}
+	return writeUnsignedTx(ctx, config, tx)
+}
+
+func buildCallTx(ctx *cli.Context, to common.Address, contractAbi, method string, params ...interface{}) error {
+	tx, err := sdk.BuildCallTx(adminAddress(), to, contractAbi, method, params...)
+	if err != nil {
+		return err
+	}
+	return writeUnsignedTx(ctx, "", tx)
+}
+
+func writeUnsignedTx(ctx *cli.Context, config string, tx *eth_sdk.UnsignedTx) error {
+	path := ctx.GlobalString(getFlagName(UnsignedFlag))
+	otx := &OfflineTx{
+		Command: ctx.Command.Name,
+		ChainId: cc.SideChainID,
+		Config:  config,
+		Tx:      tx,
+	}
+	if err := files.WriteJsonFile(path, otx, true); err != nil {
+		return err
+	}
+	if contract := tx.Contract(); contract != eth_sdk.EmptyAddress {
+		log.Info("%s on chain %d written unsigned to %s, nonce %d, contract %s",
+			otx.Command, otx.ChainId, path, tx.Nonce, contract.Hex())
+	} else {
+		log.Info("%s on chain %d written unsigned to %s, nonce %d", otx.Command, otx.ChainId, path, tx.Nonce)
+	}
+	return nil
+}
+
+func handleCmdSign(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("usage: sign <unsigned.json> <signed.json>")
+	}
+	otx := new(OfflineTx)
+	if err := files.ReadJsonFile(ctx.Args().Get(0), otx); err != nil {
+		return fmt.Errorf("read unsigned transaction, err: %v", err)
+	}
+	if otx.Tx == nil {
+		return fmt.Errorf("no transaction in %s", ctx.Args().Get(0))
+	}
+	if otx.Signed != "" {
+		return fmt.Errorf("transaction %s already signed", otx.Hash)
+	}
+
+	chainCfg := customSelectChainConfig(otx.ChainId)
+	if common.HexToAddress(otx.Tx.From) != common.HexToAddress(chainCfg.Admin) {
+		return fmt.Errorf("transaction sender %s is not the admin %s of chain %d", otx.Tx.From, chainCfg.Admin, otx.ChainId)
+	}
+	signer, err := loadSigner(chainCfg, chainCfg.Admin)
+	if err != nil {
+		return err
+	}
+	log.Info("sign %s on chain %d, to %s, nonce %d, gas %d, gas price %s, data %d bytes",
+		otx.Command, otx.ChainId, otx.Tx.To, otx.Tx.Nonce, otx.Tx.Gas, otx.Tx.GasPrice, len(common.FromHex(otx.Tx.Data)))
+
+	tx, err := eth_sdk.SignTx(otx.Tx, signer)
+	if err != nil {
+		return err
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	otx.Signed = hexutil.Encode(raw)
+	otx.Hash = tx.Hash().Hex()
+	if err := files.WriteJsonFile(ctx.Args().Get(1), otx, true); err != nil {
+		return err
+	}
+	log.Info("transaction %s signed to %s", otx.Hash, ctx.Args().Get(1))
+	return nil
+}
+
+func handleCmdBroadcast(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("usage: broadcast <signed.json>")
+	}
+	otx := new(OfflineTx)
+	if err := files.ReadJsonFile(ctx.Args().First(), otx); err != nil {
+		return fmt.Errorf("read signed transaction, err: %v", err)
+	}
+	if otx.Signed == "" {
+		return fmt.Errorf("transaction in %s is not signed", ctx.Args().First())
+	}
+	if otx.ChainId != cc.SideChainID {
+		return fmt.Errorf("transaction is for chain %d, run with --%s %d", otx.ChainId, getFlagName(ChainIDFlag), otx.ChainId)
+	}
+
+	raw, err := hexutil.Decode(otx.Signed)
+	if err != nil {
+		return err
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(raw, tx); err != nil {
+		return err
+	}
+	if tx.Hash().Hex() != otx.Hash {
+		return fmt.Errorf("signed transaction %s does not match hash %s", tx.Hash().Hex(), otx.Hash)
+	}
+	if err := sdk.SendSignedTx(tx); err != nil {
+		return fmt.Errorf("broadcast %s on chain %d failed, err: %v", otx.Command, otx.ChainId, err)
+	}
+	log.Info("broadcast %s on chain %d success, txhash %s", otx.Command, otx.ChainId, otx.Hash)
+
+	if otx.Config == "" {
+		return nil
+	}
+	contract := otx.Tx.Contract().Hex()
+	if err := setConfigContract(cc, otx.Config, contract); err != nil {
+		return err
+	}
+	log.Info("%s of chain %d is %s", otx.Config, otx.ChainId, contract)
+	return updateConfig()
+}
+
+// setConfigContract sets the field of the chain config written by a deploy
+// command.
+func setConfigContract(c *ChainConfig, field, contract string) error {
+	switch field {
+	case "ECCD":
+		c.ECCD = contract
+	case "ECCM":
+		c.ECCM = contract
+	case "CCMP":
+		c.CCMP = contract
+	case "NFTLockProxy":
+		c.NFTLockProxy = contract
+	case "NFTWrap":
+		c.NFTWrap = contract
+	case "FeeToken":
+		c.FeeToken = contract
+	default:
+		return fmt.Errorf("invalid config field %s", field)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/astaxie/beego/logs"
+	"github.com/polynetwork/poly-nft-bridge/utils/wallet"
+	"github.com/urfave/cli"
+)
+
+// setSession encrypts the password sessions with the keyring file of the
+// config, or a key of this run only when there is none.
+func setSession(c *SessionConfig) {
+	if c == nil {
+		wallet.SetSession(wallet.NewMemoryKeyring(), wallet.DefaultSessionTTL)
+		return
+	}
+	keyring := wallet.NewMemoryKeyring()
+	if c.Keyring != "" {
+		keyring = wallet.NewFileKeyring(c.Keyring)
+	}
+	ttl := wallet.DefaultSessionTTL
+	if c.TTL != 0 {
+		ttl = time.Duration(c.TTL) * time.Second
+	}
+	wallet.SetSession(keyring, ttl)
+}
+
+func handleCmdWalletUnlock(ctx *cli.Context) error {
+	if cfg.Session == nil || cfg.Session.Keyring == "" {
+		return fmt.Errorf("sessions only last for one run, set Session.Keyring in config to unlock the wallet")
+	}
+	if wallet.SessionTTL() <= 0 {
+		return fmt.Errorf("sessions are disabled by Session.TTL")
+	}
+
+	for _, c := range []*ChainConfig{cfg.Ethereum, cfg.Bsc, cfg.Heco} {
+		if c == nil || c.Admin == "" || c.AdminSigner != "" {
+			continue
+		}
+		if _, err := loadSigner(c, c.Admin); err != nil {
+			return fmt.Errorf("unlock admin %s of chain %d failed, err: %v", c.Admin, c.SideChainID, err)
+		}
+		log.Info("admin %s of chain %d unlocked", c.Admin, c.SideChainID)
+	}
+	if cfg.Poly != nil && cfg.Poly.Keystore != "" {
+		validators, err := wallet.LoadPolyAccountList(storage, cfg.Poly.Keystore, cfg.Poly.Passphrase)
+		if err != nil {
+			return fmt.Errorf("unlock poly validators failed, err: %v", err)
+		}
+		log.Info("%d poly validators unlocked", len(validators))
+	}
+
+	log.Info("wallet unlocked for %s", wallet.SessionTTL())
+	return nil
+}
+
+func handleCmdWalletLock(ctx *cli.Context) error {
+	if err := wallet.Lock(storage); err != nil {
+		return err
+	}
+	log.Info("wallet locked")
+	return nil
+}
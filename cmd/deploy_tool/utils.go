@@ -18,7 +18,6 @@
 package main
 
 import (
-	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -175,28 +174,18 @@ func SyncHecoGenesisHeader2Poly(
 
 func SyncPolyGenesisHeader2Eth(
 	polySDK *xpolysdk.PolySDK,
-	sideChainECCMOwnerKey *ecdsa.PrivateKey,
+	sideChainECCMOwner eth_sdk.Signer,
 	sideChainSdk *eth_sdk.EthereumSdk,
 	sideChainECCM common.Address,
 ) error {
 
-	// `epoch` related with the poly validators changing,
-	// we can set it as 0 if poly validators never changed on develop environment.
-	var RCEpoch uint64 = 0
-	gB, err := polySDK.GetBlockByHeight(RCEpoch)
+	headerEnc, bookeepersEnc, err := PolyGenesisHeader(polySDK)
 	if err != nil {
 		return err
 	}
 
-	bookeepers, err := xpolysdk.GetBookeeper(gB)
-	if err != nil {
-		return err
-	}
-	bookeepersEnc := xpolysdk.AssembleNoCompressBookeeper(bookeepers)
-	headerEnc := gB.Header.ToArray()
-
 	if _, err := sideChainSdk.InitGenesisBlock(
-		sideChainECCMOwnerKey,
+		sideChainECCMOwner,
 		sideChainECCM,
 		headerEnc,
 		bookeepersEnc,
@@ -206,3 +195,21 @@ func SyncPolyGenesisHeader2Eth(
 
 	return nil
 }
+
+// PolyGenesisHeader returns the poly header and the bookeepers the eccm is
+// initialized with.
+func PolyGenesisHeader(polySDK *xpolysdk.PolySDK) (headerEnc, bookeepersEnc []byte, err error) {
+	// `epoch` related with the poly validators changing,
+	// we can set it as 0 if poly validators never changed on develop environment.
+	var RCEpoch uint64 = 0
+	gB, err := polySDK.GetBlockByHeight(RCEpoch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bookeepers, err := xpolysdk.GetBookeeper(gB)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gB.Header.ToArray(), xpolysdk.AssembleNoCompressBookeeper(bookeepers), nil
+}
@@ -0,0 +1,527 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/sdk/bridge_sdk"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/utils/files"
+	"github.com/urfave/cli"
+)
+
+// states of the tokens in the progress file of lockNFTBatch
+const (
+	lockPending  = "pending"
+	lockSent     = "sent"
+	lockFinished = "finished"
+	lockFailed   = "failed"
+)
+
+// lockTrackInterval is how often the sent locks are looked up on the bridge.
+var lockTrackInterval = 15 * time.Second
+
+type lockRecord struct {
+	TokenId string
+	To      string
+	State   string
+	Hash    string `json:",omitempty"`
+	Error   string `json:",omitempty"`
+}
+
+// lockProgress is written after every change, running the same csv again
+// resumes it: finished tokens are skipped, sent ones are only tracked and
+// failed ones are sent again unless the lock proxy holds them already.
+type lockProgress struct {
+	SrcChainId uint64
+	DstChainId uint64
+	Asset      string
+	From       string
+	Tokens     []*lockRecord
+
+	path string
+	mu   sync.Mutex
+}
+
+func handleCmdNFTWrapLockBatch(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("usage: lockNFTBatch <tokens.csv>")
+	}
+	csvPath := ctx.Args().First()
+	rows, err := readLockBatch(csvPath)
+	if err != nil {
+		return err
+	}
+
+	from := flag2address(ctx, SrcAccountFlag)
+	asset := flag2address(ctx, AssetFlag)
+	dstChainID := flag2Uint64(ctx, DstChainFlag)
+	fee := flag2big(ctx, AmountFlag)
+	if fee == nil {
+		return fmt.Errorf("invalid fee amount %s", flag2string(ctx, AmountFlag))
+	}
+	id := new(big.Int).SetUint64(flag2Uint64(ctx, LockIdFlag))
+	wrapper := common.HexToAddress(cc.NFTWrap)
+	concurrency := int(ctx.Uint(getFlagName(ConcurrencyFlag)))
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	progressPath := flag2string(ctx, ProgressFlag)
+	if progressPath == "" {
+		progressPath = csvPath + ".progress.json"
+	}
+	progress, err := loadLockProgress(progressPath, cc.SideChainID, dstChainID, asset, from)
+	if err != nil {
+		return err
+	}
+	if err := progress.merge(rows); err != nil {
+		return err
+	}
+
+	key, err := loadSigner(cc, from.Hex())
+	if err != nil {
+		return err
+	}
+
+	todo := progress.filter(lockPending, lockFailed)
+	log.Info("lock %d nfts of %s from %s to chain %d, %d to send, progress %s",
+		len(progress.Tokens), asset.Hex(), from.Hex(), dstChainID, len(todo), progressPath)
+	if len(todo) > 0 {
+		if todo, err = checkLockBatch(progress, key, asset, wrapper, fee, todo, concurrency); err != nil {
+			return err
+		}
+	}
+	if len(todo) > 0 {
+		sendLockBatch(progress, key, asset, wrapper, dstChainID, fee, id, todo, concurrency)
+	}
+
+	if url := flag2string(ctx, BridgeURLFlag); url != "" {
+		bridge := bridge_sdk.NewBridgeSdk(url)
+		bridge.SetApiKey(flag2string(ctx, ApiKeyFlag))
+		trackLockBatch(progress, bridge, ctx.Duration(getFlagName(TrackTimeoutFlag)), concurrency)
+	} else {
+		log.Warn("bridge url not set, the sent transactions are not tracked")
+	}
+
+	counts := make(map[string]int)
+	for _, record := range progress.Tokens {
+		counts[record.State]++
+	}
+	log.Info("lock nft batch, finished %d, sent %d, failed %d, progress %s",
+		counts[lockFinished], counts[lockSent], counts[lockFailed], progressPath)
+	if counts[lockFailed] > 0 || counts[lockSent] > 0 {
+		return fmt.Errorf("%d of %d tokens not finished, run again to resume",
+			counts[lockFailed]+counts[lockSent], len(progress.Tokens))
+	}
+	return nil
+}
+
+// checkLockBatch makes sure every token can be locked before anything is
+// sent, the wrapper is approved for all the tokens of the owner at once. A
+// token held by the lock proxy was locked by a run which did not see the
+// confirmation, it is counted as sent and left out of the returned tokens.
+func checkLockBatch(
+	progress *lockProgress,
+	key eth_sdk.Signer,
+	asset, wrapper common.Address,
+	fee *big.Int,
+	todo []*lockRecord,
+	concurrency int,
+) ([]*lockRecord, error) {
+	from := key.Address()
+	approvedForAll, err := sdk.IsNFTApprovedForAll(asset, from, wrapper)
+	if err != nil {
+		return nil, err
+	}
+	lockProxy, err := sdk.GetWrapLockProxy(wrapper)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu         sync.Mutex
+		problems   []string
+		unapproved int
+		locked     = make(map[*lockRecord]bool)
+	)
+	runLockBatch(len(todo), concurrency, func(i int) {
+		tokenID, _ := new(big.Int).SetString(todo[i].TokenId, 10)
+		problem := ""
+		owner, err := sdk.GetNFTOwner(asset, tokenID)
+		if err != nil {
+			problem = fmt.Sprintf("token %s: %v", todo[i].TokenId, err)
+		} else if owner == lockProxy {
+			mu.Lock()
+			locked[todo[i]] = true
+			mu.Unlock()
+			return
+		} else if owner != from {
+			problem = fmt.Sprintf("token %s is owned by %s", todo[i].TokenId, owner.Hex())
+		}
+		approved := approvedForAll
+		if problem == "" && !approved {
+			to, err := sdk.GetNFTApproved(asset, tokenID)
+			if err != nil {
+				problem = fmt.Sprintf("token %s: %v", todo[i].TokenId, err)
+			}
+			approved = to == wrapper
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if problem != "" {
+			problems = append(problems, problem)
+		} else if !approved {
+			unapproved++
+		}
+	})
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			log.Error(problem)
+		}
+		return nil, fmt.Errorf("%d of %d tokens can not be locked by %s", len(problems), len(todo), from.Hex())
+	}
+
+	rest := make([]*lockRecord, 0, len(todo))
+	for _, record := range todo {
+		if !locked[record] {
+			rest = append(rest, record)
+			continue
+		}
+		if record.Hash == "" {
+			log.Warn("token %s is held by lock proxy %s, counted as sent but the lock transaction is unknown", record.TokenId, lockProxy.Hex())
+		} else {
+			log.Info("token %s is held by lock proxy %s, counted as sent, txhash %s", record.TokenId, lockProxy.Hex(), record.Hash)
+		}
+		progress.update(record, lockSent, record.Hash, "")
+	}
+	todo = rest
+	if len(todo) == 0 {
+		return todo, nil
+	}
+
+	if unapproved > 0 {
+		log.Info("%d tokens not approved, approve all the tokens of %s to wrapper %s", unapproved, from.Hex(), wrapper.Hex())
+		if _, err := sdk.NFTSetApprovalForAll(key, asset, wrapper, true); err != nil {
+			return nil, err
+		}
+		if ok, err := sdk.IsNFTApprovedForAll(asset, from, wrapper); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, fmt.Errorf("approve all the tokens to wrapper %s failed", wrapper.Hex())
+		}
+	}
+
+	total := new(big.Int).Mul(fee, big.NewInt(int64(len(todo))))
+	if cc.FeeToken == "" {
+		balance, err := sdk.GetNativeBalance(from)
+		if err != nil {
+			return nil, err
+		}
+		if balance.Cmp(total) < 0 {
+			return nil, fmt.Errorf("native balance %s less than the fee %s of %d tokens", balance.String(), total.String(), len(todo))
+		}
+		return todo, nil
+	}
+
+	feeToken := common.HexToAddress(cc.FeeToken)
+	balance, err := sdk.GetERC20Balance(feeToken, from)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(total) < 0 {
+		return nil, fmt.Errorf("fee token balance %s less than the fee %s of %d tokens", balance.String(), total.String(), len(todo))
+	}
+	allowance, err := sdk.GetERC20Allowance(feeToken, from, wrapper)
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(total) >= 0 {
+		return todo, nil
+	}
+	log.Info("approve fee %s of %d tokens to wrapper %s", total.String(), len(todo), wrapper.Hex())
+	if _, err := sdk.ApproveERC20Token(key, feeToken, wrapper, total); err != nil {
+		return nil, err
+	}
+	if allowance, err = sdk.GetERC20Allowance(feeToken, from, wrapper); err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(total) < 0 {
+		return nil, fmt.Errorf("approve fee token to wrapper contract failed")
+	}
+	return todo, nil
+}
+
+// sendLockBatch sends the locks concurrently, the nonce manager of the sdk
+// orders the transactions of the owner.
+func sendLockBatch(
+	progress *lockProgress,
+	key eth_sdk.Signer,
+	asset, wrapper common.Address,
+	dstChainID uint64,
+	fee, id *big.Int,
+	todo []*lockRecord,
+	concurrency int,
+) {
+	feeToken := common.HexToAddress(cc.FeeToken)
+	runLockBatch(len(todo), concurrency, func(i int) {
+		record := todo[i]
+		tokenID, _ := new(big.Int).SetString(record.TokenId, 10)
+		to := common.HexToAddress(record.To)
+
+		var (
+			tx  *types.Transaction
+			err error
+		)
+		if cc.FeeToken == "" {
+			tx, err = sdk.SendWrapLockWithNativeFeeToken(key, wrapper, asset, to, dstChainID, tokenID, fee, id)
+		} else {
+			tx, err = sdk.SendWrapLockWithErc20FeeToken(key, wrapper, asset, to, dstChainID, tokenID, feeToken, fee, id)
+		}
+		if err != nil {
+			log.Error("lock nft %s to %s failed, err: %v", record.TokenId, record.To, err)
+			progress.update(record, lockFailed, "", err.Error())
+			return
+		}
+		// saved before the confirmation, the lock is still tracked when the
+		// tool is killed while waiting
+		progress.update(record, lockSent, tx.Hash().Hex(), "")
+
+		hash, err := sdk.WaitTxConfirm(tx)
+		if err != nil {
+			log.Error("lock nft %s to %s not confirmed, txhash %s, err: %v", record.TokenId, record.To, hash.Hex(), err)
+			progress.update(record, lockFailed, hash.Hex(), err.Error())
+			return
+		}
+		log.Info("lock nft %s to %s success, txhash %s", record.TokenId, record.To, hash.Hex())
+		progress.update(record, lockSent, hash.Hex(), "")
+	})
+}
+
+// trackLockBatch follows the sent locks on the bridge until they are
+// finished on the destination chain or the timeout.
+func trackLockBatch(progress *lockProgress, bridge *bridge_sdk.BridgeSdk, timeout time.Duration, concurrency int) {
+	deadline := time.Now().Add(timeout)
+	for {
+		sent := make([]*lockRecord, 0)
+		for _, record := range progress.filter(lockSent) {
+			if record.Hash != "" {
+				sent = append(sent, record)
+			}
+		}
+		if len(sent) == 0 {
+			return
+		}
+		var (
+			mu      sync.Mutex
+			limited bool
+			wait    = lockTrackInterval
+		)
+		runLockBatch(len(sent), concurrency, func(i int) {
+			mu.Lock()
+			skip := limited
+			mu.Unlock()
+			if skip {
+				return
+			}
+			record := sent[i]
+			tx, err := bridge.TransactionOfHash(record.Hash)
+			if limit, ok := err.(*bridge_sdk.RateLimitError); ok {
+				// the rest of the round would be limited too
+				mu.Lock()
+				limited = true
+				if limit.RetryAfter > wait {
+					wait = limit.RetryAfter
+				}
+				mu.Unlock()
+				return
+			}
+			if err != nil {
+				log.Warn("get transaction %s of nft %s from bridge err: %v", record.Hash, record.TokenId, err)
+				return
+			}
+			if tx != nil && tx.State == basedef.STATE_FINISHED {
+				log.Info("nft %s to %s finished, txhash %s", record.TokenId, record.To, record.Hash)
+				progress.update(record, lockFinished, record.Hash, "")
+			}
+		})
+		if time.Now().After(deadline) {
+			log.Warn("%d locks not finished in %s", len(progress.filter(lockSent)), timeout.String())
+			return
+		}
+		if limited {
+			log.Warn("bridge rate limited the tracking, retry after %s", wait.String())
+		}
+		time.Sleep(wait)
+	}
+}
+
+func runLockBatch(n, concurrency int, job func(i int)) {
+	var (
+		wg   sync.WaitGroup
+		jobs = make(chan int)
+	)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				job(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// readLockBatch parses `<tokenId>,<to>` lines, lines starting with `#` are
+// comments.
+func readLockBatch(path string) ([]*lockRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*lockRecord, 0, len(records))
+	for i, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("record %d: usage: <tokenId>,<to>", i+1)
+		}
+		tokenID, ok := new(big.Int).SetString(strings.TrimSpace(record[0]), 10)
+		if !ok {
+			return nil, fmt.Errorf("record %d: invalid token id %s", i+1, record[0])
+		}
+		to, err := batchAddress(strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %v", i+1, err)
+		}
+		rows = append(rows, &lockRecord{TokenId: tokenID.String(), To: to.Hex(), State: lockPending})
+	}
+	return rows, nil
+}
+
+func loadLockProgress(path string, srcChainID, dstChainID uint64, asset, from common.Address) (*lockProgress, error) {
+	progress := &lockProgress{
+		SrcChainId: srcChainID,
+		DstChainId: dstChainID,
+		Asset:      asset.Hex(),
+		From:       from.Hex(),
+		path:       path,
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return progress, nil
+	}
+
+	last := new(lockProgress)
+	if err := files.ReadJsonFile(path, last); err != nil {
+		return nil, fmt.Errorf("read progress %s err: %v", path, err)
+	}
+	if last.SrcChainId != srcChainID || last.DstChainId != dstChainID ||
+		last.Asset != progress.Asset || last.From != progress.From {
+		return nil, fmt.Errorf("progress %s locks %s of %s from chain %d to %d, not this batch",
+			path, last.Asset, last.From, last.SrcChainId, last.DstChainId)
+	}
+	progress.Tokens = last.Tokens
+	return progress, nil
+}
+
+// merge adds the tokens of the csv which are not in the progress yet, a token
+// can not be sent to another recipient once it is in.
+func (p *lockProgress) merge(rows []*lockRecord) error {
+	index := make(map[string]*lockRecord, len(p.Tokens))
+	for _, record := range p.Tokens {
+		index[record.TokenId] = record
+	}
+	for _, row := range rows {
+		record, ok := index[row.TokenId]
+		if !ok {
+			index[row.TokenId] = row
+			p.Tokens = append(p.Tokens, row)
+			continue
+		}
+		if record.To != row.To {
+			return fmt.Errorf("token %s is locked to %s in the progress, not %s", row.TokenId, record.To, row.To)
+		}
+	}
+	return p.save()
+}
+
+func (p *lockProgress) filter(states ...string) []*lockRecord {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var res []*lockRecord
+	for _, record := range p.Tokens {
+		for _, state := range states {
+			if record.State == state {
+				res = append(res, record)
+				break
+			}
+		}
+	}
+	return res
+}
+
+func (p *lockProgress) update(record *lockRecord, state, hash, errMsg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	record.State, record.Hash, record.Error = state, hash, errMsg
+	if err := p.saveLocked(); err != nil {
+		log.Error("save progress %s err: %v", p.path, err)
+	}
+}
+
+func (p *lockProgress) save() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.saveLocked()
+}
+
+// saveLocked writes a temporary file first, the progress is not lost when
+// the tool is killed in the middle.
+func (p *lockProgress) saveLocked() error {
+	tmp := p.path + ".tmp"
+	if err := files.WriteJsonFile(tmp, p, true); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
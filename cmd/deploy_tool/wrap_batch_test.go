@@ -0,0 +1,237 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/sdk/bridge_sdk"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/test/simulation"
+	"github.com/stretchr/testify/assert"
+)
+
+var lockFee = big.NewInt(1e16)
+
+// newLockChain deploys the bridge on a simulated source chain bound to a
+// destination one, mints the tokens to the user and points the tool at the
+// source chain.
+func newLockChain(t *testing.T, tokens ...int64) (*simulation.Chain, *simulation.Chain, eth_sdk.Signer) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	user := eth_sdk.NewKeySigner(key)
+	src, err := simulation.NewChain(basedef.ETHEREUM_CROSSCHAIN_ID, user.Address())
+	assert.NoError(t, err)
+	dst, err := simulation.NewChain(basedef.BSC_CROSSCHAIN_ID, user.Address())
+	assert.NoError(t, err)
+	assert.NoError(t, src.Deploy("dog", "DOG"))
+	assert.NoError(t, dst.Deploy("dog", "DOG"))
+	assert.NoError(t, simulation.Bind(src, dst))
+	for _, id := range tokens {
+		_, err := src.Mint(user.Address(), big.NewInt(id), fmt.Sprintf("https://nft.poly.network/dog/%d", id))
+		assert.NoError(t, err)
+	}
+
+	sdk, cc = src.Sdk, &ChainConfig{SideChainID: src.ChainId, NFTWrap: src.Wrapper.Hex()}
+	return src, dst, user
+}
+
+func writeLockCsv(t *testing.T, path string, lines ...string) []*lockRecord {
+	content := ""
+	for _, line := range lines {
+		content += line + "\n"
+	}
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	rows, err := readLockBatch(path)
+	assert.NoError(t, err)
+	return rows
+}
+
+func TestLockProgressMerge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lock-batch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	csvPath, progressPath := filepath.Join(dir, "tokens.csv"), filepath.Join(dir, "progress.json")
+	asset, from := common.HexToAddress("a1"), common.HexToAddress("f1")
+	alice, bob := common.HexToAddress("01").Hex(), common.HexToAddress("02").Hex()
+
+	rows := writeLockCsv(t, csvPath, "# tokenId,to", "1,"+alice, "2, "+bob)
+	progress, err := loadLockProgress(progressPath, 2, 6, asset, from)
+	assert.NoError(t, err)
+	assert.NoError(t, progress.merge(rows))
+	progress.update(progress.Tokens[0], lockFinished, "0x01", "")
+
+	// the csv grows, the progress keeps the states of the tokens it has
+	rows = writeLockCsv(t, csvPath, "1,"+alice, "2,"+bob, "3,"+bob)
+	progress, err = loadLockProgress(progressPath, 2, 6, asset, from)
+	assert.NoError(t, err)
+	assert.NoError(t, progress.merge(rows))
+	assert.Equal(t, []*lockRecord{
+		{TokenId: "1", To: alice, State: lockFinished, Hash: "0x01"},
+		{TokenId: "2", To: bob, State: lockPending},
+		{TokenId: "3", To: bob, State: lockPending},
+	}, progress.Tokens)
+
+	// a token is pinned to its recipient
+	rows = writeLockCsv(t, csvPath, "1,"+bob)
+	progress, err = loadLockProgress(progressPath, 2, 6, asset, from)
+	assert.NoError(t, err)
+	assert.Error(t, progress.merge(rows))
+
+	// the progress of another batch is not resumed
+	_, err = loadLockProgress(progressPath, 2, 7, asset, from)
+	assert.Error(t, err)
+	_, err = loadLockProgress(progressPath, 2, 6, common.HexToAddress("a2"), from)
+	assert.Error(t, err)
+}
+
+func TestLockBatchResume(t *testing.T) {
+	src, dst, user := newLockChain(t, 1, 2, 3, 4)
+	dir, err := ioutil.TempDir("", "lock-batch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	progressPath := filepath.Join(dir, "progress.json")
+	to := user.Address().Hex()
+	rows := writeLockCsv(t, filepath.Join(dir, "tokens.csv"), "1,"+to, "2,"+to, "3,"+to, "4,"+to)
+	progress, err := loadLockProgress(progressPath, src.ChainId, dst.ChainId, src.NFT, user.Address())
+	assert.NoError(t, err)
+	assert.NoError(t, progress.merge(rows))
+
+	// 1 and 2 were mined by a run which died before it saved them, 2 was
+	// recorded as failed with its hash, 1 was never recorded
+	_, err = src.Lock(user, dst, user.Address(), big.NewInt(1), lockFee)
+	assert.NoError(t, err)
+	hash2, err := src.Lock(user, dst, user.Address(), big.NewInt(2), lockFee)
+	assert.NoError(t, err)
+	progress.update(progress.Tokens[1], lockFailed, hash2.Hex(), "not confirmed")
+	// 3 failed to be sent, it is sent again
+	progress.update(progress.Tokens[2], lockFailed, "", "nonce too low")
+
+	todo := progress.filter(lockPending, lockFailed)
+	assert.Equal(t, 4, len(todo))
+	todo, err = checkLockBatch(progress, user, src.NFT, src.Wrapper, lockFee, todo, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*lockRecord{progress.Tokens[2], progress.Tokens[3]}, todo)
+	assert.Equal(t, lockSent, progress.Tokens[0].State)
+	assert.Empty(t, progress.Tokens[0].Hash)
+	assert.Equal(t, lockSent, progress.Tokens[1].State)
+	assert.Equal(t, hash2.Hex(), progress.Tokens[1].Hash)
+	approved, err := src.Sdk.IsNFTApprovedForAll(src.NFT, user.Address(), src.Wrapper)
+	assert.NoError(t, err)
+	assert.True(t, approved)
+
+	sendLockBatch(progress, user, src.NFT, src.Wrapper, dst.ChainId, lockFee, big.NewInt(0), todo, 2)
+	for _, id := range []int64{1, 2, 3, 4} {
+		owner, err := src.Sdk.GetNFTOwner(src.NFT, big.NewInt(id))
+		assert.NoError(t, err)
+		assert.Equal(t, src.Proxy, owner)
+	}
+
+	// the saved progress is what the next run resumes
+	saved, err := loadLockProgress(progressPath, src.ChainId, dst.ChainId, src.NFT, user.Address())
+	assert.NoError(t, err)
+	for i, record := range saved.Tokens {
+		assert.Equal(t, lockSent, record.State, "token %s", record.TokenId)
+		assert.Empty(t, record.Error)
+		if i >= 2 {
+			assert.NotEmpty(t, record.Hash)
+		}
+	}
+	assert.Empty(t, saved.filter(lockPending, lockFailed))
+
+	// tracking skips the lock whose hash is unknown
+	interval := lockTrackInterval
+	lockTrackInterval = 10 * time.Millisecond
+	defer func() { lockTrackInterval = interval }()
+	finished := make(map[string]bool)
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := new(bridge_sdk.TransactionOfHashReq)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		mu.Lock()
+		finished[req.Hash] = true
+		mu.Unlock()
+		assert.NoError(t, json.NewEncoder(w).Encode(&bridge_sdk.TransactionRsp{Hash: req.Hash, State: basedef.STATE_FINISHED}))
+	}))
+	defer server.Close()
+	trackLockBatch(saved, bridge_sdk.NewBridgeSdk(server.URL+"/nft/v1/"), time.Minute, 2)
+	assert.Equal(t, 3, len(finished))
+	assert.Equal(t, []*lockRecord{saved.Tokens[0]}, saved.filter(lockSent))
+}
+
+func TestTrackLockBatchRateLimit(t *testing.T) {
+	interval := lockTrackInterval
+	lockTrackInterval = 10 * time.Millisecond
+	defer func() { lockTrackInterval = interval }()
+
+	var (
+		mu       sync.Mutex
+		requests int
+		keys     []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		first := requests == 1
+		keys = append(keys, r.Header.Get(bridge_sdk.HeaderApiKey))
+		mu.Unlock()
+		if first {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		req := new(bridge_sdk.TransactionOfHashReq)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		assert.NoError(t, json.NewEncoder(w).Encode(&bridge_sdk.TransactionRsp{Hash: req.Hash, State: basedef.STATE_FINISHED}))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "lock-batch")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	progress := &lockProgress{
+		Tokens: []*lockRecord{
+			{TokenId: "1", State: lockSent, Hash: "0x01"},
+			{TokenId: "2", State: lockSent, Hash: "0x02"},
+		},
+		path: filepath.Join(dir, "progress.json"),
+	}
+	bridge := bridge_sdk.NewBridgeSdk(server.URL + "/nft/v1/")
+	bridge.SetApiKey("key")
+	start := time.Now()
+	trackLockBatch(progress, bridge, time.Minute, 1)
+
+	// the round stops at the 429 and waits for Retry-After
+	assert.True(t, time.Since(start) >= time.Second)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, []string{"key", "key", "key"}, keys)
+	assert.Equal(t, 2, len(progress.filter(lockFinished)))
+}
@@ -27,6 +27,7 @@ import (
 	"syscall"
 
 	"github.com/astaxie/beego/logs"
+	"github.com/polynetwork/poly-nft-bridge/alert"
 	"github.com/polynetwork/poly-nft-bridge/conf"
 	"github.com/polynetwork/poly-nft-bridge/dao/crosschaindao"
 	wp "github.com/polynetwork/poly-nft-bridge/wrap"
@@ -136,6 +137,14 @@ func startServer(ctx *cli.Context) {
 
 	chainHandler := wp.NewChainHandle(chainListenConfig)
 	chainListen = wp.NewCrossChainListen(chainHandler, db)
+	if config.MonitorConfig != nil {
+		notifier, err := alert.NewNotifierFromConfig(config.MonitorConfig.Sinks)
+		if err != nil {
+			panic(err)
+		}
+		notifier.SetThrottle(config.MonitorConfig.Throttle)
+		chainListen.SetNotifier(notifier)
+	}
 	chainListen.Start()
 }
 
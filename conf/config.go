@@ -25,6 +25,7 @@ import (
 )
 
 type DBConfig struct {
+	Driver   string // mysql(default), postgres or sqlite
 	URL      string
 	User     string
 	Password string
@@ -46,6 +47,7 @@ type ChainListenConfig struct {
 	ExtendNodes     []*Restful
 	WrapperContract string
 	ECCMContract    string
+	CCMPContract    string
 	ProxyContract   string
 }
 
@@ -81,11 +83,102 @@ func (cfg *ChainListenConfig) GetExtendNodesKey() []string {
 	return keys
 }
 
+type RateLimit struct {
+	Rate  float64 // tokens refilled per second
+	Burst int     // bucket capacity
+}
+
+type ApiKeyConfig struct {
+	Key       string
+	Name      string
+	Limit     *RateLimit
+	NodeLimit *RateLimit
+}
+
+type HttpConfig struct {
+	AuthRequired bool
+	KeysFromDB   bool
+	TrustProxy   bool
+	ApiKeys      []*ApiKeyConfig
+	KeyLimit     *RateLimit
+	KeyNodeLimit *RateLimit
+	IpLimit      *RateLimit
+	IpNodeLimit  *RateLimit
+	NodeRoutes   []string
+}
+
+type AlertSinkConfig struct {
+	Type     string // log(default), webhook, dingtalk, slack or smtp
+	Url      string
+	Headers  map[string]string
+	Level    string   // alerts below the level are not sent, all by default
+	Secret   string   // dingtalk signing secret
+	Addr     string   // smtp server host:port
+	User     string   // smtp user, no auth when empty
+	Password string   // smtp password
+	From     string   // smtp sender
+	To       []string // smtp receivers
+}
+
+type AlertThrottleConfig struct {
+	Dedup  int64 // seconds an alert of the same key is sent once
+	Limit  int   // alerts sent in a period at most, no limit when zero
+	Period int64 // seconds of a rate limit period
+}
+
+type AlertRulesConfig struct {
+	Interval int64  // seconds between two evaluations
+	Stalled  int64  // seconds a chain height may stay the same
+	Lag      uint64 // blocks the listen height may fall behind the node
+	Pending  int64  // seconds a wrapper transaction may stay unfinished
+}
+
+type UnlockMonitorConfig struct {
+	Interval uint64 // seconds between two checks
+	Grace    uint64 // seconds an unlock may wait for its poly and source transactions
+}
+
+type SupplyAuditConfig struct {
+	Interval  uint64 // seconds between two audits
+	PageSize  int    // tokens read by one call
+	MaxTokens int    // tokens read of one asset at most
+	Grace     uint64 // seconds a locked token may circulate nowhere while its lock is relayed
+}
+
+type BalanceAccountConfig struct {
+	ChainId uint64
+	Role    string // relayer or admin
+	Address string
+	Min     string // native balance alerted below, in whole coins
+	Runway  int64  // seconds of runway alerted below, relayer only
+}
+
+type BalanceMonitorConfig struct {
+	Window   int64 // seconds of destination fees the relayer burn is estimated from
+	Accounts []*BalanceAccountConfig
+}
+
+type EpochMonitorConfig struct {
+	Grace int64 // seconds an eccd may keep the previous poly epoch, the relayers change it
+}
+
+type MonitorConfig struct {
+	Unlock   *UnlockMonitorConfig
+	Supply   *SupplyAuditConfig
+	Rules    *AlertRulesConfig
+	Balance  *BalanceMonitorConfig
+	Epoch    *EpochMonitorConfig
+	Sinks    []*AlertSinkConfig
+	Throttle *AlertThrottleConfig
+}
+
 type Config struct {
 	Server            string
 	Backup            bool
 	ChainListenConfig []*ChainListenConfig
 	DBConfig          *DBConfig
+	HttpConfig        *HttpConfig
+	MonitorConfig     *MonitorConfig
 }
 
 func (cfg *Config) GetChainListenConfig(chainId uint64) *ChainListenConfig {
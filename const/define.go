@@ -30,3 +30,46 @@ const (
 const (
 	ADDRESS_LENGTH = 64
 )
+
+// Disable flag of the nft asset basics, assets and asset maps. ASSET_REMOVED
+// is a map direction removed by the operator, only restoring it enables it.
+const (
+	ASSET_ENABLE = iota
+	ASSET_DISABLE
+	ASSET_PENDING
+	ASSET_REMOVED
+)
+
+// Resolved flag of the unmatched unlocks
+const (
+	UNLOCK_UNRESOLVED = iota
+	UNLOCK_RESOLVED
+)
+
+// UnlockChecked flag of the dst transactions
+const (
+	UNLOCK_UNCHECKED = iota
+	UNLOCK_CHECKED
+)
+
+// Contracts and events of the governance events
+const (
+	GOVERNANCE_ECCM    = "eccm"
+	GOVERNANCE_CCMP    = "ccmp"
+	GOVERNANCE_PROXY   = "proxy"
+	GOVERNANCE_WRAPPER = "wrapper"
+
+	GOVERNANCE_OWNERSHIP_TRANSFERRED = "OwnershipTransferred"
+	GOVERNANCE_SET_MANAGER_PROXY     = "SetManagerProxyEvent"
+	GOVERNANCE_BIND_PROXY            = "BindProxyEvent"
+	GOVERNANCE_PAUSED                = "Paused"
+	GOVERNANCE_UNPAUSED              = "Unpaused"
+)
+
+const (
+	ACCOUNT_RELAYER       = "relayer"
+	ACCOUNT_ADMIN         = "admin"
+	ACCOUNT_FEE_COLLECTOR = "fee_collector"
+
+	NATIVE_TOKEN = "0000000000000000000000000000000000000000"
+)
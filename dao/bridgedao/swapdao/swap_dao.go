@@ -0,0 +1,394 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package swapdao
+
+import (
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// from and key are reserved words, leave the quoting to the dialect
+var (
+	fromColumn = clause.Column{Table: "src_transfers", Name: "from"}
+	keyColumn  = clause.Column{Name: "key"}
+)
+
+type SwapDao struct {
+	dbCfg *conf.DBConfig
+	db    *gorm.DB
+}
+
+func NewSwapDao(dbCfg *conf.DBConfig) *SwapDao {
+	db := dbopen.MustOpen(dbCfg)
+	return &SwapDao{
+		dbCfg: dbCfg,
+		db:    db,
+	}
+}
+
+func (dao *SwapDao) GetAssets(chainId uint64) ([]*models.NFTAsset, error) {
+	assets := make([]*models.NFTAsset, 0)
+	res := dao.db.Where("chain_id = ?", chainId).
+		Preload("AssetBasic").
+		Preload("AssetMaps").
+		Preload("AssetMaps.DstAsset").
+		Find(&assets)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return assets, nil
+}
+
+func (dao *SwapDao) GetAsset(chainId uint64, hash string) (*models.NFTAsset, error) {
+	asset := new(models.NFTAsset)
+	res := dao.db.Where("hash = ? and chain_id = ?", hash, chainId).
+		Preload("AssetBasic").
+		Preload("AssetMaps").
+		Preload("AssetMaps.DstAsset").
+		Limit(1).
+		Find(asset)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return asset, nil
+}
+
+func (dao *SwapDao) GetAssetBasics() ([]*models.NFTAssetBasic, error) {
+	assetBasics := make([]*models.NFTAssetBasic, 0)
+	res := dao.db.Model(&models.NFTAssetBasic{}).Preload("Assets").Find(&assetBasics)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return assetBasics, nil
+}
+
+func (dao *SwapDao) GetAssetMaps(srcChainId uint64, srcHash string) ([]*models.NFTAssetMap, error) {
+	assetMaps := make([]*models.NFTAssetMap, 0)
+	res := dao.db.Where("src_chain_id = ? and src_asset_hash = ?", srcChainId, srcHash).
+		Preload("SrcAsset").
+		Preload("DstAsset").
+		Find(&assetMaps)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return assetMaps, nil
+}
+
+func (dao *SwapDao) GetAssetMapsReverse(dstChainId uint64, dstHash string) ([]*models.NFTAssetMap, error) {
+	assetMaps := make([]*models.NFTAssetMap, 0)
+	res := dao.db.Where("dst_chain_id = ? and dst_asset_hash = ?", dstChainId, dstHash).
+		Preload("SrcAsset").
+		Preload("DstAsset").
+		Find(&assetMaps)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return assetMaps, nil
+}
+
+func (dao *SwapDao) GetFeeToken(chainId uint64, hash string) (*models.Token, error) {
+	token := new(models.Token)
+	res := dao.db.Where("hash = ? and chain_id = ?", hash, chainId).Preload("TokenBasic").Limit(1).Find(token)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return token, nil
+}
+
+func (dao *SwapDao) GetChainFee(chainId uint64) (*models.ChainFee, error) {
+	chainFee := new(models.ChainFee)
+	res := dao.db.Where("chain_id = ?", chainId).Preload("TokenBasic").Limit(1).Find(chainFee)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return chainFee, nil
+}
+
+func (dao *SwapDao) GetFeeTokens(chainIds []uint64, hashes []string) ([]*models.Token, error) {
+	tokens := make([]*models.Token, 0)
+	res := dao.db.Where("chain_id in ? and hash in ?", chainIds, hashes).Preload("TokenBasic").Find(&tokens)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return tokens, nil
+}
+
+func (dao *SwapDao) GetChainFees(chainIds []uint64) ([]*models.ChainFee, error) {
+	chainFees := make([]*models.ChainFee, 0)
+	res := dao.db.Where("chain_id in ?", chainIds).Preload("TokenBasic").Find(&chainFees)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return chainFees, nil
+}
+
+func (dao *SwapDao) GetChains() (map[uint64]*models.Chain, error) {
+	chains := make([]*models.Chain, 0)
+	res := dao.db.Model(&models.Chain{}).Find(&chains)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	chainsMap := make(map[uint64]*models.Chain)
+	for _, chain := range chains {
+		chainsMap[chain.ChainId] = chain
+	}
+	return chainsMap, nil
+}
+
+func (dao *SwapDao) GetWrapperTransactions(pageNo, pageSize int) ([]*models.WrapperTransaction, int64, error) {
+	transactions := make([]*models.WrapperTransaction, 0)
+	res := dao.db.Limit(pageSize).
+		Offset(pageSize * pageNo).
+		Order("time asc").
+		Find(&transactions)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+
+	var transactionNum int64
+	res = dao.db.Model(&models.WrapperTransaction{}).Count(&transactionNum)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+	return transactions, transactionNum, nil
+}
+
+func (dao *SwapDao) GetWrapperTransactionsOfState(state uint64, pageNo, pageSize int) ([]*models.WrapperTransaction, int64, error) {
+	transactions := make([]*models.WrapperTransaction, 0)
+	res := dao.db.Where("status = ?", state).
+		Limit(pageSize).
+		Offset(pageSize * pageNo).
+		Order("time asc").
+		Find(&transactions)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+
+	var transactionNum int64
+	res = dao.db.Model(&models.WrapperTransaction{}).
+		Where("status = ?", state).
+		Count(&transactionNum)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+	return transactions, transactionNum, nil
+}
+
+// relations joins the wrapper transfers selected by the sub query with the
+// transactions of every step of the cross chain flow.
+func (dao *SwapDao) relations(transfers *gorm.DB) *gorm.DB {
+	return dao.db.Table("(?) as u", transfers).
+		Select("src_transactions.hash as src_hash, " +
+			"poly_transactions.hash as poly_hash, " +
+			"dst_transactions.hash as dst_hash, " +
+			"src_transactions.chain_id as chain_id," +
+			"u.asset as asset_hash, u.fee_token_hash as fee_token_hash").
+		Joins("left join src_transactions on u.hash = src_transactions.hash").
+		Joins("left join poly_transactions on src_transactions.hash = poly_transactions.src_hash").
+		Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
+		Preload("WrapperTransaction").
+		Preload("Asset").
+		Preload("Asset.AssetBasic").
+		Preload("FeeToken").
+		Preload("FeeToken.TokenBasic").
+		Preload("SrcTransaction").
+		Preload("SrcTransaction.SrcTransfer").
+		Preload("PolyTransaction").
+		Preload("DstTransaction").
+		Preload("DstTransaction.DstTransfer")
+}
+
+func (dao *SwapDao) wrapperTransfers() *gorm.DB {
+	return dao.db.Model(&models.SrcTransfer{}).
+		Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash")
+}
+
+func (dao *SwapDao) GetTransactionsOfAddress(addresses []string, pageNo, pageSize int) ([]*models.SrcPolyDstRelation, int64, error) {
+	srcPolyDstRelations := make([]*models.SrcPolyDstRelation, 0)
+	res := dao.relations(dao.wrapperTransfers().
+		Select("src_transfers.tx_hash as hash, src_transfers.asset as asset, wrapper_transactions.fee_token_hash as fee_token_hash").
+		Where("? in ? or src_transfers.dst_user in ?", fromColumn, addresses, addresses)).
+		Limit(pageSize).Offset(pageSize * pageNo).
+		Order("src_transactions.time desc").
+		Find(&srcPolyDstRelations)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+
+	var transactionNum int64
+	res = dao.wrapperTransfers().
+		Where("? in ? or src_transfers.dst_user in ?", fromColumn, addresses, addresses).
+		Count(&transactionNum)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+	return srcPolyDstRelations, transactionNum, nil
+}
+
+func (dao *SwapDao) GetTransactionOfHash(hash string) (*models.SrcPolyDstRelation, error) {
+	srcPolyDstRelation := new(models.SrcPolyDstRelation)
+	res := dao.relations(dao.wrapperTransfers().
+		Select("src_transfers.tx_hash as hash, src_transfers.asset as asset, wrapper_transactions.fee_token_hash as fee_token_hash").
+		Where("src_transfers.tx_hash = ?", hash)).
+		Order("src_transactions.time desc").
+		Find(srcPolyDstRelation)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return srcPolyDstRelation, nil
+}
+
+func (dao *SwapDao) GetWrapperSpeedUps(hash string) ([]*models.WrapperSpeedUp, error) {
+	speedUps := make([]*models.WrapperSpeedUp, 0)
+	res := dao.db.Where("src_hash = ?", hash).Order("height asc, log_index asc").Find(&speedUps)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return speedUps, nil
+}
+
+func (dao *SwapDao) GetRelayFailures(polyHash string) ([]*models.DstRelayFailure, error) {
+	failures := make([]*models.DstRelayFailure, 0)
+	res := dao.db.Where("poly_hash = ?", polyHash).Order("height asc").Find(&failures)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return failures, nil
+}
+
+func (dao *SwapDao) GetWrapperTransactionsOfHashes(hashes []string) ([]*models.WrapperTransaction, error) {
+	wrapperTransactions := make([]*models.WrapperTransaction, 0)
+	res := dao.db.Where("hash in ?", hashes).Find(&wrapperTransactions)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return wrapperTransactions, nil
+}
+
+// GetSrcTransactionsOfHashes finds the source transactions by hash or by the
+// key which the relayer knows them by.
+func (dao *SwapDao) GetSrcTransactionsOfHashes(hashes []string) ([]*models.SrcTransaction, error) {
+	srcTransactions := make([]*models.SrcTransaction, 0)
+	res := dao.db.Where("? in ? or hash in ?", keyColumn, hashes, hashes).Find(&srcTransactions)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return srcTransactions, nil
+}
+
+func (dao *SwapDao) GetApiKeys() ([]*models.ApiKey, error) {
+	keys := make([]*models.ApiKey, 0)
+	res := dao.db.Where("disable = 0").Find(&keys)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return keys, nil
+}
+
+func (dao *SwapDao) GetSupplyAudits() ([]*models.NFTSupplyAudit, error) {
+	audits := make([]*models.NFTSupplyAudit, 0)
+	res := dao.db.Preload("Issues").Order("asset_basic_name asc").Find(&audits)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return audits, nil
+}
+
+// GetGovernanceEvents returns the latest governance events first, a zero
+// chain id returns the events of all chains.
+func (dao *SwapDao) GetGovernanceEvents(chainId uint64, pageNo, pageSize int) ([]*models.GovernanceEvent, int64, error) {
+	events := make([]*models.GovernanceEvent, 0)
+	var eventNum int64
+	query := dao.db
+	if chainId != 0 {
+		query = query.Where("chain_id = ?", chainId)
+	}
+	res := query.Limit(pageSize).
+		Offset(pageSize * pageNo).
+		Order("height desc").
+		Order("log_index desc").
+		Find(&events)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+	query = dao.db.Model(&models.GovernanceEvent{})
+	if chainId != 0 {
+		query = query.Where("chain_id = ?", chainId)
+	}
+	res = query.Count(&eventNum)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+	return events, eventNum, nil
+}
+
+// GetPausedWrappers returns the chains whose wrapper was paused and not
+// unpaused since.
+func (dao *SwapDao) GetPausedWrappers() (map[uint64]bool, error) {
+	events := make([]*models.GovernanceEvent, 0)
+	res := dao.db.Where("contract = ? and event in ?", basedef.GOVERNANCE_WRAPPER,
+		[]string{basedef.GOVERNANCE_PAUSED, basedef.GOVERNANCE_UNPAUSED}).
+		Order("height asc").
+		Order("log_index asc").
+		Find(&events)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return pausedWrappers(events), nil
+}
+
+func (dao *SwapDao) GetAccountBalances() ([]*models.AccountBalance, error) {
+	balances := make([]*models.AccountBalance, 0)
+	res := dao.db.Order("chain_id asc").Order("role asc").Order("address asc").Order("token asc").Find(&balances)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return balances, nil
+}
+
+func pausedWrappers(events []*models.GovernanceEvent) map[uint64]bool {
+	paused := make(map[uint64]bool)
+	for _, event := range events {
+		if event.Event == basedef.GOVERNANCE_PAUSED {
+			paused[event.ChainId] = true
+		} else {
+			delete(paused, event.ChainId)
+		}
+	}
+	return paused
+}
+
+func (dao *SwapDao) Name() string {
+	return basedef.SERVER_POLY_SWAP
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package swapdao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/dao/migration"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqlRecorder keeps the statements a dry run session would execute.
+type sqlRecorder struct {
+	logger.Interface
+	sqls []string
+}
+
+func (r *sqlRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	r.sqls = append(r.sqls, sql)
+}
+
+func TestGetSrcTransactionsOfHashes(t *testing.T) {
+	dbCfg := &conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:bridge_src_of_hashes?mode=memory&cache=shared"}
+	dao := NewSwapDao(dbCfg)
+	dao.db.Logger = logger.Discard
+	_, err := migration.Up(dao.db, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, dao.db.Create([]*models.SrcTransaction{
+		{Hash: "aa", Key: "ka", Fee: models.NewBigIntFromInt(0)},
+		{Hash: "bb", Key: "kb", Fee: models.NewBigIntFromInt(0)},
+		{Hash: "cc", Key: "kc", Fee: models.NewBigIntFromInt(0)},
+	}).Error)
+
+	txs, err := dao.GetSrcTransactionsOfHashes([]string{"ka", "bb", "dd"})
+	assert.NoError(t, err)
+	hashes := make([]string, 0)
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	assert.ElementsMatch(t, []string{"aa", "bb"}, hashes)
+
+	// postgres takes no backticks, the dialect quotes the column
+	dialector, err := dbopen.Dialector(&conf.DBConfig{Driver: dbopen.DriverPostgres, URL: "127.0.0.1:5432"})
+	assert.NoError(t, err)
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: recorder})
+	assert.NoError(t, err)
+	_, err = (&SwapDao{db: db}).GetSrcTransactionsOfHashes([]string{"ka"})
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(recorder.sqls)) {
+		assert.Contains(t, recorder.sqls[0], `"key" in ('ka')`)
+		assert.NotContains(t, recorder.sqls[0], "`")
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bridgedao
+
+import (
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao/swapdao"
+	"github.com/polynetwork/poly-nft-bridge/models"
+)
+
+// BridgeDao is the read side used by the rpc controllers. Getters of a single
+// record return nil without error when the record does not exist.
+type BridgeDao interface {
+	GetAssets(chainId uint64) ([]*models.NFTAsset, error)
+	GetAsset(chainId uint64, hash string) (*models.NFTAsset, error)
+	GetAssetBasics() ([]*models.NFTAssetBasic, error)
+	GetAssetMaps(srcChainId uint64, srcHash string) ([]*models.NFTAssetMap, error)
+	GetAssetMapsReverse(dstChainId uint64, dstHash string) ([]*models.NFTAssetMap, error)
+	GetFeeToken(chainId uint64, hash string) (*models.Token, error)
+	GetChainFee(chainId uint64) (*models.ChainFee, error)
+	GetFeeTokens(chainIds []uint64, hashes []string) ([]*models.Token, error)
+	GetChainFees(chainIds []uint64) ([]*models.ChainFee, error)
+	GetChains() (map[uint64]*models.Chain, error)
+	GetWrapperTransactions(pageNo, pageSize int) ([]*models.WrapperTransaction, int64, error)
+	GetWrapperTransactionsOfState(state uint64, pageNo, pageSize int) ([]*models.WrapperTransaction, int64, error)
+	GetTransactionsOfAddress(addresses []string, pageNo, pageSize int) ([]*models.SrcPolyDstRelation, int64, error)
+	GetTransactionOfHash(hash string) (*models.SrcPolyDstRelation, error)
+	GetWrapperSpeedUps(hash string) ([]*models.WrapperSpeedUp, error)
+	GetRelayFailures(polyHash string) ([]*models.DstRelayFailure, error)
+	GetWrapperTransactionsOfHashes(hashes []string) ([]*models.WrapperTransaction, error)
+	GetSrcTransactionsOfHashes(hashes []string) ([]*models.SrcTransaction, error)
+	GetApiKeys() ([]*models.ApiKey, error)
+	GetSupplyAudits() ([]*models.NFTSupplyAudit, error)
+	GetGovernanceEvents(chainId uint64, pageNo, pageSize int) ([]*models.GovernanceEvent, int64, error)
+	GetPausedWrappers() (map[uint64]bool, error)
+	GetAccountBalances() ([]*models.AccountBalance, error)
+	Name() string
+}
+
+func NewBridgeDao(server string, dbCfg *conf.DBConfig) BridgeDao {
+	if server == basedef.SERVER_POLY_SWAP {
+		return swapdao.NewSwapDao(dbCfg)
+	} else {
+		return nil
+	}
+}
@@ -22,10 +22,9 @@ import (
 
 	"github.com/polynetwork/poly-nft-bridge/conf"
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
 	"github.com/polynetwork/poly-nft-bridge/models"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 type SwapDao struct {
@@ -37,15 +36,7 @@ func NewSwapDao(dbCfg *conf.DBConfig) *SwapDao {
 	swapDao := &SwapDao{
 		dbCfg: dbCfg,
 	}
-	Logger := logger.Default
-	if dbCfg.Debug == true {
-		Logger = Logger.LogMode(logger.Info)
-	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
-	if err != nil {
-		panic(err)
-	}
+	db := dbopen.MustOpen(dbCfg)
 	swapDao.db = db
 	return swapDao
 }
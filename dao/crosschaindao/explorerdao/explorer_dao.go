@@ -23,10 +23,9 @@ import (
 
 	"github.com/polynetwork/poly-nft-bridge/conf"
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
 	"github.com/polynetwork/poly-nft-bridge/models"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 type Chain struct {
@@ -164,15 +163,7 @@ func NewExplorerDao(dbCfg *conf.DBConfig) *ExplorerDao {
 	explorerDao := &ExplorerDao{
 		dbCfg: dbCfg,
 	}
-	Logger := logger.Default
-	if dbCfg.Debug == true {
-		Logger = Logger.LogMode(logger.Info)
-	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
-	if err != nil {
-		panic(err)
-	}
+	db := dbopen.MustOpen(dbCfg)
 	explorerDao.db = db
 	return explorerDao
 }
@@ -273,13 +264,13 @@ func (dao *ExplorerDao) UpdateEvents(chain *models.Chain, wrapperTransactions []
 }
 
 func (dao *ExplorerDao) RemoveEvents(srcHashes []string, polyHashes []string, dstHashes []string) error {
-	dao.db.Where("`txhash` in ?", srcHashes).Delete(&SrcTransfer{})
-	dao.db.Where("`txhash` in ?", srcHashes).Delete(&SrcTransaction{})
+	dao.db.Where("txhash in ?", srcHashes).Delete(&SrcTransfer{})
+	dao.db.Where("txhash in ?", srcHashes).Delete(&SrcTransaction{})
 
-	dao.db.Where("`txhash` in ?", polyHashes).Delete(&PolyTransaction{})
+	dao.db.Where("txhash in ?", polyHashes).Delete(&PolyTransaction{})
 
-	dao.db.Where("`txhash` in ?", dstHashes).Delete(&DstTransfer{})
-	dao.db.Where("`txhash` in ?", dstHashes).Delete(&DstTransaction{})
+	dao.db.Where("txhash in ?", dstHashes).Delete(&DstTransfer{})
+	dao.db.Where("txhash in ?", dstHashes).Delete(&DstTransaction{})
 	return nil
 }
 
@@ -403,30 +394,80 @@ func (dao *ExplorerDao) tokenType(chainId uint64) string {
 	}
 }
 
+// AddChains creates the chains missing in chain_info, the explorer has no chain fee.
 func (dao *ExplorerDao) AddChains(chain []*models.Chain, chainFees []*models.ChainFee) error {
+	for _, item := range chain {
+		newChain := &Chain{
+			ChainId: item.ChainId,
+			Name:    dao.chainName(item.ChainId),
+			Height:  item.Height,
+		}
+		res := dao.db.Where("id = ?", newChain.ChainId).FirstOrCreate(newChain)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
 	return nil
 }
 
-func (dao *ExplorerDao) RemoveTokenMaps(tokenMaps []*models.TokenMap) error {
-	return nil
+func (dao *ExplorerDao) chainName(chainId uint64) string {
+	if chainId == basedef.POLY_CROSSCHAIN_ID {
+		return "Poly"
+	} else if chainId == basedef.ETHEREUM_CROSSCHAIN_ID {
+		return "Ethereum"
+	} else if chainId == basedef.ONT_CROSSCHAIN_ID {
+		return "Ontology"
+	} else if chainId == basedef.NEO_CROSSCHAIN_ID {
+		return "Neo"
+	} else if chainId == basedef.BSC_CROSSCHAIN_ID {
+		return "BSC"
+	} else if chainId == basedef.HECO_CROSSCHAIN_ID {
+		return "Heco"
+	} else {
+		return fmt.Sprintf("%d", chainId)
+	}
 }
 
-func (dao *ExplorerDao) RemoveTokens(tokens []string) error {
+func (dao *ExplorerDao) RemoveTokenMaps(tokenMaps []*models.TokenMap) error {
 	return nil
 }
 
-func (dao *ExplorerDao) Name() string {
-	return basedef.SERVER_EXPLORER
-}
-
-func (dao *ExplorerDao) AddAssets(assetBasics []*models.NFTAssetBasic) error {
-	return nil
+func (dao *ExplorerDao) RemoveTokens(tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return dao.removeTokens(dao.db.Where("xdesc in ? and xtype not in ?", tokens, nftTypes()))
 }
 
-func (dao *ExplorerDao) RemoveAssets(assets []string) error {
-	return nil
+// removeTokens deletes the tokens selected by query together with their binds.
+func (dao *ExplorerDao) removeTokens(query *gorm.DB) error {
+	explorerTokens := make([]*Token, 0)
+	res := query.Find(&explorerTokens)
+	if res.Error != nil {
+		return res.Error
+	}
+	if len(explorerTokens) == 0 {
+		return nil
+	}
+	hashes := make([]string, 0)
+	for _, token := range explorerTokens {
+		hashes = append(hashes, token.Hash)
+	}
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("hash_src in ? or hash_dest in ?", hashes, hashes).Delete(&TokenBind{})
+		if res.Error != nil {
+			return res.Error
+		}
+		for _, token := range explorerTokens {
+			res = tx.Where("id = ? and hash = ?", token.Id, token.Hash).Delete(&Token{})
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+		return nil
+	})
 }
 
-func (dao *ExplorerDao) RemoveAssetMaps(assetMaps []*models.NFTAssetMap) error {
-	return nil
+func (dao *ExplorerDao) Name() string {
+	return basedef.SERVER_EXPLORER
 }
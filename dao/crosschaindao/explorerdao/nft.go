@@ -0,0 +1,231 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package explorerdao
+
+import (
+	"strings"
+
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"gorm.io/gorm"
+)
+
+// AddAssets saves the enabled nft assets of the enabled basics into
+// chain_token, named by the asset basic, and removes the disabled ones with
+// their binds, as the swap dao disables their maps. The binds between the
+// assets of a basic on different chains are only created for the assets new to
+// the explorer, so the directions removed by RemoveAssetMaps stay removed.
+func (dao *ExplorerDao) AddAssets(assetBasics []*models.NFTAssetBasic) error {
+	explorerTokens, explorerTokenBinds, disabled := dao.BuildAssets(assetBasics)
+	if len(explorerTokens) == 0 && len(disabled) == 0 {
+		return nil
+	}
+	hashes := make([]string, 0)
+	for _, token := range explorerTokens {
+		hashes = append(hashes, token.Hash)
+	}
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if len(disabled) > 0 {
+			res := tx.Where("hash_src in ? or hash_dest in ?", disabled, disabled).Delete(&TokenBind{})
+			if res.Error != nil {
+				return res.Error
+			}
+			res = tx.Where("hash in ? and xtype in ?", disabled, nftTypes()).Delete(&Token{})
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+		if len(explorerTokens) == 0 {
+			return nil
+		}
+
+		existing := make([]*Token, 0)
+		res := tx.Where("hash in ? and xtype in ?", hashes, nftTypes()).Find(&existing)
+		if res.Error != nil {
+			return res.Error
+		}
+		known := make(map[string]bool)
+		for _, token := range existing {
+			known[token.Hash] = true
+		}
+		binds := make([]*TokenBind, 0)
+		res = tx.Where("hash_src in ? and hash_dest in ?", hashes, hashes).Find(&binds)
+		if res.Error != nil {
+			return res.Error
+		}
+		bound := make(map[TokenBind]bool)
+		for _, bind := range binds {
+			bound[*bind] = true
+		}
+
+		res = tx.Save(explorerTokens)
+		if res.Error != nil {
+			return res.Error
+		}
+		newBinds := make([]*TokenBind, 0)
+		for _, bind := range explorerTokenBinds {
+			if known[bind.SrcHash] && known[bind.DstHash] || bound[*bind] {
+				continue
+			}
+			newBinds = append(newBinds, bind)
+		}
+		if len(newBinds) > 0 {
+			res = tx.Create(newBinds)
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+		return nil
+	})
+}
+
+// BuildAssets returns the tokens of the enabled assets, the binds between
+// them and the hashes of the disabled assets.
+func (dao *ExplorerDao) BuildAssets(assetBasics []*models.NFTAssetBasic) ([]*Token, []*TokenBind, []string) {
+	explorerTokens := make([]*Token, 0)
+	explorerTokenBinds := make([]*TokenBind, 0)
+	disabled := make([]string, 0)
+	for _, basic := range assetBasics {
+		enabled := make([]*models.NFTAsset, 0)
+		for _, asset := range basic.Assets {
+			if basic.Disable != basedef.ASSET_ENABLE || asset.Disable != basedef.ASSET_ENABLE {
+				disabled = append(disabled, strings.ToLower(asset.Hash))
+				continue
+			}
+			enabled = append(enabled, asset)
+		}
+		for _, asset := range enabled {
+			explorerTokens = append(explorerTokens, &Token{
+				Id:        asset.ChainId,
+				Token:     basic.Name,
+				Hash:      strings.ToLower(asset.Hash),
+				Name:      asset.Name,
+				Type:      dao.nftType(asset.ChainId),
+				Precision: "1",
+				Desc:      basic.Name,
+			})
+			for _, dst := range enabled {
+				if dst.ChainId != asset.ChainId {
+					explorerTokenBinds = append(explorerTokenBinds, &TokenBind{
+						SrcHash: strings.ToLower(asset.Hash),
+						DstHash: strings.ToLower(dst.Hash),
+					})
+				}
+			}
+		}
+	}
+	return explorerTokens, explorerTokenBinds, disabled
+}
+
+func (dao *ExplorerDao) RemoveAssets(assets []string) error {
+	if len(assets) == 0 {
+		return nil
+	}
+	return dao.removeTokens(dao.db.Where("xdesc in ? and xtype in ?", assets, nftTypes()))
+}
+
+func (dao *ExplorerDao) RemoveAssetMaps(assetMaps []*models.NFTAssetMap) error {
+	for _, assetMap := range assetMaps {
+		res := dao.db.Where("hash_src = ? and hash_dest = ?",
+			strings.ToLower(assetMap.SrcAssetHash),
+			strings.ToLower(assetMap.DstAssetHash),
+		).Delete(&TokenBind{})
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
+
+// RestoreAssetMaps binds the removed directions again when both assets are in
+// the explorer.
+func (dao *ExplorerDao) RestoreAssetMaps(assetMaps []*models.NFTAssetMap) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		for _, assetMap := range assetMaps {
+			bind := &TokenBind{
+				SrcHash: strings.ToLower(assetMap.SrcAssetHash),
+				DstHash: strings.ToLower(assetMap.DstAssetHash),
+			}
+			var count int64
+			res := tx.Model(&Token{}).Where("hash in ? and xtype in ?", []string{bind.SrcHash, bind.DstHash}, nftTypes()).Count(&count)
+			if res.Error != nil {
+				return res.Error
+			}
+			if count < 2 {
+				continue
+			}
+			res = tx.Model(&TokenBind{}).Where("hash_src = ? and hash_dest = ?", bind.SrcHash, bind.DstHash).Count(&count)
+			if res.Error != nil {
+				return res.Error
+			}
+			if count > 0 {
+				continue
+			}
+			if res = tx.Create(bind); res.Error != nil {
+				return res.Error
+			}
+		}
+		return nil
+	})
+}
+
+func (dao *ExplorerDao) nftType(chainId uint64) string {
+	if chainId == basedef.ETHEREUM_CROSSCHAIN_ID {
+		return "erc721"
+	} else if chainId == basedef.NEO_CROSSCHAIN_ID {
+		return "nep11"
+	} else if chainId == basedef.HECO_CROSSCHAIN_ID {
+		return "hrc721"
+	} else if chainId == basedef.BSC_CROSSCHAIN_ID {
+		return "bep721"
+	} else if chainId == basedef.ONT_CROSSCHAIN_ID {
+		return "oep5"
+	} else {
+		return "nft"
+	}
+}
+
+func nftTypes() []string {
+	return []string{"erc721", "nep11", "hrc721", "bep721", "oep5", "nft"}
+}
+
+// The bindings wait for an approval in the swap db, approved assets reach the
+// explorer through AddAssets.
+func (dao *ExplorerDao) UpdateAssetBinds(assetBinds []*models.AssetBindEvent, proxyBinds []*models.ProxyBindEvent) error {
+	return nil
+}
+
+func (dao *ExplorerDao) UpdateGovernanceEvents(events []*models.GovernanceEvent) error {
+	return nil
+}
+
+func (dao *ExplorerDao) UpdateSpeedUps(speedUps []*models.WrapperSpeedUp) error {
+	return nil
+}
+
+func (dao *ExplorerDao) UpdateRelayFailures(failures []*models.DstRelayFailure) error {
+	return nil
+}
+
+func (dao *ExplorerDao) GetPendingAssets() ([]*models.NFTAsset, []*models.NFTAssetMap, error) {
+	return nil, nil, nil
+}
+
+func (dao *ExplorerDao) ApproveAssets(assets []string) error {
+	return nil
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package explorerdao
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// The nft tests run on sqlite in the package rather than in crosschaindao/test
+// next to crosschain_explorer_dao_test.go, whose tests read a mysql testnet
+// config and panic without one, which stops the package before these run.
+func newSqliteExplorerDao(t *testing.T, name string) (*ExplorerDao, *gorm.DB) {
+	dbCfg := &conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:" + name + "?mode=memory&cache=shared"}
+	db := dbopen.MustOpen(dbCfg)
+	assert.NoError(t, db.AutoMigrate(&Chain{}, &Token{}, &TokenBind{}))
+	return NewExplorerDao(dbCfg), db
+}
+
+func explorerBinds(t *testing.T, db *gorm.DB) map[string]bool {
+	binds := make([]*TokenBind, 0)
+	assert.NoError(t, db.Find(&binds).Error)
+	result := make(map[string]bool)
+	for _, bind := range binds {
+		result[bind.SrcHash+"-"+bind.DstHash] = true
+	}
+	return result
+}
+
+func TestAssets_ExplorerDao(t *testing.T) {
+	dao, db := newSqliteExplorerDao(t, "explorer_assets")
+	basics := []*models.NFTAssetBasic{
+		{
+			Name: "dog",
+			Assets: []*models.NFTAsset{
+				{Hash: "AA01", ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Name: "Dog"},
+				{Hash: "bb01", ChainId: basedef.BSC_CROSSCHAIN_ID, Name: "Dog"},
+				{Hash: "cc01", ChainId: basedef.HECO_CROSSCHAIN_ID, Name: "Dog"},
+			},
+		},
+		{
+			Name: "cat",
+			Assets: []*models.NFTAsset{
+				{Hash: "aa02", ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Name: "Cat"},
+				{Hash: "bb02", ChainId: basedef.BSC_CROSSCHAIN_ID, Name: "Cat"},
+			},
+		},
+	}
+	assert.NoError(t, dao.AddAssets(basics))
+	// adding twice keeps a single copy
+	assert.NoError(t, dao.AddAssets(basics))
+
+	tokens := make([]*Token, 0)
+	assert.NoError(t, db.Where("xdesc = ?", "dog").Order("id").Find(&tokens).Error)
+	assert.Equal(t, 3, len(tokens))
+	assert.Equal(t, "aa01", tokens[0].Hash)
+	assert.Equal(t, "erc721", tokens[0].Type)
+	assert.Equal(t, "Dog", tokens[0].Name)
+	binds := explorerBinds(t, db)
+	assert.Equal(t, 8, len(binds))
+	assert.True(t, binds["aa01-cc01"])
+	assert.True(t, binds["cc01-aa01"])
+	assert.True(t, binds["bb02-aa02"])
+	assert.False(t, binds["aa01-aa02"])
+
+	assert.NoError(t, dao.RemoveAssetMaps([]*models.NFTAssetMap{
+		{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcAssetHash: "AA01", DstChainId: basedef.HECO_CROSSCHAIN_ID, DstAssetHash: "cc01"},
+	}))
+	binds = explorerBinds(t, db)
+	assert.False(t, binds["aa01-cc01"])
+	assert.True(t, binds["cc01-aa01"])
+	// adding the assets again keeps the removed direction
+	assert.NoError(t, dao.AddAssets(basics))
+	binds = explorerBinds(t, db)
+	assert.Equal(t, 7, len(binds))
+	assert.False(t, binds["aa01-cc01"])
+	assert.NoError(t, dao.RestoreAssetMaps([]*models.NFTAssetMap{
+		{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcAssetHash: "AA01", DstChainId: basedef.HECO_CROSSCHAIN_ID, DstAssetHash: "cc01"},
+	}))
+	assert.NoError(t, dao.RestoreAssetMaps([]*models.NFTAssetMap{
+		{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcAssetHash: "AA01", DstChainId: basedef.HECO_CROSSCHAIN_ID, DstAssetHash: "cc01"},
+	}))
+	binds = explorerBinds(t, db)
+	assert.Equal(t, 8, len(binds))
+	assert.True(t, binds["aa01-cc01"])
+
+	// a disabled asset leaves the explorer with its binds
+	basics[0].Assets[2].Disable = basedef.ASSET_DISABLE
+	assert.NoError(t, dao.AddAssets(basics))
+	assert.NoError(t, db.Where("xdesc = ?", "dog").Order("id").Find(&tokens).Error)
+	assert.Equal(t, 2, len(tokens))
+	binds = explorerBinds(t, db)
+	assert.Equal(t, 4, len(binds))
+	assert.True(t, binds["aa01-bb01"])
+	assert.False(t, binds["cc01-aa01"])
+	basics[0].Assets[2].Disable = basedef.ASSET_ENABLE
+	assert.NoError(t, dao.AddAssets(basics))
+	assert.Equal(t, 8, len(explorerBinds(t, db)))
+
+	// so do the assets of a disabled basic
+	basics[1].Disable = basedef.ASSET_DISABLE
+	assert.NoError(t, dao.AddAssets(basics))
+	var catCount int64
+	assert.NoError(t, db.Model(&Token{}).Where("xdesc = ?", "cat").Count(&catCount).Error)
+	assert.Equal(t, int64(0), catCount)
+	assert.Equal(t, 6, len(explorerBinds(t, db)))
+	basics[1].Disable = basedef.ASSET_ENABLE
+	assert.NoError(t, dao.AddAssets(basics))
+
+	assert.NoError(t, dao.RemoveAssets([]string{"dog"}))
+	var count int64
+	assert.NoError(t, db.Model(&Token{}).Where("xdesc = ?", "dog").Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+	binds = explorerBinds(t, db)
+	assert.Equal(t, 2, len(binds))
+	assert.True(t, binds["aa02-bb02"])
+}
+
+func TestTokens_ExplorerDao(t *testing.T) {
+	dao, db := newSqliteExplorerDao(t, "explorer_tokens")
+	assert.NoError(t, db.Create(&Token{Id: basedef.ETHEREUM_CROSSCHAIN_ID, Hash: "aa03", Name: "USDT", Type: "erc20", Desc: "pet"}).Error)
+	assert.NoError(t, db.Create(&TokenBind{SrcHash: "aa03", DstHash: "aa03"}).Error)
+	assert.NoError(t, dao.AddAssets([]*models.NFTAssetBasic{
+		{Name: "pet", Assets: []*models.NFTAsset{{Hash: "aa04", ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Name: "Pet"}}},
+	}))
+
+	// the fungible token and the nft asset of the same name are removed separately
+	assert.NoError(t, dao.RemoveTokens([]string{"pet"}))
+	tokens := make([]*Token, 0)
+	assert.NoError(t, db.Find(&tokens).Error)
+	assert.Equal(t, 1, len(tokens))
+	assert.Equal(t, "aa04", tokens[0].Hash)
+	assert.Equal(t, 0, len(explorerBinds(t, db)))
+
+	assert.NoError(t, dao.RemoveAssets([]string{"pet"}))
+	assert.NoError(t, db.Find(&tokens).Error)
+	assert.Equal(t, 0, len(tokens))
+}
+
+func TestAddChains_ExplorerDao(t *testing.T) {
+	dao, db := newSqliteExplorerDao(t, "explorer_chains")
+	assert.NoError(t, db.Create(&Chain{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Name: "Ethereum", Height: 100, In: 5, Out: 6}).Error)
+	assert.NoError(t, dao.AddChains([]*models.Chain{
+		{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Height: 1},
+		{ChainId: basedef.BSC_CROSSCHAIN_ID, Height: 200},
+	}, nil))
+
+	chain, err := dao.GetChain(basedef.ETHEREUM_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), chain.Height)
+	explorerChain := new(Chain)
+	assert.NoError(t, db.Where("id = ?", basedef.ETHEREUM_CROSSCHAIN_ID).First(explorerChain).Error)
+	assert.Equal(t, uint64(5), explorerChain.In)
+
+	explorerChain = new(Chain)
+	assert.NoError(t, db.Where("id = ?", basedef.BSC_CROSSCHAIN_ID).First(explorerChain).Error)
+	assert.Equal(t, "BSC", explorerChain.Name)
+	assert.Equal(t, uint64(200), explorerChain.Height)
+}
@@ -143,3 +143,31 @@ func (dao *StakeDao) RemoveAssets(assets []string) error {
 func (dao *StakeDao) RemoveAssetMaps(assetMaps []*models.NFTAssetMap) error {
 	return nil
 }
+
+func (dao *StakeDao) RestoreAssetMaps(assetMaps []*models.NFTAssetMap) error {
+	return nil
+}
+
+func (dao *StakeDao) UpdateAssetBinds(assetBinds []*models.AssetBindEvent, proxyBinds []*models.ProxyBindEvent) error {
+	return nil
+}
+
+func (dao *StakeDao) UpdateGovernanceEvents(events []*models.GovernanceEvent) error {
+	return nil
+}
+
+func (dao *StakeDao) UpdateSpeedUps(speedUps []*models.WrapperSpeedUp) error {
+	return nil
+}
+
+func (dao *StakeDao) UpdateRelayFailures(failures []*models.DstRelayFailure) error {
+	return nil
+}
+
+func (dao *StakeDao) GetPendingAssets() ([]*models.NFTAsset, []*models.NFTAssetMap, error) {
+	return nil, nil, nil
+}
+
+func (dao *StakeDao) ApproveAssets(assets []string) error {
+	return nil
+}
@@ -20,96 +20,407 @@ package swapdao
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
 	"github.com/polynetwork/poly-nft-bridge/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func (dao *SwapDao) AddAssets(basics []*models.TokenBasic) error {
-	if basics != nil && len(basics) > 0 {
-		res := dao.db.Save(basics)
+// AddAssets creates or updates the asset basics and their assets, so BaseUri,
+// Name and the Disable flags follow the config, and completes the full mesh of
+// maps between the assets of every basic. A map is disabled when the basic or
+// one of its assets is, the maps removed by the operator or still pending keep
+// their state.
+func (dao *SwapDao) AddAssets(basics []*models.NFTAssetBasic) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		for _, basic := range basics {
+			if err := addAsset(tx, basic); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func addAsset(tx *gorm.DB, basic *models.NFTAssetBasic) error {
+	res := tx.Omit(clause.Associations).Save(&models.NFTAssetBasic{
+		Name:    basic.Name,
+		Time:    basic.Time,
+		Disable: basic.Disable,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	for _, asset := range basic.Assets {
+		res = tx.Omit(clause.Associations).Save(&models.NFTAsset{
+			Hash:           strings.ToLower(asset.Hash),
+			ChainId:        asset.ChainId,
+			Name:           asset.Name,
+			BaseUri:        asset.BaseUri,
+			AssetBasicName: basic.Name,
+			Disable:        asset.Disable,
+		})
 		if res.Error != nil {
 			return res.Error
 		}
-		if res.RowsAffected == 0 {
-			return fmt.Errorf("add basics failed!")
-		}
 	}
 
-	maps := getAssetMapsFromAsset(basics)
-	if maps != nil && len(maps) > 0 {
-		res := dao.db.Save(maps)
+	// the mesh also covers the assets added by former configs
+	assets := make([]*models.NFTAsset, 0)
+	res = tx.Where("asset_basic_name = ?", basic.Name).Find(&assets)
+	if res.Error != nil {
+		return res.Error
+	}
+	hashes := make([]string, 0)
+	for _, asset := range assets {
+		hashes = append(hashes, asset.Hash)
+	}
+	existing := make([]*models.NFTAssetMap, 0)
+	res = tx.Where("src_asset_hash in ? and dst_asset_hash in ?", hashes, hashes).Find(&existing)
+	if res.Error != nil {
+		return res.Error
+	}
+	index := make(map[models.NFTAssetMap]int64)
+	for _, mp := range existing {
+		index[assetMapKey(mp)] = mp.Disable
+	}
+
+	created := make([]*models.NFTAssetMap, 0)
+	for _, mp := range getAssetMapsFromAsset(basic, assets) {
+		disable, ok := index[assetMapKey(mp)]
+		if !ok {
+			created = append(created, mp)
+			continue
+		}
+		if disable == mp.Disable || disable == basedef.ASSET_REMOVED || disable == basedef.ASSET_PENDING {
+			continue
+		}
+		res = tx.Model(&models.NFTAssetMap{}).
+			Where("src_chain_id = ? and src_asset_hash = ? and dst_chain_id = ? and dst_asset_hash = ?",
+				mp.SrcChainId, mp.SrcAssetHash, mp.DstChainId, mp.DstAssetHash).
+			Update("disable", mp.Disable)
 		if res.Error != nil {
 			return res.Error
 		}
-		if res.RowsAffected == 0 {
-			return fmt.Errorf("add basics map failed!")
+	}
+	if len(created) > 0 {
+		res = tx.Omit(clause.Associations).Create(created)
+		if res.Error != nil {
+			return res.Error
 		}
 	}
 	return nil
 }
 
+func assetMapKey(mp *models.NFTAssetMap) models.NFTAssetMap {
+	return models.NFTAssetMap{
+		SrcChainId:   mp.SrcChainId,
+		SrcAssetHash: mp.SrcAssetHash,
+		DstChainId:   mp.DstChainId,
+		DstAssetHash: mp.DstAssetHash,
+	}
+}
+
+// RemoveAssets soft deletes the asset basics, their assets and every map from
+// or to the assets. Adding the basic again enables them, except the maps
+// removed by RemoveAssetMaps.
 func (dao *SwapDao) RemoveAssets(assets []string) error {
-	for _, asset := range assets {
-		if err := dao.RemoveAsset(asset); err != nil {
-			return err
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		for _, asset := range assets {
+			if err := removeAsset(tx, asset); err != nil {
+				return err
+			}
 		}
-	}
-	return nil
+		return nil
+	})
 }
 
-func (dao *SwapDao) RemoveAsset(name string) error {
-	basic := new(models.TokenBasic)
-	res := dao.db.Model(&models.TokenBasic{}).Where("name = ?", name).Preload("Tokens").First(basic)
+func removeAsset(tx *gorm.DB, name string) error {
+	basic := new(models.NFTAssetBasic)
+	res := tx.Where("name = ?", name).Preload("Assets").Limit(1).Find(basic)
 	if res.Error != nil {
 		return res.Error
 	}
-
-	basics := []*models.TokenBasic{basic}
-	maps := getAssetMapsFromAsset(basics)
-	for _, mp := range maps {
-		dao.db.Where("src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and dst_token_hash = ?",
-			mp.SrcChainId,
-			strings.ToLower(mp.SrcTokenHash),
-			mp.DstChainId,
-			strings.ToLower(mp.DstTokenHash),
-		).Delete(&models.TokenMap{})
+	if res.RowsAffected == 0 {
+		return nil
 	}
-	for _, asset := range basic.Tokens {
-		dao.db.Where("hash = ? and chain_id = ?", asset.Hash, asset.ChainId).Delete(&models.Token{})
+	for _, asset := range basic.Assets {
+		res = tx.Model(&models.NFTAssetMap{}).
+			Where("((src_chain_id = ? and src_asset_hash = ?) or (dst_chain_id = ? and dst_asset_hash = ?)) and disable <> ?",
+				asset.ChainId, asset.Hash, asset.ChainId, asset.Hash, basedef.ASSET_REMOVED).
+			Update("disable", basedef.ASSET_DISABLE)
+		if res.Error != nil {
+			return res.Error
+		}
 	}
-	dao.db.Where("name = ?", basic.Name).Delete(&models.NFTToken{})
-	return nil
+	res = tx.Model(&models.NFTAsset{}).Where("asset_basic_name = ?", basic.Name).Update("disable", basedef.ASSET_DISABLE)
+	if res.Error != nil {
+		return res.Error
+	}
+	return tx.Model(&models.NFTAssetBasic{}).Where("name = ?", basic.Name).Update("disable", basedef.ASSET_DISABLE).Error
 }
 
-func (dao *SwapDao) RemoveAssetMaps(maps []*models.TokenMap) error {
+// RemoveAssetMaps removes single directions of the mesh, they stay removed
+// when the assets are added again until RestoreAssetMaps.
+func (dao *SwapDao) RemoveAssetMaps(maps []*models.NFTAssetMap) error {
 	for _, mp := range maps {
-		dao.db.Model(&models.TokenMap{}).
-			Where("src_chain_id = ? and src_token_hash = ? and dst_chain_id = ? and dst_token_hash = ?",
+		res := dao.db.Model(&models.NFTAssetMap{}).
+			Where("src_chain_id = ? and src_asset_hash = ? and dst_chain_id = ? and dst_asset_hash = ?",
 				mp.SrcChainId,
-				strings.ToLower(mp.SrcTokenHash),
+				strings.ToLower(mp.SrcAssetHash),
 				mp.DstChainId,
-				strings.ToLower(mp.DstTokenHash),
-			).Update("property", 0)
+				strings.ToLower(mp.DstAssetHash),
+			).Update("disable", basedef.ASSET_REMOVED)
+		if res.Error != nil {
+			return res.Error
+		}
 	}
 	return nil
 }
 
-func getAssetMapsFromAsset(basics []*models.TokenBasic) []*models.TokenMap {
-	maps := make([]*models.TokenMap, 0)
-	for _, basic := range basics {
-		for _, src := range basic.Tokens {
-			for _, dst := range basic.Tokens {
-				if dst.ChainId != src.ChainId {
-					maps = append(maps, &models.TokenMap{
-						SrcChainId:   src.ChainId,
-						SrcTokenHash: src.Hash,
-						DstChainId:   dst.ChainId,
-						DstTokenHash: dst.Hash,
-						Property:     1,
-					})
-				}
+// RestoreAssetMaps puts the removed directions back into the mesh, enabled
+// when the basic and both assets are.
+func (dao *SwapDao) RestoreAssetMaps(maps []*models.NFTAssetMap) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		for _, mp := range maps {
+			removed := new(models.NFTAssetMap)
+			res := tx.Where("src_chain_id = ? and src_asset_hash = ? and dst_chain_id = ? and dst_asset_hash = ? and disable = ?",
+				mp.SrcChainId,
+				strings.ToLower(mp.SrcAssetHash),
+				mp.DstChainId,
+				strings.ToLower(mp.DstAssetHash),
+				basedef.ASSET_REMOVED,
+			).Preload("SrcAsset.AssetBasic").Preload("DstAsset").Limit(1).Find(removed)
+			if res.Error != nil {
+				return res.Error
 			}
+			if res.RowsAffected == 0 {
+				continue
+			}
+			var disable int64 = basedef.ASSET_DISABLE
+			src, dst := removed.SrcAsset, removed.DstAsset
+			if src != nil && dst != nil && src.AssetBasic != nil &&
+				src.AssetBasic.Disable == basedef.ASSET_ENABLE && src.Disable == basedef.ASSET_ENABLE && dst.Disable == basedef.ASSET_ENABLE {
+				disable = basedef.ASSET_ENABLE
+			}
+			res = tx.Model(&models.NFTAssetMap{}).
+				Where("src_chain_id = ? and src_asset_hash = ? and dst_chain_id = ? and dst_asset_hash = ?",
+					removed.SrcChainId, removed.SrcAssetHash, removed.DstChainId, removed.DstAssetHash).
+				Update("disable", disable)
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+		return nil
+	})
+}
+
+func getAssetMapsFromAsset(basic *models.NFTAssetBasic, assets []*models.NFTAsset) []*models.NFTAssetMap {
+	maps := make([]*models.NFTAssetMap, 0)
+	for _, src := range assets {
+		for _, dst := range assets {
+			if dst.ChainId == src.ChainId {
+				continue
+			}
+			var disable int64 = basedef.ASSET_ENABLE
+			if basic.Disable != basedef.ASSET_ENABLE || src.Disable != basedef.ASSET_ENABLE || dst.Disable != basedef.ASSET_ENABLE {
+				disable = basedef.ASSET_DISABLE
+			}
+			maps = append(maps, &models.NFTAssetMap{
+				SrcChainId:   src.ChainId,
+				SrcAssetHash: src.Hash,
+				DstChainId:   dst.ChainId,
+				DstAssetHash: dst.Hash,
+				Disable:      disable,
+			})
 		}
 	}
 	return maps
 }
+
+// UpdateAssetBinds saves the bindings found on chain as pending assets and
+// maps, existing rows keep their state. The proxy keeps a single asset per
+// target chain, so the maps to the former target are disabled.
+func (dao *SwapDao) UpdateAssetBinds(assetBinds []*models.AssetBindEvent, proxyBinds []*models.ProxyBindEvent) error {
+	if len(assetBinds) == 0 && len(proxyBinds) == 0 {
+		return nil
+	}
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		for _, bind := range proxyBinds {
+			res := tx.Save(&models.NFTProxyBind{
+				ChainId:   bind.ChainId,
+				ToChainId: bind.ToChainId,
+				ProxyHash: strings.ToLower(bind.ProxyHash),
+				TxHash:    bind.TxHash,
+				Height:    bind.Height,
+			})
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+		for _, bind := range assetBinds {
+			if err := bindAsset(tx, bind); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (dao *SwapDao) UpdateGovernanceEvents(events []*models.GovernanceEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	res := dao.db.Clauses(clause.OnConflict{DoNothing: true}).Create(events)
+	return res.Error
+}
+
+func bindAsset(tx *gorm.DB, bind *models.AssetBindEvent) error {
+	hash, toHash := strings.ToLower(bind.AssetHash), strings.ToLower(bind.ToAssetHash)
+	src, err := findAsset(tx, bind.ChainId, hash)
+	if err != nil {
+		return err
+	}
+	if src == nil {
+		// a discovered asset never joins an existing basic by itself, the
+		// operator merges the basics with asset_tool after the review
+		basicName := pendingBasicName(bind.Symbol, bind.ChainId, hash)
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.NFTAssetBasic{
+			Name:    basicName,
+			Time:    time.Now().Unix(),
+			Disable: basedef.ASSET_PENDING,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		res = tx.Omit(clause.Associations).Create(&models.NFTAsset{
+			Hash:           hash,
+			ChainId:        bind.ChainId,
+			Name:           bind.Name,
+			BaseUri:        bind.BaseUri,
+			AssetBasicName: basicName,
+			Disable:        basedef.ASSET_PENDING,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+
+	res := tx.Model(&models.NFTAssetMap{}).
+		Where("src_chain_id = ? and src_asset_hash = ? and dst_chain_id = ? and dst_asset_hash <> ?",
+			bind.ChainId, hash, bind.ToChainId, toHash).
+		Update("disable", basedef.ASSET_DISABLE)
+	if res.Error != nil {
+		return res.Error
+	}
+	if toHash == "" {
+		return nil
+	}
+	return tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.NFTAssetMap{
+		SrcChainId:   bind.ChainId,
+		SrcAssetHash: hash,
+		DstChainId:   bind.ToChainId,
+		DstAssetHash: toHash,
+		Disable:      basedef.ASSET_PENDING,
+	}).Error
+}
+
+// pendingBasicName is unique to the asset and fits the basic name column, the
+// symbol is cut when it is too long.
+func pendingBasicName(symbol string, chainId uint64, hash string) string {
+	suffix := fmt.Sprintf("%d-%s", chainId, hash)
+	if symbol == "" {
+		return suffix
+	}
+	runes := []rune(symbol)
+	if max := 64 - len(suffix) - 1; len(runes) > max {
+		if max <= 0 {
+			return suffix
+		}
+		runes = runes[:max]
+	}
+	return string(runes) + "-" + suffix
+}
+
+func findAsset(tx *gorm.DB, chainId uint64, hash string) (*models.NFTAsset, error) {
+	asset := new(models.NFTAsset)
+	res := tx.Where("hash = ? and chain_id = ?", hash, chainId).Limit(1).Find(asset)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return asset, nil
+}
+
+func (dao *SwapDao) GetPendingAssets() ([]*models.NFTAsset, []*models.NFTAssetMap, error) {
+	assets := make([]*models.NFTAsset, 0)
+	res := dao.db.Where("disable = ?", basedef.ASSET_PENDING).Find(&assets)
+	if res.Error != nil {
+		return nil, nil, res.Error
+	}
+	maps := make([]*models.NFTAssetMap, 0)
+	res = dao.db.Where("disable = ?", basedef.ASSET_PENDING).
+		Preload("SrcAsset").
+		Preload("DstAsset").
+		Find(&maps)
+	if res.Error != nil {
+		return nil, nil, res.Error
+	}
+	return assets, maps, nil
+}
+
+// ApproveAssets enables the pending basics and their pending assets, and the
+// pending maps from them whose target asset is enabled. The maps to an asset
+// which is not discovered yet stay pending until it is approved.
+func (dao *SwapDao) ApproveAssets(assets []string) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		for _, asset := range assets {
+			if err := approveAsset(tx, asset); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func approveAsset(tx *gorm.DB, name string) error {
+	res := tx.Model(&models.NFTAssetBasic{}).
+		Where("name = ? and disable = ?", name, basedef.ASSET_PENDING).
+		Update("disable", basedef.ASSET_ENABLE)
+	if res.Error != nil {
+		return res.Error
+	}
+	res = tx.Model(&models.NFTAsset{}).
+		Where("asset_basic_name = ? and disable = ?", name, basedef.ASSET_PENDING).
+		Update("disable", basedef.ASSET_ENABLE)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	pending := make([]*models.NFTAssetMap, 0)
+	res = tx.Where("disable = ?", basedef.ASSET_PENDING).Preload("SrcAsset").Preload("DstAsset").Find(&pending)
+	if res.Error != nil {
+		return res.Error
+	}
+	for _, mp := range pending {
+		src, dst := mp.SrcAsset, mp.DstAsset
+		if src == nil || dst == nil || src.Disable != basedef.ASSET_ENABLE || dst.Disable != basedef.ASSET_ENABLE {
+			continue
+		}
+		if src.AssetBasicName != name && dst.AssetBasicName != name {
+			continue
+		}
+		res = tx.Model(&models.NFTAssetMap{}).
+			Where("src_chain_id = ? and src_asset_hash = ? and dst_chain_id = ? and dst_asset_hash = ?",
+				mp.SrcChainId, mp.SrcAssetHash, mp.DstChainId, mp.DstAssetHash).
+			Update("disable", basedef.ASSET_ENABLE)
+		if res.Error != nil {
+			return res.Error
+		}
+	}
+	return nil
+}
@@ -19,14 +19,16 @@ package swapdao
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/polynetwork/poly-nft-bridge/conf"
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/dao/migration"
 	"github.com/polynetwork/poly-nft-bridge/models"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"gorm.io/gorm/clause"
 )
 
 type SwapDao struct {
@@ -40,22 +42,12 @@ func NewSwapDao(dbCfg *conf.DBConfig, backup bool) *SwapDao {
 		dbCfg:  dbCfg,
 		backup: backup,
 	}
-	Logger := logger.Default
-	if dbCfg.Debug == true {
-		Logger = Logger.LogMode(logger.Info)
-	}
-	db, err := gorm.Open(mysql.Open(dbCfg.User+":"+dbCfg.Password+"@tcp("+dbCfg.URL+")/"+
-		dbCfg.Scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
-	if err != nil {
-		panic(err)
-	}
+	db := dbopen.MustOpen(dbCfg)
 
-	if err := db.AutoMigrate(
-	//&models.NFTAssetBasic{},
-	//&models.NFTAsset{},
-	//&models.NFTAssetMap{},
-	); err != nil {
-		panic(err)
+	if !backup {
+		if _, err := migration.Up(db, 0); err != nil {
+			panic(err)
+		}
 	}
 
 	swapDao.db = db
@@ -71,6 +63,13 @@ func (dao *SwapDao) UpdateEvents(
 ) error {
 
 	if wrapperTransactions != nil && len(wrapperTransactions) > 0 {
+		for _, wrapperTransaction := range wrapperTransactions {
+			totalFee, err := wrapperTotalFee(dao.db, wrapperTransaction)
+			if err != nil {
+				return err
+			}
+			wrapperTransaction.TotalFee = totalFee
+		}
 		res := dao.db.Save(wrapperTransactions)
 		if res.Error != nil {
 			return res.Error
@@ -103,15 +102,86 @@ func (dao *SwapDao) UpdateEvents(
 	return nil
 }
 
+// UpdateSpeedUps saves the speed ups and counts the total fee of the wrapper
+// transactions again, a speed up of a wrapper transaction which is not saved yet
+// is counted when it is saved.
+func (dao *SwapDao) UpdateSpeedUps(speedUps []*models.WrapperSpeedUp) error {
+	if len(speedUps) == 0 {
+		return nil
+	}
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(speedUps)
+		if res.Error != nil {
+			return res.Error
+		}
+		counted := make(map[string]bool)
+		for _, speedUp := range speedUps {
+			if counted[speedUp.SrcHash] {
+				continue
+			}
+			counted[speedUp.SrcHash] = true
+			wrapperTransaction := new(models.WrapperTransaction)
+			res := tx.Where("hash = ?", speedUp.SrcHash).Limit(1).Find(wrapperTransaction)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				continue
+			}
+			totalFee, err := wrapperTotalFee(tx, wrapperTransaction)
+			if err != nil {
+				return err
+			}
+			res = tx.Model(&models.WrapperTransaction{}).Where("hash = ?", wrapperTransaction.Hash).Update("total_fee", totalFee)
+			if res.Error != nil {
+				return res.Error
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateRelayFailures saves the reverted relays, a block listened again saves
+// nothing new.
+func (dao *SwapDao) UpdateRelayFailures(failures []*models.DstRelayFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return dao.db.Clauses(clause.OnConflict{DoNothing: true}).Create(failures).Error
+}
+
+// wrapperTotalFee is the fee amount of the wrapper transaction plus its saved
+// speed ups, a speed up paid in another token is not counted.
+func wrapperTotalFee(db *gorm.DB, wrapperTransaction *models.WrapperTransaction) (*models.BigInt, error) {
+	total := new(big.Int)
+	if wrapperTransaction.FeeAmount != nil {
+		total.Set(&wrapperTransaction.FeeAmount.Int)
+	}
+	speedUps := make([]*models.WrapperSpeedUp, 0)
+	res := db.Where("src_hash = ?", wrapperTransaction.Hash).Find(&speedUps)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	for _, speedUp := range speedUps {
+		if speedUp.FeeTokenHash != wrapperTransaction.FeeTokenHash || speedUp.FeeAmount == nil {
+			continue
+		}
+		total.Add(total, &speedUp.FeeAmount.Int)
+	}
+	return models.NewBigInt(total), nil
+}
+
 func (dao *SwapDao) RemoveEvents(srcHashes []string, polyHashes []string, dstHashes []string) error {
-	dao.db.Where("`tx_hash` in ?", srcHashes).Delete(&models.SrcTransfer{})
-	dao.db.Where("`hash` in ?", srcHashes).Delete(&models.SrcTransaction{})
-	dao.db.Where("`hash` in ?", srcHashes).Delete(&models.WrapperTransaction{})
+	dao.db.Where("tx_hash in ?", srcHashes).Delete(&models.SrcTransfer{})
+	dao.db.Where("hash in ?", srcHashes).Delete(&models.SrcTransaction{})
+	dao.db.Where("hash in ?", srcHashes).Delete(&models.WrapperTransaction{})
+	dao.db.Where("src_hash in ?", srcHashes).Delete(&models.WrapperSpeedUp{})
 
-	dao.db.Where("`hash` in ?", polyHashes).Delete(&models.PolyTransaction{})
+	dao.db.Where("hash in ?", polyHashes).Delete(&models.PolyTransaction{})
+	dao.db.Where("poly_hash in ?", polyHashes).Delete(&models.DstRelayFailure{})
 
-	dao.db.Where("`tx_hash` in ?", dstHashes).Delete(&models.DstTransfer{})
-	dao.db.Where("`hash` in ?", dstHashes).Delete(&models.DstTransaction{})
+	dao.db.Where("tx_hash in ?", dstHashes).Delete(&models.DstTransfer{})
+	dao.db.Where("hash in ?", dstHashes).Delete(&models.DstTransaction{})
 	return nil
 }
 
@@ -40,6 +40,13 @@ type CrossChainDao interface {
 	AddAssets(assetBasics []*models.NFTAssetBasic) error
 	RemoveAssets(assets []string) error
 	RemoveAssetMaps(assetMaps []*models.NFTAssetMap) error
+	RestoreAssetMaps(assetMaps []*models.NFTAssetMap) error
+	UpdateAssetBinds(assetBinds []*models.AssetBindEvent, proxyBinds []*models.ProxyBindEvent) error
+	GetPendingAssets() ([]*models.NFTAsset, []*models.NFTAssetMap, error)
+	ApproveAssets(assets []string) error
+	UpdateGovernanceEvents(events []*models.GovernanceEvent) error
+	UpdateSpeedUps(speedUps []*models.WrapperSpeedUp) error
+	UpdateRelayFailures(failures []*models.DstRelayFailure) error
 }
 
 func NewCrossChainDao(server string, backup bool, dbCfg *conf.DBConfig) CrossChainDao {
@@ -81,7 +81,7 @@ func TestCrossChainSrc_ExplorerDao(t *testing.T) {
 	if config == nil {
 		panic("read config failed!")
 	}
-	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_EXPLORER, config.DBConfig)
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_EXPLORER, false, config.DBConfig)
 	if dao == nil {
 		panic("server is not valid")
 	}
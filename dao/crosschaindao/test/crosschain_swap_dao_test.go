@@ -42,7 +42,7 @@ func TestCrossChain_SwapDao(t *testing.T) {
 	if config == nil {
 		panic("read config failed!")
 	}
-	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_SWAP, config.DBConfig)
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_SWAP, false, config.DBConfig)
 	if dao == nil {
 		panic("server is not valid")
 	}
@@ -86,7 +86,7 @@ func TestCrossChainSrc_SwapDao(t *testing.T) {
 	if config == nil {
 		panic("read config failed!")
 	}
-	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_SWAP, config.DBConfig)
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_SWAP, false, config.DBConfig)
 	if dao == nil {
 		panic("server is not valid")
 	}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dbopen
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	DriverMysql    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSqlite   = "sqlite"
+)
+
+// Open connects to the database described by dbCfg. For sqlite the URL is the
+// database file, ":memory:" or a "file:" uri.
+func Open(dbCfg *conf.DBConfig) (*gorm.DB, error) {
+	Logger := logger.Default
+	if dbCfg.Debug == true {
+		Logger = Logger.LogMode(logger.Info)
+	}
+	dialector, err := Dialector(dbCfg)
+	if err != nil {
+		return nil, err
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: Logger})
+	if err != nil {
+		return nil, err
+	}
+	if driver(dbCfg) == DriverSqlite {
+		db.ClauseBuilders["WHERE"] = sqliteWhere
+		// sqlite serializes writers, and every connection to ":memory:" is a new database
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
+
+// MustOpen is Open for the daos, which panic when the database is unreachable.
+func MustOpen(dbCfg *conf.DBConfig) *gorm.DB {
+	db, err := Open(dbCfg)
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+func Dialector(dbCfg *conf.DBConfig) (gorm.Dialector, error) {
+	switch driver(dbCfg) {
+	case DriverMysql:
+		return mysql.Open(dbCfg.User + ":" + dbCfg.Password + "@tcp(" + dbCfg.URL + ")/" +
+			dbCfg.Scheme + "?charset=utf8"), nil
+	case DriverPostgres:
+		dsn := dbCfg.URL
+		if !strings.Contains(dsn, "://") {
+			u := &url.URL{
+				Scheme:   "postgres",
+				User:     url.UserPassword(dbCfg.User, dbCfg.Password),
+				Host:     dbCfg.URL,
+				Path:     "/" + dbCfg.Scheme,
+				RawQuery: "sslmode=disable",
+			}
+			dsn = u.String()
+		}
+		return &postgresDialector{postgres.Open(dsn).(*postgres.Dialector)}, nil
+	case DriverSqlite:
+		return sqlite.Open(dbCfg.URL), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", dbCfg.Driver)
+	}
+}
+
+func driver(dbCfg *conf.DBConfig) string {
+	if dbCfg.Driver == "" {
+		return DriverMysql
+	}
+	return strings.ToLower(dbCfg.Driver)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dbopen
+
+import (
+	"regexp"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// the models declare mysql display widths such as bigint(20), which postgres rejects
+var displayWidth = regexp.MustCompile(`^(bigint|int|integer|smallint)\(\d+\)`)
+
+type postgresDialector struct {
+	*postgres.Dialector
+}
+
+func (dialector postgresDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return postgres.Migrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   dialector,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+func (dialector postgresDialector) DataTypeOf(field *schema.Field) string {
+	return displayWidth.ReplaceAllString(dialector.Dialector.DataTypeOf(field), "$1")
+}
+
+// rowValuesIN is a multi column IN. sqlite only accepts a sub query on the
+// right side of a row value IN, so the values are given as a VALUES list.
+type rowValuesIN clause.IN
+
+func (in rowValuesIN) Build(builder clause.Builder) {
+	builder.WriteQuoted(in.Column)
+	builder.WriteString(" IN (VALUES ")
+	builder.AddVar(builder, in.Values...)
+	builder.WriteByte(')')
+}
+
+// sqliteWhere rewrites the multi column IN conditions built by gorm preloads
+// of composite foreign keys.
+func sqliteWhere(c clause.Clause, builder clause.Builder) {
+	if where, ok := c.Expression.(clause.Where); ok {
+		exprs := make([]clause.Expression, len(where.Exprs))
+		for idx, expr := range where.Exprs {
+			if in, ok := expr.(clause.IN); ok {
+				if _, ok := in.Column.([]clause.Column); ok && len(in.Values) > 0 {
+					expr = rowValuesIN(in)
+				}
+			}
+			exprs[idx] = expr
+		}
+		c.Expression = clause.Where{Exprs: exprs}
+	}
+	c.Builder = nil
+	c.Build(builder)
+}
@@ -0,0 +1,288 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Migration is one versioned step of the schema. Up and Down run in a
+// transaction together with the bookkeeping in schema_migrations, but note that
+// mysql commits ddl statements implicitly, so steps should be safe to re-run.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+type SchemaMigration struct {
+	Version   uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	Name      string `gorm:"size:64;not null"`
+	AppliedAt int64  `gorm:"type:bigint(20);not null"`
+}
+
+type MigrationStatus struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	AppliedAt int64
+}
+
+var migrations = make([]*Migration, 0)
+
+func register(migration *Migration) {
+	for _, m := range migrations {
+		if m.Version == migration.Version {
+			panic(fmt.Sprintf("duplicate migration version %d", migration.Version))
+		}
+	}
+	migrations = append(migrations, migration)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+}
+
+func Migrations() []*Migration {
+	return migrations
+}
+
+func Latest() uint64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+func applied(db *gorm.DB) (map[uint64]*SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+	records := make([]*SchemaMigration, 0)
+	res := db.Find(&records)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	versions := make(map[uint64]*SchemaMigration)
+	for _, record := range records {
+		versions[record.Version] = record
+	}
+	return versions, nil
+}
+
+func Status(db *gorm.DB) ([]*MigrationStatus, error) {
+	versions, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]*MigrationStatus, 0)
+	for _, m := range migrations {
+		s := &MigrationStatus{Version: m.Version, Name: m.Name}
+		if record, ok := versions[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = record.AppliedAt
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// Pending returns the migrations not applied yet.
+func Pending(db *gorm.DB) ([]*Migration, error) {
+	versions, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]*Migration, 0)
+	for _, m := range migrations {
+		if _, ok := versions[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// advisoryLock is taken around Up and Down, every listener and bridge_http
+// migrates at start and they must not run the same steps at once. The lock
+// statement tries once and selects 1 when the lock is taken by the session.
+type advisoryLock struct {
+	lock   string
+	unlock string
+}
+
+var (
+	advisoryLocks = map[string]*advisoryLock{
+		"mysql": {
+			lock:   "SELECT GET_LOCK('poly_nft_bridge_migration', 0)",
+			unlock: "SELECT RELEASE_LOCK('poly_nft_bridge_migration')",
+		},
+		"postgres": {
+			lock:   "SELECT CAST(pg_try_advisory_lock(7083270713) AS INTEGER)",
+			unlock: "SELECT pg_advisory_unlock(7083270713)",
+		},
+	}
+	lockTimeout  = 10 * time.Minute
+	lockInterval = time.Second
+)
+
+// withLock runs migrate holding the advisory lock of the dialect, sqlite has
+// none and is only used by one process.
+func withLock(db *gorm.DB, migrate func() error) error {
+	lock, ok := advisoryLocks[db.Dialector.Name()]
+	if !ok {
+		return migrate()
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	// the lock belongs to the session, so it is taken and released on one
+	// connection while the migrations run on the others
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		var taken sql.NullInt64
+		if err := conn.QueryRowContext(context.Background(), lock.lock).Scan(&taken); err != nil {
+			return fmt.Errorf("take migration lock: %v", err)
+		}
+		if taken.Valid && taken.Int64 == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("migration lock is held by another process for %s", lockTimeout.String())
+		}
+		time.Sleep(lockInterval)
+	}
+	defer conn.ExecContext(context.Background(), lock.unlock)
+	return migrate()
+}
+
+// Up applies the pending migrations up to version target, 0 means the latest.
+func Up(db *gorm.DB, target uint64) (done []*Migration, err error) {
+	err = withLock(db, func() error {
+		done, err = up(db, target)
+		return err
+	})
+	return
+}
+
+func up(db *gorm.DB, target uint64) ([]*Migration, error) {
+	pending, err := Pending(db)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]*Migration, 0)
+	for _, m := range pending {
+		if target != 0 && m.Version > target {
+			break
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now().Unix()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s up: %v", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations.
+func Down(db *gorm.DB, steps int) (done []*Migration, err error) {
+	err = withLock(db, func() error {
+		done, err = down(db, steps)
+		return err
+	})
+	return
+}
+
+func down(db *gorm.DB, steps int) ([]*Migration, error) {
+	versions, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]*Migration, 0)
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		m := migrations[i]
+		if _, ok := versions[m.Version]; !ok {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, "version = ?", m.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s down: %v", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// createTables creates the missing tables, and the missing indexes of the
+// tables which were created by AutoMigrate before there were migrations.
+func createTables(tx *gorm.DB, tables ...interface{}) error {
+	for _, table := range tables {
+		if !tx.Migrator().HasTable(table) {
+			if err := tx.Migrator().CreateTable(table); err != nil {
+				return err
+			}
+			continue
+		}
+		s, err := schema.Parse(table, &sync.Map{}, tx.NamingStrategy)
+		if err != nil {
+			return err
+		}
+		for name := range s.ParseIndexes() {
+			if tx.Migrator().HasIndex(table, name) {
+				continue
+			}
+			if err := tx.Migrator().CreateIndex(table, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func dropTables(tx *gorm.DB, tables ...interface{}) error {
+	for i := len(tables) - 1; i >= 0; i-- {
+		if err := tx.Migrator().DropTable(tables[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := dbopen.Open(&conf.DBConfig{Driver: dbopen.DriverSqlite, URL: ":memory:"})
+	assert.NoError(t, err)
+	db.Logger = logger.Discard
+	return db
+}
+
+func TestRegisterOrder(t *testing.T) {
+	for i := 1; i < len(migrations); i++ {
+		assert.Less(t, migrations[i-1].Version, migrations[i].Version)
+	}
+	assert.Equal(t, migrations[len(migrations)-1].Version, Latest())
+	assert.Panics(t, func() {
+		register(&Migration{Version: migrations[0].Version})
+	})
+}
+
+func TestUpDown(t *testing.T) {
+	db := openTestDB(t)
+
+	done, err := Up(db, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations), len(done))
+	for _, table := range tablesV1 {
+		assert.True(t, db.Migrator().HasTable(table))
+	}
+	assert.True(t, db.Migrator().HasIndex(&srcTransferV1{}, "idx_src_transfers_from"))
+	assert.True(t, db.Migrator().HasIndex(&srcTransferV1{}, "idx_src_transfers_dst_user"))
+	assert.True(t, db.Migrator().HasIndex(&polyTransactionV1{}, "idx_poly_transactions_src_hash"))
+	assert.True(t, db.Migrator().HasIndex(&dstTransactionV1{}, "idx_dst_transactions_poly_hash"))
+
+	// the current models fit the schema
+	assert.NoError(t, db.Create(&models.SrcTransfer{TxHash: "aa", From: "bb", Amount: models.NewBigIntFromInt(1)}).Error)
+	assert.NoError(t, db.Create(&models.NFTAsset{Hash: "aa", ChainId: 2, Name: "dog", AssetBasicName: "dog"}).Error)
+	assert.NoError(t, db.Create(&models.WrapperTransaction{Hash: "aa", FeeAmount: models.NewBigIntFromInt(1), TotalFee: models.NewBigIntFromInt(2)}).Error)
+	assert.NoError(t, db.Create(&models.WrapperSpeedUp{TxHash: "bb", SrcHash: "aa", FeeAmount: models.NewBigIntFromInt(1)}).Error)
+	assert.NoError(t, db.Create(&models.PolyTransaction{Hash: "aa", Fee: models.NewBigIntFromInt(0),
+		CrossChainArgs: models.CrossChainArgs{Method: "unlock", ArgsTokenId: "1"}}).Error)
+	assert.NoError(t, db.Create(&models.DstRelayFailure{TxHash: "bb", PolyHash: "aa", Fee: models.NewBigIntFromInt(1)}).Error)
+	assert.NoError(t, db.Create(&models.DstTransaction{Hash: "aa", Fee: models.NewBigIntFromInt(0), UnlockChecked: basedef.UNLOCK_CHECKED}).Error)
+
+	done, err = Up(db, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(done))
+
+	status, err := Status(db)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations), len(status))
+	for _, s := range status {
+		assert.True(t, s.Applied)
+		assert.NotZero(t, s.AppliedAt)
+	}
+
+	done, err = Down(db, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(done))
+	assert.Equal(t, Latest(), done[0].Version)
+	pending, err := Pending(db)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(pending))
+
+	done, err = Down(db, len(migrations))
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations)-1, len(done))
+	for _, table := range tablesV1 {
+		assert.False(t, db.Migrator().HasTable(table))
+	}
+
+	done, err = Up(db, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(done))
+	assert.True(t, db.Migrator().HasTable(&chainV1{}))
+}
+
+func TestUpOverAutoMigrate(t *testing.T) {
+	db := openTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&models.Chain{}, &models.SrcTransfer{}, &models.PolyTransaction{}))
+	assert.NoError(t, db.Create(&models.Chain{ChainId: 2, Height: 100}).Error)
+	assert.False(t, db.Migrator().HasIndex(&srcTransferV1{}, "idx_src_transfers_from"))
+
+	_, err := Up(db, 1)
+	assert.NoError(t, err)
+	assert.True(t, db.Migrator().HasIndex(&srcTransferV1{}, "idx_src_transfers_from"))
+	assert.True(t, db.Migrator().HasIndex(&polyTransactionV1{}, "idx_poly_transactions_src_hash"))
+
+	chain := new(models.Chain)
+	assert.NoError(t, db.Where("chain_id = ?", 2).First(chain).Error)
+	assert.Equal(t, uint64(100), chain.Height)
+}
+
+func TestUnlockCheckedBackfill(t *testing.T) {
+	db := openTestDB(t)
+	_, err := Up(db, 9)
+	assert.NoError(t, err)
+	now := uint64(time.Now().Unix())
+	assert.NoError(t, db.Create(&dstTransactionV1{Hash: "aa", Time: now - 2*unlockCheckedBackfillV10, Fee: models.NewBigIntFromInt(0)}).Error)
+	assert.NoError(t, db.Create(&dstTransactionV1{Hash: "bb", Time: now - 60, Fee: models.NewBigIntFromInt(0)}).Error)
+
+	_, err = Up(db, 10)
+	assert.NoError(t, err)
+	checked := make(map[string]int64)
+	txs := make([]*models.DstTransaction, 0)
+	assert.NoError(t, db.Find(&txs).Error)
+	for _, tx := range txs {
+		checked[tx.Hash] = tx.UnlockChecked
+	}
+	assert.Equal(t, map[string]int64{"aa": basedef.UNLOCK_CHECKED, "bb": basedef.UNLOCK_UNCHECKED}, checked)
+}
+
+func TestFailedUp(t *testing.T) {
+	db := openTestDB(t)
+	_, err := Up(db, 0)
+	assert.NoError(t, err)
+
+	saved := migrations
+	defer func() { migrations = saved }()
+	migrations = append(append([]*Migration{}, saved...), &Migration{
+		Version: Latest() + 1,
+		Name:    "broken",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("alter table nothing add column x int").Error
+		},
+	})
+	done, err := Up(db, 0)
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(done))
+	pending, err := Pending(db)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(pending))
+}
+
+func TestAdvisoryLock(t *testing.T) {
+	db, err := dbopen.Open(&conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:migration_lock?mode=memory&cache=shared"})
+	assert.NoError(t, err)
+	db.Logger = logger.Discard
+	// the lock holds a connection of its own
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(2)
+
+	savedTimeout, savedInterval := lockTimeout, lockInterval
+	defer func() {
+		delete(advisoryLocks, "sqlite")
+		lockTimeout, lockInterval = savedTimeout, savedInterval
+	}()
+	lockTimeout, lockInterval = 50*time.Millisecond, 10*time.Millisecond
+
+	advisoryLocks["sqlite"] = &advisoryLock{lock: "SELECT 0", unlock: "SELECT 1"}
+	done, err := Up(db, 0)
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(done))
+	pending, err := Pending(db)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations), len(pending))
+
+	advisoryLocks["sqlite"] = &advisoryLock{lock: "SELECT 1", unlock: "SELECT 1"}
+	done, err = Up(db, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations), len(done))
+	done, err = Down(db, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(done))
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"gorm.io/gorm"
+)
+
+// The tables as they were when migrations were introduced. Relations are left
+// out, so no foreign keys are created, and the join columns of the transaction
+// queries are indexed.
+
+type chainV1 struct {
+	ChainId             uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	Height              uint64 `gorm:"type:bigint(20);not null"`
+	BackwardBlockNumber uint64 `gorm:"type:bigint(20);not null"`
+}
+
+func (chainV1) TableName() string { return "chains" }
+
+type wrapperTransactionV1 struct {
+	Hash         string         `gorm:"primaryKey;size:66;not null"`
+	User         string         `gorm:"type:varchar(66);not null"`
+	SrcChainId   uint64         `gorm:"type:bigint(20);not null"`
+	BlockHeight  uint64         `gorm:"type:bigint(20);not null"`
+	Time         uint64         `gorm:"type:bigint(20);not null;index:idx_wrapper_transactions_status,priority:2"`
+	DstChainId   uint64         `gorm:"type:bigint(20);not null"`
+	DstUser      string         `gorm:"type:varchar(66);not null"`
+	ServerId     uint64         `gorm:"type:bigint(20);not null"`
+	FeeTokenHash string         `gorm:"size:66;not null"`
+	FeeAmount    *models.BigInt `gorm:"type:varchar(64);not null"`
+	Status       uint64         `gorm:"type:bigint(20);not null;index:idx_wrapper_transactions_status,priority:1"`
+}
+
+func (wrapperTransactionV1) TableName() string { return "wrapper_transactions" }
+
+type srcTransactionV1 struct {
+	Hash       string         `gorm:"primaryKey;size:66;not null"`
+	ChainId    uint64         `gorm:"type:bigint(20);not null"`
+	State      uint64         `gorm:"type:bigint(20);not null"`
+	Time       uint64         `gorm:"type:bigint(20);not null;index:idx_src_transactions_time"`
+	Fee        *models.BigInt `gorm:"type:varchar(64);not null"`
+	Height     uint64         `gorm:"type:bigint(20);not null"`
+	User       string         `gorm:"type:varchar(66);not null"`
+	DstChainId uint64         `gorm:"type:bigint(20);not null"`
+	Contract   string         `gorm:"type:varchar(66);not null"`
+	Key        string         `gorm:"type:varchar(8192);not null"`
+	Param      string         `gorm:"type:varchar(8192);not null"`
+}
+
+func (srcTransactionV1) TableName() string { return "src_transactions" }
+
+type srcTransferV1 struct {
+	TxHash     string         `gorm:"primaryKey;size:66;not null"`
+	ChainId    uint64         `gorm:"type:bigint(20);not null"`
+	Time       uint64         `gorm:"type:bigint(20);not null"`
+	Asset      string         `gorm:"type:varchar(66);not null"`
+	From       string         `gorm:"type:varchar(66);not null;index:idx_src_transfers_from"`
+	To         string         `gorm:"type:varchar(66);not null"`
+	Amount     *models.BigInt `gorm:"type:varchar(64);not null"`
+	DstChainId uint64         `gorm:"type:bigint(20);not null"`
+	DstAsset   string         `gorm:"type:varchar(66);not null"`
+	DstUser    string         `gorm:"type:varchar(66);not null;index:idx_src_transfers_dst_user"`
+}
+
+func (srcTransferV1) TableName() string { return "src_transfers" }
+
+type polyTransactionV1 struct {
+	Hash       string         `gorm:"primaryKey;size:66;not null"`
+	ChainId    uint64         `gorm:"type:bigint(20);not null"`
+	State      uint64         `gorm:"type:bigint(20);not null"`
+	Time       uint64         `gorm:"type:bigint(20);not null"`
+	Fee        *models.BigInt `gorm:"type:varchar(64);not null"`
+	Height     uint64         `gorm:"type:bigint(20);not null"`
+	SrcChainId uint64         `gorm:"type:bigint(20);not null"`
+	SrcHash    string         `gorm:"size:66;not null;index:idx_poly_transactions_src_hash"`
+	DstChainId uint64         `gorm:"type:bigint(20);not null"`
+	Key        string         `gorm:"type:varchar(8192);not null"`
+}
+
+func (polyTransactionV1) TableName() string { return "poly_transactions" }
+
+type dstTransactionV1 struct {
+	Hash       string         `gorm:"primaryKey;size:66;not null"`
+	ChainId    uint64         `gorm:"type:bigint(20);not null"`
+	State      uint64         `gorm:"type:bigint(20);not null"`
+	Time       uint64         `gorm:"type:bigint(20);not null"`
+	Fee        *models.BigInt `gorm:"type:varchar(64);not null"`
+	Height     uint64         `gorm:"type:bigint(20);not null"`
+	SrcChainId uint64         `gorm:"type:bigint(20);not null"`
+	Contract   string         `gorm:"type:varchar(66);not null"`
+	PolyHash   string         `gorm:"size:66;not null;index:idx_dst_transactions_poly_hash"`
+}
+
+func (dstTransactionV1) TableName() string { return "dst_transactions" }
+
+type dstTransferV1 struct {
+	TxHash  string         `gorm:"primaryKey;size:66;not null"`
+	ChainId uint64         `gorm:"type:bigint(20);not null"`
+	Time    uint64         `gorm:"type:bigint(20);not null"`
+	Asset   string         `gorm:"type:varchar(66);not null"`
+	From    string         `gorm:"type:varchar(66);not null"`
+	To      string         `gorm:"type:varchar(66);not null"`
+	Amount  *models.BigInt `gorm:"type:varchar(64);not null"`
+}
+
+func (dstTransferV1) TableName() string { return "dst_transfers" }
+
+type tokenBasicV1 struct {
+	Name      string `gorm:"primaryKey;size:64;not null"`
+	Precision uint64 `gorm:"type:bigint(20);not null"`
+	Price     int64  `gorm:"size:64;not null"`
+	Ind       uint64 `gorm:"type:bigint(20);not null"`
+	Time      int64  `gorm:"type:bigint(20);not null"`
+	Property  int64  `gorm:"type:bigint(20);not null"`
+}
+
+func (tokenBasicV1) TableName() string { return "token_basics" }
+
+type priceMarketV1 struct {
+	TokenBasicName string `gorm:"primaryKey;size:64;not null"`
+	MarketName     string `gorm:"primaryKey;size:64;not null"`
+	Name           string `gorm:"size:64;not null"`
+	Price          int64  `gorm:"type:bigint(20);not null"`
+	Ind            uint64 `gorm:"type:bigint(20);not null"`
+	Time           int64  `gorm:"type:bigint(20);not null"`
+}
+
+func (priceMarketV1) TableName() string { return "price_markets" }
+
+type chainFeeV1 struct {
+	ChainId        uint64         `gorm:"primaryKey;type:bigint(20);not null"`
+	TokenBasicName string         `gorm:"size:64;not null"`
+	MaxFee         *models.BigInt `gorm:"type:varchar(64);not null"`
+	MinFee         *models.BigInt `gorm:"type:varchar(64);not null"`
+	ProxyFee       *models.BigInt `gorm:"type:varchar(64);not null"`
+	Ind            uint64         `gorm:"type:bigint(20);not null"`
+	Time           int64          `gorm:"type:bigint(20);not null"`
+}
+
+func (chainFeeV1) TableName() string { return "chain_fees" }
+
+type tokenV1 struct {
+	Hash           string `gorm:"primaryKey;size:66;not null"`
+	ChainId        uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	Name           string `gorm:"size:64;not null"`
+	Precision      uint64 `gorm:"type:bigint(20);not null"`
+	TokenBasicName string `gorm:"size:64;not null"`
+	Property       int64  `gorm:"type:bigint(20);not null"`
+}
+
+func (tokenV1) TableName() string { return "tokens" }
+
+type tokenMapV1 struct {
+	SrcChainId   uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	SrcTokenHash string `gorm:"primaryKey;size:66;not null"`
+	DstChainId   uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	DstTokenHash string `gorm:"primaryKey;size:66;not null"`
+	Property     int64  `gorm:"type:bigint(20);not null"`
+}
+
+func (tokenMapV1) TableName() string { return "token_maps" }
+
+type nftAssetBasicV1 struct {
+	Name    string `gorm:"primaryKey;size:64;not null"`
+	Time    int64  `gorm:"type:bigint(20);not null"`
+	Disable int64  `gorm:"type:int;not null"`
+}
+
+func (nftAssetBasicV1) TableName() string { return "nft_asset_basics" }
+
+type nftAssetV1 struct {
+	Hash           string `gorm:"primaryKey;size:66;not null"`
+	ChainId        uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	Name           string `gorm:"size:64;not null"`
+	BaseUri        string `gorm:"type:varchar(128);not null"`
+	AssetBasicName string `gorm:"size:64;not null"`
+	Disable        int64  `gorm:"type:int;not null"`
+}
+
+func (nftAssetV1) TableName() string { return "nft_assets" }
+
+type nftAssetMapV1 struct {
+	SrcChainId   uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	SrcAssetHash string `gorm:"primaryKey;size:66;not null"`
+	DstChainId   uint64 `gorm:"primaryKey;type:bigint(20);not null;index:idx_nft_asset_maps_dst,priority:1"`
+	DstAssetHash string `gorm:"primaryKey;size:66;not null;index:idx_nft_asset_maps_dst,priority:2"`
+	Disable      int64  `gorm:"type:int;not null"`
+}
+
+func (nftAssetMapV1) TableName() string { return "nft_asset_maps" }
+
+type apiKeyV1 struct {
+	Key       string  `gorm:"primaryKey;size:64;not null"`
+	Name      string  `gorm:"size:64;not null"`
+	Rate      float64 `gorm:"not null"`
+	Burst     int64   `gorm:"type:bigint(20);not null"`
+	NodeRate  float64 `gorm:"not null"`
+	NodeBurst int64   `gorm:"type:bigint(20);not null"`
+	Disable   int64   `gorm:"type:int;not null"`
+}
+
+func (apiKeyV1) TableName() string { return "api_keys" }
+
+var tablesV1 = []interface{}{
+	&chainV1{},
+	&wrapperTransactionV1{},
+	&srcTransactionV1{},
+	&srcTransferV1{},
+	&polyTransactionV1{},
+	&dstTransactionV1{},
+	&dstTransferV1{},
+	&tokenBasicV1{},
+	&priceMarketV1{},
+	&chainFeeV1{},
+	&tokenV1{},
+	&tokenMapV1{},
+	&nftAssetBasicV1{},
+	&nftAssetV1{},
+	&nftAssetMapV1{},
+	&apiKeyV1{},
+}
+
+func init() {
+	register(&Migration{
+		Version: 1,
+		Name:    "initial",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, tablesV1...)
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, tablesV1...)
+		},
+	})
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import "gorm.io/gorm"
+
+type nftProxyBindV2 struct {
+	ChainId   uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	ToChainId uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	ProxyHash string `gorm:"type:varchar(128);not null"`
+	TxHash    string `gorm:"size:66;not null"`
+	Height    uint64 `gorm:"type:bigint(20);not null"`
+}
+
+func (nftProxyBindV2) TableName() string { return "nft_proxy_binds" }
+
+func init() {
+	register(&Migration{
+		Version: 2,
+		Name:    "nft_proxy_binds",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &nftProxyBindV2{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &nftProxyBindV2{})
+		},
+	})
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import "gorm.io/gorm"
+
+type unmatchedUnlockV3 struct {
+	Hash       string `gorm:"primaryKey;size:66;not null"`
+	ChainId    uint64 `gorm:"type:bigint(20);not null"`
+	SrcChainId uint64 `gorm:"type:bigint(20);not null"`
+	PolyHash   string `gorm:"size:66;not null"`
+	SrcHash    string `gorm:"size:66;not null"`
+	Reason     string `gorm:"type:varchar(256);not null"`
+	Time       uint64 `gorm:"type:bigint(20);not null"`
+	CreateTime uint64 `gorm:"type:bigint(20);not null"`
+	Resolved   int64  `gorm:"type:int;not null;index:idx_unmatched_unlocks_resolved"`
+}
+
+func (unmatchedUnlockV3) TableName() string { return "unmatched_unlocks" }
+
+func init() {
+	register(&Migration{
+		Version: 3,
+		Name:    "unmatched_unlocks",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &unmatchedUnlockV3{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &unmatchedUnlockV3{})
+		},
+	})
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import "gorm.io/gorm"
+
+type nftSupplyAuditV4 struct {
+	AssetBasicName string `gorm:"primaryKey;size:64;not null"`
+	Time           int64  `gorm:"type:bigint(20);not null"`
+	Chains         int64  `gorm:"type:bigint(20);not null"`
+	Tokens         int64  `gorm:"type:bigint(20);not null"`
+	Locked         int64  `gorm:"type:bigint(20);not null"`
+	Double         int64  `gorm:"type:bigint(20);not null"`
+	Missing        int64  `gorm:"type:bigint(20);not null"`
+	Truncated      int64  `gorm:"type:int;not null"`
+}
+
+func (nftSupplyAuditV4) TableName() string { return "nft_supply_audits" }
+
+type nftSupplyIssueV4 struct {
+	AssetBasicName string `gorm:"primaryKey;size:64;not null"`
+	TokenId        string `gorm:"primaryKey;size:80;not null"`
+	Kind           string `gorm:"primaryKey;size:16;not null"`
+	Chains         string `gorm:"type:varchar(256);not null"`
+}
+
+func (nftSupplyIssueV4) TableName() string { return "nft_supply_issues" }
+
+func init() {
+	register(&Migration{
+		Version: 4,
+		Name:    "nft_supply_audits",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &nftSupplyAuditV4{}, &nftSupplyIssueV4{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &nftSupplyAuditV4{}, &nftSupplyIssueV4{})
+		},
+	})
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import "gorm.io/gorm"
+
+type governanceEventV5 struct {
+	TxHash   string `gorm:"primaryKey;size:66;not null"`
+	LogIndex uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	ChainId  uint64 `gorm:"type:bigint(20);not null;index:idx_governance_events_chain,priority:1"`
+	Contract string `gorm:"size:16;not null"`
+	Address  string `gorm:"size:66;not null"`
+	Event    string `gorm:"size:32;not null"`
+	Detail   string `gorm:"type:varchar(512);not null"`
+	Height   uint64 `gorm:"type:bigint(20);not null;index:idx_governance_events_chain,priority:2"`
+	Time     uint64 `gorm:"type:bigint(20);not null"`
+}
+
+func (governanceEventV5) TableName() string { return "governance_events" }
+
+func init() {
+	register(&Migration{
+		Version: 5,
+		Name:    "governance_events",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &governanceEventV5{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &governanceEventV5{})
+		},
+	})
+}
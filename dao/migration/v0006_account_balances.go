@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"gorm.io/gorm"
+)
+
+type accountBalanceV6 struct {
+	ChainId   uint64         `gorm:"primaryKey;type:bigint(20);not null"`
+	Address   string         `gorm:"primaryKey;size:66;not null"`
+	Token     string         `gorm:"primaryKey;size:66;not null"`
+	Role      string         `gorm:"size:16;not null"`
+	Name      string         `gorm:"size:64;not null"`
+	Precision uint64         `gorm:"type:bigint(20);not null"`
+	Balance   *models.BigInt `gorm:"type:varchar(64);not null"`
+	Min       *models.BigInt `gorm:"type:varchar(64);not null"`
+	Burn      *models.BigInt `gorm:"type:varchar(64);not null"`
+	Runway    int64          `gorm:"type:bigint(20);not null"`
+	Low       int64          `gorm:"type:int;not null"`
+	Time      int64          `gorm:"type:bigint(20);not null"`
+}
+
+func (accountBalanceV6) TableName() string { return "account_balances" }
+
+func init() {
+	register(&Migration{
+		Version: 6,
+		Name:    "account_balances",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &accountBalanceV6{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &accountBalanceV6{})
+		},
+	})
+}
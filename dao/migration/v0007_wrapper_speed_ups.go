@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"gorm.io/gorm"
+)
+
+type wrapperSpeedUpV7 struct {
+	TxHash       string         `gorm:"primaryKey;size:66;not null"`
+	LogIndex     uint64         `gorm:"primaryKey;type:bigint(20);not null"`
+	SrcHash      string         `gorm:"index;size:66;not null"`
+	ChainId      uint64         `gorm:"type:bigint(20);not null"`
+	User         string         `gorm:"type:varchar(66);not null"`
+	FeeTokenHash string         `gorm:"size:66;not null"`
+	FeeAmount    *models.BigInt `gorm:"type:varchar(64);not null"`
+	Height       uint64         `gorm:"type:bigint(20);not null"`
+	Time         uint64         `gorm:"type:bigint(20);not null"`
+}
+
+func (wrapperSpeedUpV7) TableName() string { return "wrapper_speed_ups" }
+
+// wrapperTotalFeeV7 is the column added to wrapper_transactions.
+type wrapperTotalFeeV7 struct {
+	TotalFee *models.BigInt `gorm:"type:varchar(64);not null;default:'0'"`
+}
+
+func (wrapperTotalFeeV7) TableName() string { return "wrapper_transactions" }
+
+func init() {
+	register(&Migration{
+		Version: 7,
+		Name:    "wrapper_speed_ups",
+		Up: func(tx *gorm.DB) error {
+			if err := createTables(tx, &wrapperSpeedUpV7{}); err != nil {
+				return err
+			}
+			if !tx.Migrator().HasColumn(&wrapperTotalFeeV7{}, "TotalFee") {
+				if err := tx.Migrator().AddColumn(&wrapperTotalFeeV7{}, "TotalFee"); err != nil {
+					return err
+				}
+			}
+			// the speed ups before were saved over the wrapper transactions, so
+			// the fee amount is all that is known of them
+			return tx.Exec("update wrapper_transactions set total_fee = fee_amount").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&wrapperTotalFeeV7{}, "TotalFee") {
+				if err := tx.Migrator().DropColumn(&wrapperTotalFeeV7{}, "TotalFee"); err != nil {
+					return err
+				}
+			}
+			return dropTables(tx, &wrapperSpeedUpV7{})
+		},
+	})
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"gorm.io/gorm"
+)
+
+type crossChainArgsV8 struct {
+	ToContract   string `gorm:"type:varchar(66);not null;default:''"`
+	Method       string `gorm:"size:32;not null;default:''"`
+	ArgsAsset    string `gorm:"type:varchar(66);not null;default:''"`
+	ArgsUser     string `gorm:"type:varchar(66);not null;default:''"`
+	ArgsTokenId  string `gorm:"type:varchar(80);not null;default:''"`
+	ArgsTokenUri string `gorm:"type:varchar(1024);not null;default:''"`
+	ArgsError    string `gorm:"type:varchar(256);not null;default:''"`
+}
+
+type srcTransactionArgsV8 struct {
+	Args crossChainArgsV8 `gorm:"embedded"`
+}
+
+func (srcTransactionArgsV8) TableName() string { return "src_transactions" }
+
+type polyTransactionArgsV8 struct {
+	Args crossChainArgsV8 `gorm:"embedded"`
+}
+
+func (polyTransactionArgsV8) TableName() string { return "poly_transactions" }
+
+var crossChainArgsColumnsV8 = []string{"ToContract", "Method", "ArgsAsset", "ArgsUser", "ArgsTokenId", "ArgsTokenUri", "ArgsError"}
+
+func init() {
+	register(&Migration{
+		Version: 8,
+		Name:    "cross_chain_args",
+		Up: func(tx *gorm.DB) error {
+			for _, table := range []interface{}{&srcTransactionArgsV8{}, &polyTransactionArgsV8{}} {
+				for _, column := range crossChainArgsColumnsV8 {
+					if tx.Migrator().HasColumn(table, column) {
+						continue
+					}
+					if err := tx.Migrator().AddColumn(table, column); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, table := range []interface{}{&srcTransactionArgsV8{}, &polyTransactionArgsV8{}} {
+				for _, column := range crossChainArgsColumnsV8 {
+					if !tx.Migrator().HasColumn(table, column) {
+						continue
+					}
+					if err := tx.Migrator().DropColumn(table, column); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	})
+}
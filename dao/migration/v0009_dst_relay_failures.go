@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"gorm.io/gorm"
+)
+
+type dstRelayFailureV9 struct {
+	TxHash     string         `gorm:"primaryKey;size:66;not null"`
+	ChainId    uint64         `gorm:"type:bigint(20);not null"`
+	PolyHash   string         `gorm:"index;size:66;not null"`
+	SrcChainId uint64         `gorm:"type:bigint(20);not null"`
+	SrcKey     string         `gorm:"size:66;not null"`
+	Relayer    string         `gorm:"type:varchar(66);not null"`
+	Reason     string         `gorm:"type:varchar(256);not null;default:''"`
+	Fee        *models.BigInt `gorm:"type:varchar(64);not null"`
+	Height     uint64         `gorm:"type:bigint(20);not null"`
+	Time       uint64         `gorm:"type:bigint(20);not null"`
+}
+
+func (dstRelayFailureV9) TableName() string { return "dst_relay_failures" }
+
+func init() {
+	register(&Migration{
+		Version: 9,
+		Name:    "dst_relay_failures",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &dstRelayFailureV9{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropTables(tx, &dstRelayFailureV9{})
+		},
+	})
+}
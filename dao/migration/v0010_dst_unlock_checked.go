@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type dstTransactionUnlockCheckedV10 struct {
+	UnlockChecked int64 `gorm:"type:int;not null;default:0;index:idx_dst_transactions_unlock_checked"`
+}
+
+func (dstTransactionUnlockCheckedV10) TableName() string { return "dst_transactions" }
+
+// unlocks older than this are taken as checked by the monitor, which looked one
+// day back on start before the flag was kept
+const unlockCheckedBackfillV10 = 86400
+
+func init() {
+	register(&Migration{
+		Version: 10,
+		Name:    "dst_unlock_checked",
+		Up: func(tx *gorm.DB) error {
+			table := &dstTransactionUnlockCheckedV10{}
+			if !tx.Migrator().HasColumn(table, "UnlockChecked") {
+				if err := tx.Migrator().AddColumn(table, "UnlockChecked"); err != nil {
+					return err
+				}
+			}
+			if !tx.Migrator().HasIndex(table, "idx_dst_transactions_unlock_checked") {
+				if err := tx.Migrator().CreateIndex(table, "idx_dst_transactions_unlock_checked"); err != nil {
+					return err
+				}
+			}
+			return tx.Table("dst_transactions").
+				Where("time <= ?", time.Now().Unix()-unlockCheckedBackfillV10).
+				Update("unlock_checked", 1).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			table := &dstTransactionUnlockCheckedV10{}
+			if tx.Migrator().HasIndex(table, "idx_dst_transactions_unlock_checked") {
+				if err := tx.Migrator().DropIndex(table, "idx_dst_transactions_unlock_checked"); err != nil {
+					return err
+				}
+			}
+			if !tx.Migrator().HasColumn(table, "UnlockChecked") {
+				return nil
+			}
+			return tx.Migrator().DropColumn(table, "UnlockChecked")
+		},
+	})
+}
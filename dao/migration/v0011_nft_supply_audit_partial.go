@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import "gorm.io/gorm"
+
+type nftSupplyAuditPartialV11 struct {
+	Partial int64 `gorm:"type:int;not null;default:0"`
+}
+
+func (nftSupplyAuditPartialV11) TableName() string { return "nft_supply_audits" }
+
+func init() {
+	register(&Migration{
+		Version: 11,
+		Name:    "nft_supply_audit_partial",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&nftSupplyAuditPartialV11{}, "Partial") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&nftSupplyAuditPartialV11{}, "Partial")
+		},
+		Down: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasColumn(&nftSupplyAuditPartialV11{}, "Partial") {
+				return nil
+			}
+			return tx.Migrator().DropColumn(&nftSupplyAuditPartialV11{}, "Partial")
+		},
+	})
+}
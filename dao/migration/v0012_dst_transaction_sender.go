@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package migration
+
+import "gorm.io/gorm"
+
+type dstTransactionSenderV12 struct {
+	Sender string `gorm:"type:varchar(66);not null;default:'';index:idx_dst_transactions_sender"`
+}
+
+func (dstTransactionSenderV12) TableName() string { return "dst_transactions" }
+
+func init() {
+	register(&Migration{
+		Version: 12,
+		Name:    "dst_transaction_sender",
+		Up: func(tx *gorm.DB) error {
+			table := &dstTransactionSenderV12{}
+			if !tx.Migrator().HasColumn(table, "Sender") {
+				if err := tx.Migrator().AddColumn(table, "Sender"); err != nil {
+					return err
+				}
+			}
+			if tx.Migrator().HasIndex(table, "idx_dst_transactions_sender") {
+				return nil
+			}
+			return tx.Migrator().CreateIndex(table, "idx_dst_transactions_sender")
+		},
+		Down: func(tx *gorm.DB) error {
+			table := &dstTransactionSenderV12{}
+			if tx.Migrator().HasIndex(table, "idx_dst_transactions_sender") {
+				if err := tx.Migrator().DropIndex(table, "idx_dst_transactions_sender"); err != nil {
+					return err
+				}
+			}
+			if !tx.Migrator().HasColumn(table, "Sender") {
+				return nil
+			}
+			return tx.Migrator().DropColumn(table, "Sender")
+		},
+	})
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package swapdao
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// key is a reserved word, leave the quoting to the dialect
+var keyColumn = clause.Column{Name: "key"}
+
+type SwapDao struct {
+	dbCfg *conf.DBConfig
+	db    *gorm.DB
+}
+
+func NewSwapDao(dbCfg *conf.DBConfig) *SwapDao {
+	db := dbopen.MustOpen(dbCfg)
+	return &SwapDao{
+		dbCfg: dbCfg,
+		db:    db,
+	}
+}
+
+// GetUncheckedDstTransactions returns at most limit unlocks not checked by the
+// unlock monitor with time <= before, oldest first.
+func (dao *SwapDao) GetUncheckedDstTransactions(before uint64, limit int) ([]*models.DstTransaction, error) {
+	txs := make([]*models.DstTransaction, 0)
+	res := dao.db.Where("unlock_checked = ? and time <= ?", basedef.UNLOCK_UNCHECKED, before).
+		Preload("DstTransfer").
+		Order("time asc").
+		Limit(limit).
+		Find(&txs)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return txs, nil
+}
+
+func (dao *SwapDao) SetDstTransactionsChecked(hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	return dao.db.Model(&models.DstTransaction{}).Where("hash in ?", hashes).
+		Update("unlock_checked", basedef.UNLOCK_CHECKED).Error
+}
+
+func (dao *SwapDao) GetDstTransaction(hash string) (*models.DstTransaction, error) {
+	tx := new(models.DstTransaction)
+	res := dao.db.Where("hash = ?", hash).Preload("DstTransfer").Limit(1).Find(tx)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return tx, nil
+}
+
+func (dao *SwapDao) GetPolyTransaction(hash string) (*models.PolyTransaction, error) {
+	tx := new(models.PolyTransaction)
+	res := dao.db.Where("hash = ?", hash).Limit(1).Find(tx)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return tx, nil
+}
+
+// GetSrcTransaction looks the source up by the hash poly recorded, which is
+// either the transaction hash or the eccm tx id depending on the chain.
+func (dao *SwapDao) GetSrcTransaction(chainId uint64, hash string) (*models.SrcTransaction, error) {
+	tx := new(models.SrcTransaction)
+	res := dao.db.Where("chain_id = ? and (hash = ? or ? = ?)", chainId, hash, keyColumn, hash).
+		Preload("SrcTransfer").
+		Limit(1).
+		Find(tx)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return tx, nil
+}
+
+func (dao *SwapDao) GetAssetMaps(srcChainId uint64, srcHash string, dstChainId uint64) ([]*models.NFTAssetMap, error) {
+	assetMaps := make([]*models.NFTAssetMap, 0)
+	res := dao.db.Where("src_chain_id = ? and src_asset_hash = ? and dst_chain_id = ?",
+		srcChainId, strings.ToLower(srcHash), dstChainId).
+		Find(&assetMaps)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return assetMaps, nil
+}
+
+func (dao *SwapDao) GetUnmatchedUnlock(hash string) (*models.UnmatchedUnlock, error) {
+	unlock := new(models.UnmatchedUnlock)
+	res := dao.db.Where("hash = ?", hash).Limit(1).Find(unlock)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return unlock, nil
+}
+
+// GetUnmatchedUnlocks returns the unlocks flagged and not resolved yet.
+func (dao *SwapDao) GetUnmatchedUnlocks() ([]*models.UnmatchedUnlock, error) {
+	unlocks := make([]*models.UnmatchedUnlock, 0)
+	res := dao.db.Where("resolved = ?", basedef.UNLOCK_UNRESOLVED).Order("time asc").Find(&unlocks)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return unlocks, nil
+}
+
+func (dao *SwapDao) SaveUnmatchedUnlock(unlock *models.UnmatchedUnlock) error {
+	return dao.db.Save(unlock).Error
+}
+
+func (dao *SwapDao) ResolveUnmatchedUnlock(hash string) error {
+	return dao.db.Model(&models.UnmatchedUnlock{}).Where("hash = ?", hash).
+		Update("resolved", basedef.UNLOCK_RESOLVED).Error
+}
+
+// GetAuditAssetMaps returns the enabled asset maps with both assets.
+func (dao *SwapDao) GetAuditAssetMaps() ([]*models.NFTAssetMap, error) {
+	assetMaps := make([]*models.NFTAssetMap, 0)
+	res := dao.db.Where("disable = ?", basedef.ASSET_ENABLE).
+		Preload("SrcAsset").
+		Preload("DstAsset").
+		Find(&assetMaps)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return assetMaps, nil
+}
+
+func (dao *SwapDao) GetSupplyAudit(name string) (*models.NFTSupplyAudit, error) {
+	audit := new(models.NFTSupplyAudit)
+	res := dao.db.Where("asset_basic_name = ?", name).Preload("Issues").Limit(1).Find(audit)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return audit, nil
+}
+
+// SaveSupplyAudit replaces the last audit of the collection and its issues.
+func (dao *SwapDao) SaveSupplyAudit(audit *models.NFTSupplyAudit) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("asset_basic_name = ?", audit.AssetBasicName).Delete(&models.NFTSupplyIssue{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(audit).Error; err != nil {
+			return err
+		}
+		if len(audit.Issues) == 0 {
+			return nil
+		}
+		return tx.Create(audit.Issues).Error
+	})
+}
+
+func (dao *SwapDao) GetChains() ([]*models.Chain, error) {
+	chains := make([]*models.Chain, 0)
+	res := dao.db.Order("chain_id asc").Find(&chains)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return chains, nil
+}
+
+// GetPendingWrapperTransactions returns the unfinished wrapper transactions
+// sent before the time, oldest first.
+func (dao *SwapDao) GetPendingWrapperTransactions(before uint64) ([]*models.WrapperTransaction, error) {
+	txs := make([]*models.WrapperTransaction, 0)
+	res := dao.db.Where("status != ? and time < ?", basedef.STATE_FINISHED, before).
+		Order("time asc").
+		Find(&txs)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return txs, nil
+}
+
+// GetPendingLocks returns the locks of the asset on the chain sent after
+// since which have no destination transaction yet.
+func (dao *SwapDao) GetPendingLocks(chainId uint64, asset string, since uint64) ([]*models.SrcTransfer, error) {
+	transfers := make([]*models.SrcTransfer, 0)
+	res := dao.db.Model(&models.SrcTransfer{}).
+		Select("src_transfers.*").
+		Joins("inner join src_transactions on src_transfers.tx_hash = src_transactions.hash").
+		Joins("left join poly_transactions on poly_transactions.src_chain_id = src_transactions.chain_id and "+
+			"(poly_transactions.src_hash = src_transactions.hash or poly_transactions.src_hash = ?)",
+			clause.Column{Table: "src_transactions", Name: "key"}).
+		Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
+		Where("src_transfers.chain_id = ? and src_transfers.asset = ? and src_transfers.time > ? and dst_transactions.hash is null",
+			chainId, asset, since).
+		Find(&transfers)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return transfers, nil
+}
+
+// GetDstTransactionFees sums the fees of the unlocks the relayer sent on the
+// chain after start. The fees are strings, they are summed here.
+func (dao *SwapDao) GetDstTransactionFees(chainId uint64, sender string, start uint64) (*big.Int, error) {
+	txs := make([]*models.DstTransaction, 0)
+	res := dao.db.Select("fee").Where("chain_id = ? and sender = ? and time > ?", chainId, sender, start).Find(&txs)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	fees := big.NewInt(0)
+	for _, tx := range txs {
+		if tx.Fee != nil {
+			fees.Add(fees, &tx.Fee.Int)
+		}
+	}
+	return fees, nil
+}
+
+func (dao *SwapDao) GetFeeTokens(chainId uint64) ([]*models.Token, error) {
+	tokens := make([]*models.Token, 0)
+	res := dao.db.Where("chain_id = ?", chainId).Order("hash asc").Find(&tokens)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return tokens, nil
+}
+
+func (dao *SwapDao) SaveAccountBalances(balances []*models.AccountBalance) error {
+	if len(balances) == 0 {
+		return nil
+	}
+	res := dao.db.Save(balances)
+	return res.Error
+}
+
+func (dao *SwapDao) Name() string {
+	return basedef.SERVER_POLY_SWAP
+}
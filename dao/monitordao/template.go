@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitordao
+
+import (
+	"math/big"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/monitordao/swapdao"
+	"github.com/polynetwork/poly-nft-bridge/models"
+)
+
+// MonitorDao is used by the security monitors. Getters of a single record
+// return nil without error when the record does not exist.
+type MonitorDao interface {
+	GetUncheckedDstTransactions(before uint64, limit int) ([]*models.DstTransaction, error)
+	SetDstTransactionsChecked(hashes []string) error
+	GetDstTransaction(hash string) (*models.DstTransaction, error)
+	GetPolyTransaction(hash string) (*models.PolyTransaction, error)
+	GetSrcTransaction(chainId uint64, hash string) (*models.SrcTransaction, error)
+	GetAssetMaps(srcChainId uint64, srcHash string, dstChainId uint64) ([]*models.NFTAssetMap, error)
+	GetUnmatchedUnlock(hash string) (*models.UnmatchedUnlock, error)
+	GetUnmatchedUnlocks() ([]*models.UnmatchedUnlock, error)
+	SaveUnmatchedUnlock(unlock *models.UnmatchedUnlock) error
+	ResolveUnmatchedUnlock(hash string) error
+	GetAuditAssetMaps() ([]*models.NFTAssetMap, error)
+	GetSupplyAudit(name string) (*models.NFTSupplyAudit, error)
+	SaveSupplyAudit(audit *models.NFTSupplyAudit) error
+	GetChains() ([]*models.Chain, error)
+	GetPendingWrapperTransactions(before uint64) ([]*models.WrapperTransaction, error)
+	GetPendingLocks(chainId uint64, asset string, since uint64) ([]*models.SrcTransfer, error)
+	GetDstTransactionFees(chainId uint64, sender string, start uint64) (*big.Int, error)
+	GetFeeTokens(chainId uint64) ([]*models.Token, error)
+	SaveAccountBalances(balances []*models.AccountBalance) error
+	Name() string
+}
+
+func NewMonitorDao(server string, dbCfg *conf.DBConfig) MonitorDao {
+	if server == basedef.SERVER_POLY_SWAP {
+		return swapdao.NewSwapDao(dbCfg)
+	} else {
+		return nil
+	}
+}
@@ -45,6 +45,14 @@ type NFTAssetMap struct {
 	Disable      int64     `gorm:"type:int;not null"`
 }
 
+type NFTProxyBind struct {
+	ChainId   uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	ToChainId uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	ProxyHash string `gorm:"type:varchar(128);not null"`
+	TxHash    string `gorm:"size:66;not null"`
+	Height    uint64 `gorm:"type:bigint(20);not null"`
+}
+
 type NFTToken struct {
 	Hash       string         `gorm:"primaryKey;size:66;not null"`
 	ChainId    uint64         `gorm:"primaryKey;type:bigint(20);not null"`
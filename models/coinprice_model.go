@@ -84,6 +84,7 @@ type WrapperTransactionWithToken struct {
 	FeeTokenHash string  `gorm:"size:66;not null"`
 	FeeToken     *Token  `gorm:"foreignKey:FeeTokenHash,SrcChainId;references:Hash,ChainId"`
 	FeeAmount    *BigInt `gorm:"type:varchar(64);not null"`
+	TotalFee     *BigInt `gorm:"type:varchar(64);not null"`
 	Status       uint64  `gorm:"type:bigint(20);not null"`
 }
 
@@ -36,6 +36,19 @@ type SrcTransaction struct {
 	Key         string       `gorm:"type:varchar(8192);not null"`
 	Param       string       `gorm:"type:varchar(8192);not null"`
 	SrcTransfer *SrcTransfer `gorm:"foreignKey:TxHash;references:Hash"`
+	CrossChainArgs
+}
+
+// CrossChainArgs are decoded from the param committed to poly. ArgsError tells
+// why they can not be decoded, or how they differ from the proxy lock event.
+type CrossChainArgs struct {
+	ToContract   string `gorm:"type:varchar(66);not null;default:''"`
+	Method       string `gorm:"size:32;not null;default:''"`
+	ArgsAsset    string `gorm:"type:varchar(66);not null;default:''"`
+	ArgsUser     string `gorm:"type:varchar(66);not null;default:''"`
+	ArgsTokenId  string `gorm:"type:varchar(80);not null;default:''"`
+	ArgsTokenUri string `gorm:"type:varchar(1024);not null;default:''"`
+	ArgsError    string `gorm:"type:varchar(256);not null;default:''"`
 }
 
 type SrcTransfer struct {
@@ -62,6 +75,7 @@ type PolyTransaction struct {
 	SrcHash    string  `gorm:"size:66;not null"`
 	DstChainId uint64  `gorm:"type:bigint(20);not null"`
 	Key        string  `gorm:"type:varchar(8192);not null"`
+	CrossChainArgs
 }
 
 type PolySrcRelation struct {
@@ -82,6 +96,12 @@ type DstTransaction struct {
 	Contract    string       `gorm:"type:varchar(66);not null"`
 	PolyHash    string       `gorm:"size:66;not null"`
 	DstTransfer *DstTransfer `gorm:"foreignKey:TxHash;references:Hash"`
+
+	// UnlockChecked is set once the unlock monitor has checked the unlock
+	UnlockChecked int64 `gorm:"type:int;not null;default:0"`
+
+	// Sender is the relayer which sent the unlock, empty before it was kept
+	Sender string `gorm:"type:varchar(66);not null;default:''"`
 }
 
 type DstTransfer struct {
@@ -105,9 +125,39 @@ type WrapperTransaction struct {
 	ServerId     uint64  `gorm:"type:bigint(20);not null"`
 	FeeTokenHash string  `gorm:"size:66;not null"`
 	FeeAmount    *BigInt `gorm:"type:varchar(64);not null"`
+	TotalFee     *BigInt `gorm:"type:varchar(64);not null;default:'0'"`
 	Status       uint64  `gorm:"type:bigint(20);not null"`
 }
 
+// WrapperSpeedUp is a fee added to a wrapper transaction after the lock, the
+// TotalFee of the wrapper transaction is its FeeAmount plus all of them.
+type WrapperSpeedUp struct {
+	TxHash       string  `gorm:"primaryKey;size:66;not null"`
+	LogIndex     uint64  `gorm:"primaryKey;type:bigint(20);not null"`
+	SrcHash      string  `gorm:"index;size:66;not null"`
+	ChainId      uint64  `gorm:"type:bigint(20);not null"`
+	User         string  `gorm:"type:varchar(66);not null"`
+	FeeTokenHash string  `gorm:"size:66;not null"`
+	FeeAmount    *BigInt `gorm:"type:varchar(64);not null"`
+	Height       uint64  `gorm:"type:bigint(20);not null"`
+	Time         uint64  `gorm:"type:bigint(20);not null"`
+}
+
+// DstRelayFailure is a relay of a poly transaction whose transaction reverted
+// on the destination chain, the transfer waits until another relay succeeds.
+type DstRelayFailure struct {
+	TxHash     string  `gorm:"primaryKey;size:66;not null"`
+	ChainId    uint64  `gorm:"type:bigint(20);not null"`
+	PolyHash   string  `gorm:"index;size:66;not null"`
+	SrcChainId uint64  `gorm:"type:bigint(20);not null"`
+	SrcKey     string  `gorm:"size:66;not null"`
+	Relayer    string  `gorm:"type:varchar(66);not null"`
+	Reason     string  `gorm:"type:varchar(256);not null;default:''"`
+	Fee        *BigInt `gorm:"type:varchar(64);not null"`
+	Height     uint64  `gorm:"type:bigint(20);not null"`
+	Time       uint64  `gorm:"type:bigint(20);not null"`
+}
+
 type SrcPolyDstRelation struct {
 	SrcHash            string
 	WrapperTransaction *WrapperTransaction `gorm:"foreignKey:SrcHash;references:Hash"`
@@ -56,3 +56,22 @@ type ProxyUnlockEvent struct {
 	ToAddress   string
 	TokenId     *big.Int
 }
+
+type AssetBindEvent struct {
+	TxHash      string
+	ChainId     uint64
+	AssetHash   string
+	Name        string
+	Symbol      string
+	BaseUri     string
+	ToChainId   uint64
+	ToAssetHash string
+	Height      uint64
+}
+type ProxyBindEvent struct {
+	TxHash    string
+	ChainId   uint64
+	ToChainId uint64
+	ProxyHash string
+	Height    uint64
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package models
+
+type UnmatchedUnlock struct {
+	Hash       string `gorm:"primaryKey;size:66;not null"`
+	ChainId    uint64 `gorm:"type:bigint(20);not null"`
+	SrcChainId uint64 `gorm:"type:bigint(20);not null"`
+	PolyHash   string `gorm:"size:66;not null"`
+	SrcHash    string `gorm:"size:66;not null"`
+	Reason     string `gorm:"type:varchar(256);not null"`
+	Time       uint64 `gorm:"type:bigint(20);not null"`
+	CreateTime uint64 `gorm:"type:bigint(20);not null"`
+	Resolved   int64  `gorm:"type:int;not null"`
+}
+
+// NFTSupplyAudit is the latest audit of a collection over the chains it is
+// mapped to.
+type NFTSupplyAudit struct {
+	AssetBasicName string            `gorm:"primaryKey;size:64;not null"`
+	Time           int64             `gorm:"type:bigint(20);not null"`
+	Chains         int64             `gorm:"type:bigint(20);not null"`
+	Tokens         int64             `gorm:"type:bigint(20);not null"`
+	Locked         int64             `gorm:"type:bigint(20);not null"`
+	Double         int64             `gorm:"type:bigint(20);not null"`
+	Missing        int64             `gorm:"type:bigint(20);not null"`
+	Truncated      int64             `gorm:"type:int;not null"`
+	Partial        int64             `gorm:"type:int;not null;default:0"`
+	Issues         []*NFTSupplyIssue `gorm:"foreignKey:AssetBasicName;references:AssetBasicName"`
+}
+
+type NFTSupplyIssue struct {
+	AssetBasicName string `gorm:"primaryKey;size:64;not null"`
+	TokenId        string `gorm:"primaryKey;size:80;not null"`
+	Kind           string `gorm:"primaryKey;size:16;not null"`
+	Chains         string `gorm:"type:varchar(256);not null"`
+}
+
+// GovernanceEvent is an ownership, manager, binding or pause change of one of
+// the bridge contracts.
+type GovernanceEvent struct {
+	TxHash   string `gorm:"primaryKey;size:66;not null"`
+	LogIndex uint64 `gorm:"primaryKey;type:bigint(20);not null"`
+	ChainId  uint64 `gorm:"type:bigint(20);not null"`
+	Contract string `gorm:"size:16;not null"`
+	Address  string `gorm:"size:66;not null"`
+	Event    string `gorm:"size:32;not null"`
+	Detail   string `gorm:"type:varchar(512);not null"`
+	Height   uint64 `gorm:"type:bigint(20);not null"`
+	Time     uint64 `gorm:"type:bigint(20);not null"`
+}
+
+// AccountBalance is the latest balance of a watched account in one token, the
+// native token has a zero hash. Runway is the seconds the balance lasts at the
+// recent burn, -1 when nothing is burnt.
+type AccountBalance struct {
+	ChainId   uint64  `gorm:"primaryKey;type:bigint(20);not null"`
+	Address   string  `gorm:"primaryKey;size:66;not null"`
+	Token     string  `gorm:"primaryKey;size:66;not null"`
+	Role      string  `gorm:"size:16;not null"`
+	Name      string  `gorm:"size:64;not null"`
+	Precision uint64  `gorm:"type:bigint(20);not null"`
+	Balance   *BigInt `gorm:"type:varchar(64);not null"`
+	Min       *BigInt `gorm:"type:varchar(64);not null"`
+	Burn      *BigInt `gorm:"type:varchar(64);not null"`
+	Runway    int64   `gorm:"type:bigint(20);not null"`
+	Low       int64   `gorm:"type:int;not null"`
+	Time      int64   `gorm:"type:bigint(20);not null"`
+}
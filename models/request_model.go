@@ -19,6 +19,7 @@ package models
 
 import (
 	"math/big"
+	"sort"
 
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
 	"github.com/polynetwork/poly-nft-bridge/utils/decimal"
@@ -211,6 +212,184 @@ func MakeNFTAssetMapsRsp(assetMaps []*NFTAssetMap) *NFTAssetMapsRsp {
 	return rsp
 }
 
+type NFTSupplyAuditsReq struct {
+}
+
+type NFTSupplyIssueRsp struct {
+	TokenId string
+	Kind    string
+	Chains  string
+}
+
+type NFTSupplyAuditRsp struct {
+	Name      string
+	Time      int64
+	Chains    int64
+	Tokens    int64
+	Locked    int64
+	Double    int64
+	Missing   int64
+	Truncated bool
+	Partial   bool
+	Issues    []*NFTSupplyIssueRsp
+}
+
+func MakeNFTSupplyAuditRsp(audit *NFTSupplyAudit) *NFTSupplyAuditRsp {
+	rsp := &NFTSupplyAuditRsp{
+		Name:      audit.AssetBasicName,
+		Time:      audit.Time,
+		Chains:    audit.Chains,
+		Tokens:    audit.Tokens,
+		Locked:    audit.Locked,
+		Double:    audit.Double,
+		Missing:   audit.Missing,
+		Truncated: audit.Truncated == 1,
+		Partial:   audit.Partial == 1,
+		Issues:    make([]*NFTSupplyIssueRsp, 0),
+	}
+	for _, issue := range audit.Issues {
+		rsp.Issues = append(rsp.Issues, &NFTSupplyIssueRsp{
+			TokenId: issue.TokenId,
+			Kind:    issue.Kind,
+			Chains:  issue.Chains,
+		})
+	}
+	return rsp
+}
+
+type NFTSupplyAuditsRsp struct {
+	TotalCount uint64
+	Broken     uint64
+	Audits     []*NFTSupplyAuditRsp
+}
+
+func MakeNFTSupplyAuditsRsp(audits []*NFTSupplyAudit) *NFTSupplyAuditsRsp {
+	rsp := &NFTSupplyAuditsRsp{
+		TotalCount: uint64(len(audits)),
+		Audits:     make([]*NFTSupplyAuditRsp, 0),
+	}
+	for _, audit := range audits {
+		if audit.Double > 0 || audit.Missing > 0 {
+			rsp.Broken++
+		}
+		rsp.Audits = append(rsp.Audits, MakeNFTSupplyAuditRsp(audit))
+	}
+	return rsp
+}
+
+type GovernanceEventsReq struct {
+	ChainId  uint64
+	PageSize int
+	PageNo   int
+}
+
+type GovernanceEventRsp struct {
+	TxHash   string
+	LogIndex uint64
+	ChainId  uint64
+	Contract string
+	Address  string
+	Event    string
+	Detail   string
+	Height   uint64
+	Time     uint64
+}
+
+func MakeGovernanceEventRsp(event *GovernanceEvent) *GovernanceEventRsp {
+	return &GovernanceEventRsp{
+		TxHash:   event.TxHash,
+		LogIndex: event.LogIndex,
+		ChainId:  event.ChainId,
+		Contract: event.Contract,
+		Address:  event.Address,
+		Event:    event.Event,
+		Detail:   event.Detail,
+		Height:   event.Height,
+		Time:     event.Time,
+	}
+}
+
+type GovernanceEventsRsp struct {
+	PageSize   int
+	PageNo     int
+	TotalPage  int
+	TotalCount int
+	Paused     []uint64 // chains whose wrapper is paused
+	Events     []*GovernanceEventRsp
+}
+
+func MakeGovernanceEventsRsp(pageSize, pageNo, totalPage, totalCount int, events []*GovernanceEvent, paused map[uint64]bool) *GovernanceEventsRsp {
+	rsp := &GovernanceEventsRsp{
+		PageSize:   pageSize,
+		PageNo:     pageNo,
+		TotalPage:  totalPage,
+		TotalCount: totalCount,
+		Paused:     make([]uint64, 0),
+		Events:     make([]*GovernanceEventRsp, 0),
+	}
+	for chainId := range paused {
+		rsp.Paused = append(rsp.Paused, chainId)
+	}
+	sort.Slice(rsp.Paused, func(i, j int) bool { return rsp.Paused[i] < rsp.Paused[j] })
+	for _, event := range events {
+		rsp.Events = append(rsp.Events, MakeGovernanceEventRsp(event))
+	}
+	return rsp
+}
+
+type AccountBalancesReq struct {
+}
+
+type AccountBalanceRsp struct {
+	ChainId    uint64
+	Role       string
+	Address    string
+	Token      string
+	Name       string
+	Balance    string
+	Min        string
+	BurnPerDay string
+	Runway     int64 // seconds, -1 when nothing is burnt
+	Low        bool
+	Time       int64
+}
+
+func MakeAccountBalanceRsp(balance *AccountBalance) *AccountBalanceRsp {
+	return &AccountBalanceRsp{
+		ChainId:    balance.ChainId,
+		Role:       balance.Role,
+		Address:    balance.Address,
+		Token:      balance.Token,
+		Name:       balance.Name,
+		Balance:    FormatAmount(balance.Balance, balance.Precision),
+		Min:        FormatAmount(balance.Min, balance.Precision),
+		BurnPerDay: FormatAmount(balance.Burn, balance.Precision),
+		Runway:     balance.Runway,
+		Low:        balance.Low == 1,
+		Time:       balance.Time,
+	}
+}
+
+type AccountBalancesRsp struct {
+	TotalCount uint64
+	Low        uint64
+	Balances   []*AccountBalanceRsp
+}
+
+func MakeAccountBalancesRsp(balances []*AccountBalance) *AccountBalancesRsp {
+	rsp := &AccountBalancesRsp{
+		TotalCount: uint64(len(balances)),
+		Balances:   make([]*AccountBalanceRsp, 0),
+	}
+	for _, balance := range balances {
+		if balance.Low == 1 {
+			rsp.Low++
+		}
+		rsp.Balances = append(rsp.Balances, MakeAccountBalanceRsp(balance))
+	}
+	return rsp
+}
+
 type PriceMarketRsp struct {
 	TokenBasicName string
 	MarketName     string
@@ -392,6 +571,7 @@ type GetFeeRsp struct {
 	UsdtAmount               string
 	TokenAmount              string
 	TokenAmountWithPrecision string
+	Paused                   bool // the wrapper of the source chain is paused
 }
 
 func MakeGetFeeRsp(srcChainId uint64, hash string, dstChainId uint64, usdtAmount *big.Float, tokenAmount *big.Float, tokenAmountWithPrecision *big.Float) *GetFeeRsp {
@@ -424,58 +604,58 @@ func MakeGetFeeRsp(srcChainId uint64, hash string, dstChainId uint64, usdtAmount
 	return getFeeRsp
 }
 
-//type CheckFeeReq struct {
-//	Hash    string
-//	ChainId uint64
-//}
-//
-//type CheckFeeRsp struct {
-//	ChainId     uint64
-//	Hash        string
-//	PayState    int
-//	Amount      string
-//	MinProxyFee string
-//}
-//
-//type CheckFeesReq struct {
-//	Checks []*CheckFeeReq
-//}
-//
-//type CheckFeesRsp struct {
-//	TotalCount uint64
-//	CheckFees  []*CheckFeeRsp
-//}
-//
-//func MakeCheckFeesRsp(checkFees []*CheckFee) *CheckFeesRsp {
-//	checkFeesRsp := &CheckFeesRsp{
-//		TotalCount: uint64(len(checkFees)),
-//	}
-//	for _, checkFee := range checkFees {
-//		checkFeesRsp.CheckFees = append(checkFeesRsp.CheckFees, MakeCheckFeeRsp(checkFee))
-//	}
-//	return checkFeesRsp
-//}
-//
-//func MakeCheckFeeRsp(checkFee *CheckFee) *CheckFeeRsp {
-//	checkFeeRsp := &CheckFeeRsp{
-//		ChainId:     checkFee.ChainId,
-//		Hash:        checkFee.Hash,
-//		PayState:    checkFee.PayState,
-//		Amount:      checkFee.Amount.String(),
-//		MinProxyFee: checkFee.MinProxyFee.String(),
-//	}
-//	{
-//		aaa, _ := checkFee.Amount.Float64()
-//		bbb := decimal.NewFromFloat(aaa)
-//		checkFeeRsp.Amount = bbb.String()
-//	}
-//	{
-//		aaa, _ := checkFee.MinProxyFee.Float64()
-//		bbb := decimal.NewFromFloat(aaa)
-//		checkFeeRsp.MinProxyFee = bbb.String()
-//	}
-//	return checkFeeRsp
-//}
+type CheckFeeReq struct {
+	Hash    string
+	ChainId uint64
+}
+
+type CheckFeeRsp struct {
+	ChainId     uint64
+	Hash        string
+	PayState    int
+	Amount      string
+	MinProxyFee string
+}
+
+type CheckFeesReq struct {
+	Checks []*CheckFeeReq
+}
+
+type CheckFeesRsp struct {
+	TotalCount uint64
+	CheckFees  []*CheckFeeRsp
+}
+
+func MakeCheckFeesRsp(checkFees []*CheckFee) *CheckFeesRsp {
+	checkFeesRsp := &CheckFeesRsp{
+		TotalCount: uint64(len(checkFees)),
+	}
+	for _, checkFee := range checkFees {
+		checkFeesRsp.CheckFees = append(checkFeesRsp.CheckFees, MakeCheckFeeRsp(checkFee))
+	}
+	return checkFeesRsp
+}
+
+func MakeCheckFeeRsp(checkFee *CheckFee) *CheckFeeRsp {
+	checkFeeRsp := &CheckFeeRsp{
+		ChainId:     checkFee.ChainId,
+		Hash:        checkFee.Hash,
+		PayState:    checkFee.PayState,
+		Amount:      checkFee.Amount.String(),
+		MinProxyFee: checkFee.MinProxyFee.String(),
+	}
+	{
+		aaa, _ := checkFee.Amount.Float64()
+		bbb := decimal.NewFromFloat(aaa)
+		checkFeeRsp.Amount = bbb.String()
+	}
+	{
+		aaa, _ := checkFee.MinProxyFee.Float64()
+		bbb := decimal.NewFromFloat(aaa)
+		checkFeeRsp.MinProxyFee = bbb.String()
+	}
+	return checkFeeRsp
+}
 
 type WrapperTransactionReq struct {
 	Hash string
@@ -492,7 +672,16 @@ type WrapperTransactionRsp struct {
 	ServerId     uint64
 	FeeTokenHash string
 	FeeAmount    string
+	TotalFee     string // the fee amount with the speed ups
 	State        uint64
+	Paused       bool
+}
+
+func wrapperTotalFee(transaction *WrapperTransaction) *BigInt {
+	if transaction.TotalFee == nil {
+		return transaction.FeeAmount
+	}
+	return transaction.TotalFee
 }
 
 func MakeWrapperTransactionRsp(transaction *WrapperTransaction) *WrapperTransactionRsp {
@@ -507,6 +696,7 @@ func MakeWrapperTransactionRsp(transaction *WrapperTransaction) *WrapperTransact
 		ServerId:     transaction.ServerId,
 		FeeTokenHash: transaction.FeeTokenHash,
 		FeeAmount:    transaction.FeeAmount.String(),
+		TotalFee:     wrapperTotalFee(transaction).String(),
 		State:        transaction.Status,
 	}
 	return transactionRsp
@@ -567,9 +757,38 @@ type TransactionRsp struct {
 	ServerId         uint64
 	FeeToken         *TokenRsp
 	FeeAmount        string
+	TotalFee         string
 	State            uint64
 	Asset            *NFTAssetRsp
 	TransactionState []*TransactionStateRsp
+	FeeHistory       []*FeeHistoryRsp   `json:",omitempty"`
+	Args             *CrossChainArgsRsp `json:",omitempty"`
+	RelayFailures    []*RelayFailureRsp `json:",omitempty"`
+	Paused           bool
+}
+
+// CrossChainArgsRsp are the args the source chain committed to poly, Error is
+// set when they can not be decoded or differ from the lock.
+type CrossChainArgsRsp struct {
+	ToContract string
+	Method     string
+	Asset      string
+	User       string
+	TokenId    string
+	TokenUri   string
+	Error      string
+}
+
+func MakeCrossChainArgsRsp(args *CrossChainArgs) *CrossChainArgsRsp {
+	return &CrossChainArgsRsp{
+		ToContract: args.ToContract,
+		Method:     args.Method,
+		Asset:      args.ArgsAsset,
+		User:       args.ArgsUser,
+		TokenId:    args.ArgsTokenId,
+		TokenUri:   args.ArgsTokenUri,
+		Error:      args.ArgsError,
+	}
 }
 
 func MakeTransactionRsp(transaction *SrcPolyDstRelation, chainsMap map[uint64]*Chain) *TransactionRsp {
@@ -582,6 +801,7 @@ func MakeTransactionRsp(transaction *SrcPolyDstRelation, chainsMap map[uint64]*C
 		DstChainId:  transaction.WrapperTransaction.DstChainId,
 		ServerId:    transaction.WrapperTransaction.ServerId,
 		FeeAmount:   transaction.WrapperTransaction.FeeAmount.String(),
+		TotalFee:    wrapperTotalFee(transaction.WrapperTransaction).String(),
 		TokenId:     transaction.SrcTransaction.SrcTransfer.Amount.String(),
 		DstUser:     transaction.SrcTransaction.SrcTransfer.DstUser,
 		State:       transaction.WrapperTransaction.Status,
@@ -597,6 +817,14 @@ func MakeTransactionRsp(transaction *SrcPolyDstRelation, chainsMap map[uint64]*C
 			feeAmount := bbb.Div(precision)
 			transactionRsp.FeeAmount = feeAmount.String()
 		}
+		{
+			bbb := decimal.NewFromBigInt(&wrapperTotalFee(transaction.WrapperTransaction).Int, 0)
+			totalFee := bbb.Div(precision)
+			transactionRsp.TotalFee = totalFee.String()
+		}
+	}
+	if transaction.SrcTransaction != nil && (transaction.SrcTransaction.Method != "" || transaction.SrcTransaction.ArgsError != "") {
+		transactionRsp.Args = MakeCrossChainArgsRsp(&transaction.SrcTransaction.CrossChainArgs)
 	}
 	if transaction.SrcTransaction != nil {
 		transactionRsp.TransactionState = append(transactionRsp.TransactionState, &TransactionStateRsp{
@@ -663,6 +891,83 @@ func MakeTransactionRsp(transaction *SrcPolyDstRelation, chainsMap map[uint64]*C
 	return transactionRsp
 }
 
+// FeeHistoryRsp is a payment of the fee of a wrapper transaction, the lock
+// first and then the speed ups. TotalFee is the fee paid until then.
+type FeeHistoryRsp struct {
+	Hash         string
+	User         string
+	Height       uint64
+	Time         uint64
+	FeeTokenHash string
+	FeeAmount    string
+	TotalFee     string
+}
+
+// MakeFeeHistoryRsp lists the fee payments of the wrapper transaction, a speed
+// up paid in another token is listed but not counted.
+func MakeFeeHistoryRsp(transaction *WrapperTransaction, speedUps []*WrapperSpeedUp, token *Token) []*FeeHistoryRsp {
+	format := func(amount *big.Int) string {
+		if token == nil {
+			return amount.String()
+		}
+		precision := decimal.NewFromInt(basedef.Int64FromFigure(int(token.TokenBasic.Precision)))
+		return decimal.NewFromBigInt(amount, 0).Div(precision).String()
+	}
+	total := new(big.Int).Set(&transaction.FeeAmount.Int)
+	history := []*FeeHistoryRsp{{
+		Hash:         transaction.Hash,
+		User:         transaction.User,
+		Height:       transaction.BlockHeight,
+		Time:         transaction.Time,
+		FeeTokenHash: transaction.FeeTokenHash,
+		FeeAmount:    format(&transaction.FeeAmount.Int),
+		TotalFee:     format(total),
+	}}
+	for _, speedUp := range speedUps {
+		if speedUp.FeeTokenHash == transaction.FeeTokenHash {
+			total.Add(total, &speedUp.FeeAmount.Int)
+		}
+		history = append(history, &FeeHistoryRsp{
+			Hash:         speedUp.TxHash,
+			User:         speedUp.User,
+			Height:       speedUp.Height,
+			Time:         speedUp.Time,
+			FeeTokenHash: speedUp.FeeTokenHash,
+			FeeAmount:    format(&speedUp.FeeAmount.Int),
+			TotalFee:     format(total),
+		})
+	}
+	return history
+}
+
+// RelayFailureRsp is a relay of the poly transaction which reverted on the
+// destination chain, Reason is empty when the node does not tell it.
+type RelayFailureRsp struct {
+	Hash    string
+	ChainId uint64
+	Relayer string
+	Reason  string
+	Fee     string
+	Height  uint64
+	Time    uint64
+}
+
+func MakeRelayFailuresRsp(failures []*DstRelayFailure) []*RelayFailureRsp {
+	failuresRsp := make([]*RelayFailureRsp, 0, len(failures))
+	for _, failure := range failures {
+		failuresRsp = append(failuresRsp, &RelayFailureRsp{
+			Hash:    failure.TxHash,
+			ChainId: failure.ChainId,
+			Relayer: failure.Relayer,
+			Reason:  failure.Reason,
+			Fee:     failure.Fee.String(),
+			Height:  failure.Height,
+			Time:    failure.Time,
+		})
+	}
+	return failuresRsp
+}
+
 type TransactionsOfAddressReq struct {
 	Addresses []string
 	PageSize  int
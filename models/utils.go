@@ -21,6 +21,8 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"math/big"
+
+	"github.com/polynetwork/poly-nft-bridge/utils/decimal"
 )
 
 type BigInt struct {
@@ -44,8 +46,16 @@ func (bigInt *BigInt) Value() (driver.Value, error) {
 }
 
 func (bigInt *BigInt) Scan(v interface{}) error {
-	value, ok := v.([]byte)
-	if !ok {
+	var value []byte
+	switch v := v.(type) {
+	case []byte:
+		value = v
+	case string:
+		value = []byte(v)
+	case int64:
+		bigInt.Int = *new(big.Int).SetInt64(v)
+		return nil
+	default:
 		return fmt.Errorf("type error, %v", v)
 	}
 	if string(value) == "null" {
@@ -58,3 +68,11 @@ func (bigInt *BigInt) Scan(v interface{}) error {
 	bigInt.Int = *data
 	return nil
 }
+
+// FormatAmount renders an amount of the precision in whole tokens.
+func FormatAmount(amount *BigInt, precision uint64) string {
+	if amount == nil {
+		return "0"
+	}
+	return decimal.NewFromBigInt(&amount.Int, -int32(precision)).String()
+}
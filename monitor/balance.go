@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/monitordao"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/utils/decimal"
+)
+
+const (
+	defaultBalanceWindow = 86400
+
+	nativePrecision = 18
+	secondsPerDay   = 86400
+)
+
+// BalanceNode is the chain side read by the balance rule.
+type BalanceNode interface {
+	NativeBalance(owner common.Address) (*big.Int, error)
+	ERC20Balance(asset, owner common.Address) (*big.Int, error)
+	WrapFeeCollector(wrapper common.Address) (common.Address, error)
+}
+
+// NewBalanceNodes returns the nodes of the evm chains and the wrappers of the
+// chains which have one configured.
+func NewBalanceNodes(cfgs []*conf.ChainListenConfig) (map[uint64]BalanceNode, map[uint64]common.Address) {
+	nodes := make(map[uint64]BalanceNode)
+	wrappers := make(map[uint64]common.Address)
+	for _, cfg := range cfgs {
+		if cfg.ChainId == basedef.POLY_CROSSCHAIN_ID {
+			continue
+		}
+		nodes[cfg.ChainId] = eth_sdk.NewEthereumSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+		if cfg.WrapperContract != "" {
+			wrappers[cfg.ChainId] = common.HexToAddress(cfg.WrapperContract)
+		}
+	}
+	return nodes, wrappers
+}
+
+type balanceAccount struct {
+	chainId uint64
+	role    string
+	address common.Address
+	min     *big.Int
+	runway  int64
+}
+
+// balanceRule reads the native balance of the configured accounts and the fee
+// token balances of the wrapper fee collectors, saves them into
+// account_balances and fires when a balance is below its minimum or a relayer
+// runs out within its runway. The relayer burn is the fees of the unlocks on
+// its chain in the window.
+type balanceRule struct {
+	db       monitordao.MonitorDao
+	nodes    map[uint64]BalanceNode
+	wrappers map[uint64]common.Address
+	window   int64
+	accounts []*balanceAccount
+}
+
+func NewBalanceRule(
+	cfg *conf.BalanceMonitorConfig,
+	db monitordao.MonitorDao,
+	nodes map[uint64]BalanceNode,
+	wrappers map[uint64]common.Address,
+) (Rule, error) {
+	if cfg == nil {
+		cfg = &conf.BalanceMonitorConfig{}
+	}
+	if cfg.Window == 0 {
+		cfg.Window = defaultBalanceWindow
+	}
+	accounts := make([]*balanceAccount, 0, len(cfg.Accounts))
+	for _, account := range cfg.Accounts {
+		if _, ok := nodes[account.ChainId]; !ok {
+			return nil, fmt.Errorf("balance account %s of chain %d without node", account.Address, account.ChainId)
+		}
+		if account.Role != basedef.ACCOUNT_RELAYER && account.Role != basedef.ACCOUNT_ADMIN {
+			return nil, fmt.Errorf("balance account %s with unknown role %s", account.Address, account.Role)
+		}
+		min := big.NewInt(0)
+		if account.Min != "" {
+			value, err := decimal.NewFromString(account.Min)
+			if err != nil {
+				return nil, fmt.Errorf("balance account %s min %s, err: %v", account.Address, account.Min, err)
+			}
+			min = value.Shift(nativePrecision).BigInt()
+		}
+		accounts = append(accounts, &balanceAccount{
+			chainId: account.ChainId,
+			role:    account.Role,
+			address: common.HexToAddress(account.Address),
+			min:     min,
+			runway:  account.Runway,
+		})
+	}
+	return &balanceRule{
+		db:       db,
+		nodes:    nodes,
+		wrappers: wrappers,
+		window:   cfg.Window,
+		accounts: accounts,
+	}, nil
+}
+
+func (r *balanceRule) Name() string {
+	return "balance"
+}
+
+func (r *balanceRule) Evaluate(now int64) ([]*alert.Alert, error) {
+	balances := make([]*models.AccountBalance, 0)
+	alerts := make([]*alert.Alert, 0)
+	for _, account := range r.accounts {
+		amount, err := r.nodes[account.chainId].NativeBalance(account.address)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d balance of %s, err: %v", account.chainId, account.address.Hex(), err)
+		}
+		burn := big.NewInt(0)
+		if account.role == basedef.ACCOUNT_RELAYER {
+			fees, err := r.db.GetDstTransactionFees(account.chainId, hexAddress(account.address), uint64(now-r.window))
+			if err != nil {
+				return nil, err
+			}
+			burn = new(big.Int).Div(new(big.Int).Mul(fees, big.NewInt(secondsPerDay)), big.NewInt(r.window))
+		}
+		balance := &models.AccountBalance{
+			ChainId:   account.chainId,
+			Address:   hexAddress(account.address),
+			Token:     basedef.NATIVE_TOKEN,
+			Role:      account.role,
+			Name:      "native",
+			Precision: nativePrecision,
+			Balance:   models.NewBigInt(amount),
+			Min:       models.NewBigInt(account.min),
+			Burn:      models.NewBigInt(burn),
+			Runway:    runway(amount, burn),
+			Time:      now,
+		}
+		if account.runway > 0 && balance.Runway >= 0 && balance.Runway < account.runway {
+			alerts = append(alerts, &alert.Alert{
+				Level: alert.LevelWarn,
+				Key:   fmt.Sprintf("runway:%d:%s", balance.ChainId, balance.Address),
+				Title: fmt.Sprintf("%s %s runs out soon on chain %d", balance.Role, balance.Address, balance.ChainId),
+				Content: fmt.Sprintf("balance %s lasts %d seconds at %s a day", models.FormatAmount(balance.Balance, balance.Precision),
+					balance.Runway, models.FormatAmount(balance.Burn, balance.Precision)),
+			})
+		}
+		balances = append(balances, balance)
+	}
+	collectors, err := r.readCollectors(now)
+	if err != nil {
+		return nil, err
+	}
+	balances = append(balances, collectors...)
+
+	for _, balance := range balances {
+		if balance.Min.Sign() > 0 && balance.Balance.Cmp(&balance.Min.Int) < 0 {
+			balance.Low = 1
+			alerts = append(alerts, &alert.Alert{
+				Level: alert.LevelCritical,
+				Key:   fmt.Sprintf("balance:%d:%s:%s", balance.ChainId, balance.Address, balance.Token),
+				Title: fmt.Sprintf("%s %s balance is low on chain %d", balance.Role, balance.Address, balance.ChainId),
+				Content: fmt.Sprintf("balance %s, min %s", models.FormatAmount(balance.Balance, balance.Precision),
+					models.FormatAmount(balance.Min, balance.Precision)),
+			})
+		}
+	}
+	if err := r.db.SaveAccountBalances(balances); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// readCollectors reads the balances of the wrapper fee collector in each fee
+// token of its chain.
+func (r *balanceRule) readCollectors(now int64) ([]*models.AccountBalance, error) {
+	balances := make([]*models.AccountBalance, 0)
+	chains := make([]uint64, 0, len(r.wrappers))
+	for chainId := range r.wrappers {
+		chains = append(chains, chainId)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+	for _, chainId := range chains {
+		node := r.nodes[chainId]
+		wrapper := r.wrappers[chainId]
+		collector, err := node.WrapFeeCollector(wrapper)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d fee collector, err: %v", chainId, err)
+		}
+		tokens, err := r.db.GetFeeTokens(chainId)
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range tokens {
+			var balance *big.Int
+			if strings.EqualFold(token.Hash, basedef.NATIVE_TOKEN) {
+				balance, err = node.NativeBalance(collector)
+			} else {
+				balance, err = node.ERC20Balance(common.HexToAddress(token.Hash), collector)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("chain %d balance of fee collector in %s, err: %v", chainId, token.Hash, err)
+			}
+			balances = append(balances, &models.AccountBalance{
+				ChainId:   chainId,
+				Address:   hexAddress(collector),
+				Token:     strings.ToLower(token.Hash),
+				Role:      basedef.ACCOUNT_FEE_COLLECTOR,
+				Name:      token.Name,
+				Precision: token.Precision,
+				Balance:   models.NewBigInt(balance),
+				Min:       models.NewBigIntFromInt(0),
+				Burn:      models.NewBigIntFromInt(0),
+				Runway:    -1,
+				Time:      now,
+			})
+		}
+	}
+	return balances, nil
+}
+
+func hexAddress(address common.Address) string {
+	return strings.ToLower(address.Hex()[2:])
+}
+
+// runway returns the seconds the balance lasts at the burn a day, -1 when
+// nothing is burnt.
+func runway(balance, burn *big.Int) int64 {
+	if burn.Sign() <= 0 {
+		return -1
+	}
+	seconds := new(big.Int).Div(new(big.Int).Mul(balance, big.NewInt(secondsPerDay)), burn)
+	if !seconds.IsInt64() {
+		return math.MaxInt64
+	}
+	return seconds.Int64()
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	relayer   = "7777777777777777777777777777777777777777"
+	relayer2  = "6666666666666666666666666666666666666666"
+	collector = "8888888888888888888888888888888888888888"
+	feeToken  = "9999999999999999999999999999999999999999"
+)
+
+type balanceNode struct {
+	balances map[common.Address]*big.Int
+	erc20    map[common.Address]*big.Int
+}
+
+func (n *balanceNode) NativeBalance(owner common.Address) (*big.Int, error) {
+	return n.balances[owner], nil
+}
+
+func (n *balanceNode) ERC20Balance(asset, owner common.Address) (*big.Int, error) {
+	return n.erc20[owner], nil
+}
+
+func (n *balanceNode) WrapFeeCollector(wrapper common.Address) (common.Address, error) {
+	return common.HexToAddress(collector), nil
+}
+
+func ether(tenths int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(tenths), big.NewInt(100000000000000000))
+}
+
+func TestBalanceRule(t *testing.T) {
+	db, dao := newTestDB(t, "monitor_balance")
+	eth := basedef.ETHEREUM_CROSSCHAIN_ID
+	// 0.5 spent in the last day, the first unlock is out of the window
+	for i, tt := range []uint64{9000, 50000, 90000} {
+		fee := ether(int64(i + 1))
+		if i == 0 {
+			fee = ether(10)
+		}
+		assert.NoError(t, db.Create(&models.DstTransaction{Hash: string(rune('a'+i)) + "000", ChainId: eth, Time: tt,
+			Fee: models.NewBigInt(fee), Sender: relayer}).Error)
+	}
+	// the unlocks of another relayer are not burnt by the first one
+	assert.NoError(t, db.Create(&models.DstTransaction{Hash: "eth2", ChainId: eth, Time: 90000,
+		Fee: models.NewBigInt(ether(20)), Sender: relayer2}).Error)
+	assert.NoError(t, db.Create(&models.DstTransaction{Hash: "bsc0", ChainId: basedef.BSC_CROSSCHAIN_ID, Time: 90000,
+		Fee: models.NewBigInt(ether(10))}).Error)
+	for _, hash := range []string{basedef.NATIVE_TOKEN, feeToken} {
+		assert.NoError(t, db.Create(&models.Token{Hash: hash, ChainId: eth, Name: "fee", Precision: 6}).Error)
+	}
+
+	node := &balanceNode{
+		balances: map[common.Address]*big.Int{common.HexToAddress(relayer): ether(12), common.HexToAddress(relayer2): ether(100),
+			common.HexToAddress(collector): ether(3)},
+		erc20: map[common.Address]*big.Int{common.HexToAddress(collector): big.NewInt(2500000)},
+	}
+	nodes := map[uint64]BalanceNode{eth: node}
+	rule, err := NewBalanceRule(&conf.BalanceMonitorConfig{Accounts: []*conf.BalanceAccountConfig{
+		{ChainId: eth, Role: basedef.ACCOUNT_RELAYER, Address: "0x" + relayer, Min: "1", Runway: 3 * 86400},
+		{ChainId: eth, Role: basedef.ACCOUNT_RELAYER, Address: "0x" + relayer2},
+	}}, dao, nodes, map[uint64]common.Address{eth: common.HexToAddress("0x01")})
+	assert.NoError(t, err)
+
+	alerts, err := rule.Evaluate(100000)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(alerts))
+	assert.Equal(t, alert.LevelWarn, alerts[0].Level)
+	assert.Equal(t, "balance 1.2 lasts 207360 seconds at 0.5 a day", alerts[0].Content)
+
+	balances := make([]*models.AccountBalance, 0)
+	assert.NoError(t, db.Order("role asc").Order("token asc").Order("address asc").Find(&balances).Error)
+	assert.Equal(t, 4, len(balances))
+	assert.Equal(t, []string{basedef.ACCOUNT_FEE_COLLECTOR, basedef.ACCOUNT_FEE_COLLECTOR, basedef.ACCOUNT_RELAYER, basedef.ACCOUNT_RELAYER},
+		[]string{balances[0].Role, balances[1].Role, balances[2].Role, balances[3].Role})
+	assert.Equal(t, []string{"2", "0.5"},
+		[]string{models.FormatAmount(balances[2].Burn, 18), models.FormatAmount(balances[3].Burn, 18)})
+	assert.Equal(t, "0.3", models.FormatAmount(balances[0].Balance, 18))
+	assert.Equal(t, "2.5", models.FormatAmount(balances[1].Balance, balances[1].Precision))
+	assert.Equal(t, int64(-1), balances[1].Runway)
+
+	node.balances[common.HexToAddress(relayer)] = ether(9)
+	alerts, err = rule.Evaluate(100060)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(alerts))
+	assert.Equal(t, alert.LevelCritical, alerts[1].Level)
+	assert.Equal(t, "balance 0.9, min 1", alerts[1].Content)
+	relayerBalance := new(models.AccountBalance)
+	assert.NoError(t, db.Where("role = ? and address = ?", basedef.ACCOUNT_RELAYER, relayer).First(relayerBalance).Error)
+	assert.Equal(t, int64(1), relayerBalance.Low)
+
+	_, err = NewBalanceRule(&conf.BalanceMonitorConfig{Accounts: []*conf.BalanceAccountConfig{
+		{ChainId: basedef.BSC_CROSSCHAIN_ID, Role: basedef.ACCOUNT_RELAYER, Address: relayer},
+	}}, dao, nodes, nil)
+	assert.Error(t, err)
+}
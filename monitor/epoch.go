@@ -0,0 +1,234 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/sdk/poly_sdk"
+)
+
+const defaultEpochGrace = 600
+
+// EpochNode is the side chain read by the epoch rule.
+type EpochNode interface {
+	ECCDEpoch(eccm common.Address) (uint32, []common.Address, error)
+}
+
+// PolyEpochNode is the poly side read by the epoch rule.
+type PolyEpochNode interface {
+	GetCurrentBlockHeight() (uint64, error)
+	GetEpochHeight(height uint64) (uint64, error)
+	GetEpoch(height uint64) (*poly_sdk.PolyEpoch, error)
+}
+
+// NewEpochNodes returns the poly node, the nodes of the evm chains and the
+// eccm of the chains which have one configured. The poly node is nil without
+// the poly chain.
+func NewEpochNodes(cfgs []*conf.ChainListenConfig) (PolyEpochNode, map[uint64]EpochNode, map[uint64]common.Address) {
+	var poly PolyEpochNode
+	nodes := make(map[uint64]EpochNode)
+	eccms := make(map[uint64]common.Address)
+	for _, cfg := range cfgs {
+		if cfg.ChainId == basedef.POLY_CROSSCHAIN_ID {
+			poly = poly_sdk.NewPolySDKPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+			continue
+		}
+		if cfg.ECCMContract == "" {
+			continue
+		}
+		nodes[cfg.ChainId] = eth_sdk.NewEthereumSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+		eccms[cfg.ChainId] = common.HexToAddress(cfg.ECCMContract)
+	}
+	return poly, nodes, eccms
+}
+
+// EpochState is the poly epoch the eccd of a side chain keeps.
+type EpochState struct {
+	ChainId uint64
+	ECCM    common.Address
+	Height  uint64
+	Keepers []common.Address
+	Synced  bool
+}
+
+// EpochChecker compares the poly epoch kept by the eccd of each side chain
+// with the current one of poly. Every change of the poly book keepers has to
+// be submitted to the eccm by changeBookKeeper, or the unlocks signed by the
+// new book keepers fail.
+type EpochChecker struct {
+	poly  PolyEpochNode
+	nodes map[uint64]EpochNode
+	eccms map[uint64]common.Address
+	epoch *poly_sdk.PolyEpoch // the current epoch read last
+}
+
+func NewEpochChecker(poly PolyEpochNode, nodes map[uint64]EpochNode, eccms map[uint64]common.Address) (*EpochChecker, error) {
+	if poly == nil {
+		return nil, fmt.Errorf("epoch checker without poly node")
+	}
+	for chainId := range eccms {
+		if _, ok := nodes[chainId]; !ok {
+			return nil, fmt.Errorf("eccm of chain %d without node", chainId)
+		}
+	}
+	return &EpochChecker{poly: poly, nodes: nodes, eccms: eccms}, nil
+}
+
+// Check returns the current poly epoch and the state of each side chain in
+// the order of chain id.
+func (c *EpochChecker) Check() (*poly_sdk.PolyEpoch, []*EpochState, error) {
+	latest, err := c.poly.GetCurrentBlockHeight()
+	if err != nil {
+		return nil, nil, fmt.Errorf("poly latest height, err: %v", err)
+	}
+	height, err := c.poly.GetEpochHeight(latest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("poly epoch of block %d, err: %v", latest, err)
+	}
+	if c.epoch == nil || c.epoch.Height != height {
+		if c.epoch, err = c.poly.GetEpoch(height); err != nil {
+			return nil, nil, fmt.Errorf("poly epoch %d, err: %v", height, err)
+		}
+	}
+
+	chains := make([]uint64, 0, len(c.eccms))
+	for chainId := range c.eccms {
+		chains = append(chains, chainId)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+	states := make([]*EpochState, 0, len(chains))
+	for _, chainId := range chains {
+		eccdHeight, keepers, err := c.nodes[chainId].ECCDEpoch(c.eccms[chainId])
+		if err != nil {
+			return nil, nil, fmt.Errorf("chain %d eccd epoch, err: %v", chainId, err)
+		}
+		states = append(states, &EpochState{
+			ChainId: chainId,
+			ECCM:    c.eccms[chainId],
+			Height:  uint64(eccdHeight),
+			Keepers: keepers,
+			Synced:  uint64(eccdHeight) == c.epoch.Height && sameKeepers(keepers, c.epoch.Keepers),
+		})
+	}
+	return c.epoch, states, nil
+}
+
+// Changes returns the poly epochs after the one at height up to the current
+// one. They are submitted in order, since each change is verified with the
+// book keepers of the epoch before it.
+func (c *EpochChecker) Changes(height uint64) ([]*poly_sdk.PolyEpoch, error) {
+	if c.epoch == nil || c.epoch.Height <= height {
+		return nil, nil
+	}
+	changes := []*poly_sdk.PolyEpoch{c.epoch}
+	for next := c.epoch.Height; next > 0; {
+		prev, err := c.poly.GetEpochHeight(next - 1)
+		if err != nil {
+			return nil, fmt.Errorf("poly epoch of block %d, err: %v", next-1, err)
+		}
+		if prev <= height {
+			break
+		}
+		epoch, err := c.poly.GetEpoch(prev)
+		if err != nil {
+			return nil, fmt.Errorf("poly epoch %d, err: %v", prev, err)
+		}
+		changes = append([]*poly_sdk.PolyEpoch{epoch}, changes...)
+		next = prev
+	}
+	return changes, nil
+}
+
+func sameKeepers(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	keepers := make(map[common.Address]bool, len(a))
+	for _, keeper := range a {
+		keepers[keeper] = true
+	}
+	for _, keeper := range b {
+		if !keepers[keeper] {
+			return false
+		}
+	}
+	return true
+}
+
+// epochRule fires when the eccd of a side chain keeps another poly epoch than
+// the current one for longer than grace seconds.
+type epochRule struct {
+	checker *EpochChecker
+	grace   int64
+	since   map[uint64]int64
+}
+
+func NewEpochRule(cfg *conf.EpochMonitorConfig, checker *EpochChecker) Rule {
+	if cfg == nil {
+		cfg = &conf.EpochMonitorConfig{}
+	}
+	if cfg.Grace == 0 {
+		cfg.Grace = defaultEpochGrace
+	}
+	return &epochRule{checker: checker, grace: cfg.Grace, since: make(map[uint64]int64)}
+}
+
+func (r *epochRule) Name() string {
+	return "epoch"
+}
+
+func (r *epochRule) Evaluate(now int64) ([]*alert.Alert, error) {
+	epoch, states, err := r.checker.Check()
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]*alert.Alert, 0)
+	for _, state := range states {
+		if state.Synced {
+			delete(r.since, state.ChainId)
+			continue
+		}
+		since, ok := r.since[state.ChainId]
+		if !ok {
+			r.since[state.ChainId] = now
+			since = now
+		}
+		if now-since < r.grace {
+			continue
+		}
+		changes, err := r.checker.Changes(state.Height)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, &alert.Alert{
+			Level: alert.LevelCritical,
+			Key:   fmt.Sprintf("epoch:%d", state.ChainId),
+			Title: fmt.Sprintf("chain %d eccm misses poly epoch %d", state.ChainId, epoch.Height),
+			Content: fmt.Sprintf("eccd keeps the epoch of poly block %d with %d keepers for %d seconds, %d changes to submit by changeBookKeeper",
+				state.Height, len(state.Keepers), now-since, len(changes)),
+		})
+	}
+	return alerts, nil
+}
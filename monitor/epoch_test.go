@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/sdk/poly_sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+// polyEpochNode starts an epoch at each height of epochs.
+type polyEpochNode struct {
+	height uint64
+	epochs []uint64
+	reads  int
+}
+
+func (n *polyEpochNode) GetCurrentBlockHeight() (uint64, error) {
+	return n.height, nil
+}
+
+func (n *polyEpochNode) GetEpochHeight(height uint64) (uint64, error) {
+	epoch := uint64(0)
+	for _, start := range n.epochs {
+		if start <= height {
+			epoch = start
+		}
+	}
+	return epoch, nil
+}
+
+func (n *polyEpochNode) GetEpoch(height uint64) (*poly_sdk.PolyEpoch, error) {
+	for _, start := range n.epochs {
+		if start == height {
+			n.reads++
+			return &poly_sdk.PolyEpoch{Height: height, Keepers: epochKeepers(height)}, nil
+		}
+	}
+	return nil, fmt.Errorf("block %d does not start an epoch", height)
+}
+
+func epochKeepers(height uint64) []common.Address {
+	return []common.Address{common.BigToAddress(common.Big1), common.BytesToAddress([]byte(fmt.Sprint(height)))}
+}
+
+type eccdNode struct {
+	height  uint32
+	keepers []common.Address
+}
+
+func (n *eccdNode) ECCDEpoch(eccm common.Address) (uint32, []common.Address, error) {
+	return n.height, n.keepers, nil
+}
+
+func TestEpochRule(t *testing.T) {
+	eth, bsc := basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID
+	poly := &polyEpochNode{height: 150, epochs: []uint64{0, 100}}
+	nodes := map[uint64]EpochNode{
+		eth: &eccdNode{height: 100, keepers: epochKeepers(100)},
+		bsc: &eccdNode{height: 100, keepers: epochKeepers(100)},
+	}
+	eccms := map[uint64]common.Address{eth: common.HexToAddress("0x02"), bsc: common.HexToAddress("0x06")}
+	checker, err := NewEpochChecker(poly, nodes, eccms)
+	assert.NoError(t, err)
+	rule := NewEpochRule(&conf.EpochMonitorConfig{Grace: 300}, checker)
+
+	alerts, err := rule.Evaluate(1000)
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	// poly changes its book keepers twice, eth follows the first one only
+	poly.epochs = append(poly.epochs, 200, 300)
+	poly.height = 320
+	nodes[eth].(*eccdNode).height = 200
+	nodes[eth].(*eccdNode).keepers = epochKeepers(200)
+	alerts, err = rule.Evaluate(1100)
+	assert.NoError(t, err)
+	assert.Empty(t, alerts, "the relayers change the epoch within the grace")
+
+	alerts, err = rule.Evaluate(1400)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(alerts))
+	assert.Equal(t, alert.LevelCritical, alerts[0].Level)
+	assert.Equal(t, "chain 2 eccm misses poly epoch 300", alerts[0].Title)
+	assert.Equal(t, "eccd keeps the epoch of poly block 200 with 2 keepers for 300 seconds, 1 changes to submit by changeBookKeeper",
+		alerts[0].Content)
+	assert.Equal(t, fmt.Sprintf("epoch:%d", bsc), alerts[1].Key)
+
+	changes, err := checker.Changes(100)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, []uint64{200, 300}, []uint64{changes[0].Height, changes[1].Height})
+	assert.Equal(t, 4, poly.reads, "the current epoch is read once")
+
+	// the keepers of the eccd have to be the ones of the epoch as well
+	nodes[eth].(*eccdNode).height = 300
+	nodes[bsc].(*eccdNode).height = 300
+	nodes[bsc].(*eccdNode).keepers = epochKeepers(300)
+	_, states, err := checker.Check()
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, []bool{states[0].Synced, states[1].Synced})
+
+	_, err = NewEpochChecker(nil, nodes, eccms)
+	assert.Error(t, err)
+}
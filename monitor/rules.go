@@ -0,0 +1,312 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/monitordao"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/sdk/poly_sdk"
+)
+
+const (
+	defaultRulesInterval = 60
+	defaultRulesStalled  = 600
+	defaultRulesLag      = 100
+	defaultRulesPending  = 3600
+
+	// hashes of the pending wrapper transactions listed in an alert
+	pendingListed = 5
+)
+
+// HeightNode is the chain side read by the lag rule.
+type HeightNode interface {
+	GetLatestHeight() (uint64, error)
+}
+
+type polyHeightNode struct {
+	*poly_sdk.PolySDKPro
+}
+
+func (n *polyHeightNode) GetLatestHeight() (uint64, error) {
+	return n.GetCurrentBlockHeight()
+}
+
+// NewHeightNodes returns the nodes of all listened chains.
+func NewHeightNodes(cfgs []*conf.ChainListenConfig) map[uint64]HeightNode {
+	nodes := make(map[uint64]HeightNode)
+	for _, cfg := range cfgs {
+		if cfg.ChainId == basedef.POLY_CROSSCHAIN_ID {
+			nodes[cfg.ChainId] = &polyHeightNode{poly_sdk.NewPolySDKPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)}
+		} else {
+			nodes[cfg.ChainId] = eth_sdk.NewEthereumSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+		}
+	}
+	return nodes
+}
+
+// Rule returns an alert for each condition it finds. The key of an alert
+// names the condition, so the engine knows when it is resolved.
+type Rule interface {
+	Name() string
+	Evaluate(now int64) ([]*alert.Alert, error)
+}
+
+// RuleEngine evaluates the rules periodically. An alert is sent on every
+// evaluation while its condition holds, the notifier throttle decides how
+// often it reaches the sinks, and an info alert is sent once it is resolved.
+type RuleEngine struct {
+	cfg      *conf.AlertRulesConfig
+	rules    []Rule
+	notifier *alert.Notifier
+	active   map[string]map[string]*alert.Alert // rule name to the firing alerts by key
+	exit     chan bool
+}
+
+func NewRuleEngine(cfg *conf.AlertRulesConfig, db monitordao.MonitorDao, nodes map[uint64]HeightNode, notifier *alert.Notifier) *RuleEngine {
+	if cfg == nil {
+		cfg = &conf.AlertRulesConfig{}
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultRulesInterval
+	}
+	if cfg.Stalled == 0 {
+		cfg.Stalled = defaultRulesStalled
+	}
+	if cfg.Lag == 0 {
+		cfg.Lag = defaultRulesLag
+	}
+	if cfg.Pending == 0 {
+		cfg.Pending = defaultRulesPending
+	}
+	rules := []Rule{
+		&stalledRule{db: db, after: cfg.Stalled, seen: make(map[uint64]*heightSeen)},
+		&lagRule{db: db, nodes: nodes, lag: cfg.Lag},
+		&pendingRule{db: db, after: cfg.Pending},
+	}
+	return &RuleEngine{
+		cfg:      cfg,
+		rules:    rules,
+		notifier: notifier,
+		active:   make(map[string]map[string]*alert.Alert),
+		exit:     make(chan bool, 0),
+	}
+}
+
+// AddRule adds a rule before the engine starts.
+func (e *RuleEngine) AddRule(rule Rule) {
+	e.rules = append(e.rules, rule)
+}
+
+func (e *RuleEngine) Start() {
+	logs.Info("start alert rules, rules: %d", len(e.rules))
+	go e.run()
+}
+
+func (e *RuleEngine) Stop() {
+	e.exit <- true
+	logs.Info("stop alert rules")
+}
+
+func (e *RuleEngine) run() {
+	ticker := time.NewTicker(time.Second * time.Duration(e.cfg.Interval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.safeEvaluate()
+		case <-e.exit:
+			return
+		}
+	}
+}
+
+func (e *RuleEngine) safeEvaluate() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("alert rules, recover info: %s", string(debug.Stack()))
+		}
+	}()
+	e.Evaluate(time.Now().Unix())
+}
+
+// Evaluate runs all rules at the given time. A failing rule is logged and
+// keeps its firing alerts until it succeeds again.
+func (e *RuleEngine) Evaluate(now int64) {
+	for _, rule := range e.rules {
+		alerts, err := rule.Evaluate(now)
+		if err != nil {
+			logs.Error("alert rule %s failed, err: %v", rule.Name(), err)
+			continue
+		}
+		firing := make(map[string]*alert.Alert)
+		for _, a := range alerts {
+			a.Source = rule.Name()
+			a.Time = now
+			firing[a.Key] = a
+			e.notifier.Notify(a)
+		}
+		for key, a := range e.active[rule.Name()] {
+			if _, ok := firing[key]; ok {
+				continue
+			}
+			e.notifier.Forget(key)
+			e.notifier.Notify(&alert.Alert{
+				Level:   alert.LevelInfo,
+				Source:  rule.Name(),
+				Title:   "resolved: " + a.Title,
+				Content: a.Content,
+				Time:    now,
+			})
+		}
+		e.active[rule.Name()] = firing
+	}
+}
+
+type heightSeen struct {
+	height uint64
+	since  int64
+}
+
+// stalledRule fires when the listen height of a chain stays the same for
+// longer than after seconds.
+type stalledRule struct {
+	db    monitordao.MonitorDao
+	after int64
+	seen  map[uint64]*heightSeen
+}
+
+func (r *stalledRule) Name() string {
+	return "stalled"
+}
+
+func (r *stalledRule) Evaluate(now int64) ([]*alert.Alert, error) {
+	chains, err := r.db.GetChains()
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]*alert.Alert, 0)
+	for _, chain := range chains {
+		seen, ok := r.seen[chain.ChainId]
+		if !ok || seen.height != chain.Height {
+			r.seen[chain.ChainId] = &heightSeen{height: chain.Height, since: now}
+			continue
+		}
+		if now-seen.since < r.after {
+			continue
+		}
+		alerts = append(alerts, &alert.Alert{
+			Level:   alert.LevelCritical,
+			Key:     fmt.Sprintf("stalled:%d", chain.ChainId),
+			Title:   fmt.Sprintf("chain %d is not listening", chain.ChainId),
+			Content: fmt.Sprintf("height %d has not moved for %d seconds", chain.Height, now-seen.since),
+		})
+	}
+	return alerts, nil
+}
+
+// lagRule fires when the listen height of a chain is more than lag blocks
+// behind its node. A chain without a node is skipped.
+type lagRule struct {
+	db    monitordao.MonitorDao
+	nodes map[uint64]HeightNode
+	lag   uint64
+}
+
+func (r *lagRule) Name() string {
+	return "lag"
+}
+
+func (r *lagRule) Evaluate(now int64) ([]*alert.Alert, error) {
+	chains, err := r.db.GetChains()
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]*alert.Alert, 0)
+	for _, chain := range chains {
+		node, ok := r.nodes[chain.ChainId]
+		if !ok {
+			continue
+		}
+		height, err := node.GetLatestHeight()
+		if err != nil {
+			return nil, fmt.Errorf("chain %d latest height, err: %v", chain.ChainId, err)
+		}
+		if height <= chain.Height+r.lag {
+			continue
+		}
+		alerts = append(alerts, &alert.Alert{
+			Level:   alert.LevelWarn,
+			Key:     fmt.Sprintf("lag:%d", chain.ChainId),
+			Title:   fmt.Sprintf("chain %d is lagging", chain.ChainId),
+			Content: fmt.Sprintf("listen height %d, node height %d", chain.Height, height),
+		})
+	}
+	return alerts, nil
+}
+
+// pendingRule fires for each source chain with wrapper transactions that are
+// not finished after seconds.
+type pendingRule struct {
+	db    monitordao.MonitorDao
+	after int64
+}
+
+func (r *pendingRule) Name() string {
+	return "pending"
+}
+
+func (r *pendingRule) Evaluate(now int64) ([]*alert.Alert, error) {
+	txs, err := r.db.GetPendingWrapperTransactions(uint64(now - r.after))
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[uint64][]string)
+	for _, tx := range txs {
+		hashes[tx.SrcChainId] = append(hashes[tx.SrcChainId], tx.Hash)
+	}
+	chains := make([]uint64, 0, len(hashes))
+	for chainId := range hashes {
+		chains = append(chains, chainId)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+	alerts := make([]*alert.Alert, 0)
+	for _, chainId := range chains {
+		listed := hashes[chainId]
+		if len(listed) > pendingListed {
+			listed = listed[:pendingListed]
+		}
+		alerts = append(alerts, &alert.Alert{
+			Level: alert.LevelWarn,
+			Key:   fmt.Sprintf("pending:%d", chainId),
+			Title: fmt.Sprintf("There is unfinished transactions of chain %d", chainId),
+			Content: fmt.Sprintf("%d wrapper transactions are pending over %d seconds, oldest: %s",
+				len(hashes[chainId]), r.after, strings.Join(listed, ", ")),
+		})
+	}
+	return alerts, nil
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type heightNode struct {
+	height uint64
+	err    error
+}
+
+func (n *heightNode) GetLatestHeight() (uint64, error) {
+	return n.height, n.err
+}
+
+func (s *recordSink) titles() []string {
+	titles := make([]string, 0, len(s.alerts))
+	for _, a := range s.alerts {
+		titles = append(titles, a.Title)
+	}
+	s.alerts = s.alerts[:0]
+	return titles
+}
+
+func TestRuleEngine(t *testing.T) {
+	db, dao := newTestDB(t, "monitor_rules")
+	eth, bsc := basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID
+	assert.NoError(t, db.Create(&models.Chain{ChainId: eth, Height: 100}).Error)
+	assert.NoError(t, db.Create(&models.Chain{ChainId: bsc, Height: 200}).Error)
+	for i, status := range []uint64{basedef.STATE_FINISHED, basedef.STATE_SOURCE_DONE, basedef.STATE_POLY_CONFIRMED} {
+		assert.NoError(t, db.Create(&models.WrapperTransaction{Hash: fmt.Sprintf("%064d", i), SrcChainId: eth, DstChainId: bsc,
+			Time: 600, FeeAmount: models.NewBigIntFromInt(0), Status: status}).Error)
+	}
+
+	sink := &recordSink{}
+	notifier := alert.NewNotifier(sink)
+	notifier.SetThrottle(&conf.AlertThrottleConfig{Dedup: 300})
+	nodes := map[uint64]HeightNode{eth: &heightNode{height: 150}, bsc: &heightNode{height: 210}}
+	engine := NewRuleEngine(&conf.AlertRulesConfig{Stalled: 120, Lag: 20, Pending: 600}, dao, nodes, notifier)
+
+	// heights are first seen, eth lags and the wrapper transactions are new
+	engine.Evaluate(1100)
+	assert.Equal(t, []string{"chain 2 is lagging"}, sink.titles())
+
+	engine.Evaluate(1300)
+	pending := fmt.Sprintf("There is unfinished transactions of chain %d", eth)
+	assert.Equal(t, []string{"chain 2 is not listening", fmt.Sprintf("chain %d is not listening", bsc), pending}, sink.titles())
+	assert.Equal(t, fmt.Sprintf("%d wrapper transactions are pending over 600 seconds, oldest: %064d, %064d", 2, 1, 2),
+		engine.active["pending"][fmt.Sprintf("pending:%d", eth)].Content)
+
+	// the conditions still hold within the dedup window, eth moves on
+	assert.NoError(t, db.Model(&models.Chain{}).Where("chain_id = ?", eth).Update("height", 140).Error)
+	nodes[eth].(*heightNode).height = 175
+	engine.Evaluate(1400)
+	assert.Equal(t, []string{"resolved: chain 2 is not listening"}, sink.titles())
+
+	// the lag comes back after resolved without waiting for the window
+	nodes[eth].(*heightNode).height = 155
+	engine.Evaluate(1450)
+	assert.Equal(t, []string{"resolved: chain 2 is lagging"}, sink.titles())
+	nodes[eth].(*heightNode).height = 170
+	engine.Evaluate(1460)
+	assert.Equal(t, []string{"chain 2 is lagging"}, sink.titles())
+
+	// a failing rule keeps its alerts
+	nodes[bsc].(*heightNode).err = fmt.Errorf("node down")
+	engine.Evaluate(1470)
+	assert.Empty(t, sink.titles())
+	assert.Equal(t, 1, len(engine.active["lag"]))
+}
@@ -0,0 +1,386 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"math/big"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/monitordao"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+)
+
+const (
+	defaultSupplyInterval  = 3600
+	defaultSupplyPageSize  = 100
+	defaultSupplyMaxTokens = 10000
+	defaultSupplyGrace     = 3600
+)
+
+const (
+	SupplyDouble  = "double"  // the token circulates on more than one chain
+	SupplyMissing = "missing" // the token is locked and circulates nowhere
+)
+
+// NFTNode is the chain side read by the supply auditor.
+type NFTNode interface {
+	NFTBalance(asset, owner common.Address) (int, error)
+	GetNFTs(asset, owner common.Address, start, end int) ([]*big.Int, error)
+	GetAssetNFTs(asset common.Address, start, end int) ([]*big.Int, error)
+}
+
+// NewNFTNodes returns the nodes and lock proxies of the evm chains which have
+// a proxy configured.
+func NewNFTNodes(cfgs []*conf.ChainListenConfig) (map[uint64]NFTNode, map[uint64]common.Address) {
+	nodes := make(map[uint64]NFTNode)
+	proxies := make(map[uint64]common.Address)
+	for _, cfg := range cfgs {
+		if cfg.ChainId == basedef.POLY_CROSSCHAIN_ID || cfg.ProxyContract == "" {
+			continue
+		}
+		nodes[cfg.ChainId] = eth_sdk.NewEthereumSdkPro(cfg.GetNodesUrl(), cfg.ListenSlot, cfg.ChainId)
+		proxies[cfg.ChainId] = common.HexToAddress(cfg.ProxyContract)
+	}
+	return nodes, proxies
+}
+
+// SupplyAuditor checks that every token of a collection circulates on one
+// chain at most. A token sent across is held by the lock proxy of the chain it
+// left, so each token held by a proxy should circulate on exactly one of the
+// other chains the collection is mapped to. The audit is partial when a chain
+// of the collection has no node or more than MaxTokens tokens, a token may
+// then circulate on a chain which was not read, so double and missing tokens
+// are not reported. A token locked less than Grace ago whose lock has no
+// destination transaction yet is being relayed, it is not reported missing.
+type SupplyAuditor struct {
+	cfg      *conf.SupplyAuditConfig
+	db       monitordao.MonitorDao
+	nodes    map[uint64]NFTNode
+	proxies  map[uint64]common.Address
+	notifier *alert.Notifier
+	exit     chan bool
+}
+
+func NewSupplyAuditor(
+	cfg *conf.SupplyAuditConfig,
+	db monitordao.MonitorDao,
+	nodes map[uint64]NFTNode,
+	proxies map[uint64]common.Address,
+	notifier *alert.Notifier,
+) *SupplyAuditor {
+	if cfg == nil {
+		cfg = &conf.SupplyAuditConfig{}
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultSupplyInterval
+	}
+	if cfg.PageSize == 0 {
+		cfg.PageSize = defaultSupplyPageSize
+	}
+	if cfg.MaxTokens == 0 {
+		cfg.MaxTokens = defaultSupplyMaxTokens
+	}
+	if cfg.Grace == 0 {
+		cfg.Grace = defaultSupplyGrace
+	}
+	return &SupplyAuditor{
+		cfg:      cfg,
+		db:       db,
+		nodes:    nodes,
+		proxies:  proxies,
+		notifier: notifier,
+		exit:     make(chan bool, 0),
+	}
+}
+
+func (a *SupplyAuditor) Start() {
+	logs.Info("start supply auditor, dao: %s", a.db.Name())
+	go a.run()
+}
+
+func (a *SupplyAuditor) Stop() {
+	a.exit <- true
+	logs.Info("stop supply auditor")
+}
+
+func (a *SupplyAuditor) run() {
+	ticker := time.NewTicker(time.Second * time.Duration(a.cfg.Interval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.safeAudit()
+		case <-a.exit:
+			return
+		}
+	}
+}
+
+func (a *SupplyAuditor) safeAudit() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("supply auditor, recover info: %s", string(debug.Stack()))
+		}
+	}()
+	if _, err := a.Audit(); err != nil {
+		logs.Error("supply audit failed, err: %v", err)
+	}
+}
+
+type auditAsset struct {
+	chainId uint64
+	hash    string
+}
+
+// Audit audits every mapped collection and saves the results. A collection
+// whose chains can not be read is logged and left out.
+func (a *SupplyAuditor) Audit() ([]*models.NFTSupplyAudit, error) {
+	assetMaps, err := a.db.GetAuditAssetMaps()
+	if err != nil {
+		return nil, err
+	}
+	collections := make(map[string]map[auditAsset]bool)
+	for _, assetMap := range assetMaps {
+		if assetMap.SrcAsset == nil || assetMap.DstAsset == nil {
+			continue
+		}
+		name := assetMap.SrcAsset.AssetBasicName
+		if collections[name] == nil {
+			collections[name] = make(map[auditAsset]bool)
+		}
+		collections[name][auditAsset{assetMap.SrcChainId, assetMap.SrcAssetHash}] = true
+		collections[name][auditAsset{assetMap.DstChainId, assetMap.DstAssetHash}] = true
+	}
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	audits := make([]*models.NFTSupplyAudit, 0)
+	for _, name := range names {
+		audit, err := a.auditCollection(name, collections[name])
+		if err != nil {
+			logs.Error("supply audit of %s failed, err: %v", name, err)
+			continue
+		}
+		old, err := a.db.GetSupplyAudit(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.db.SaveSupplyAudit(audit); err != nil {
+			return nil, err
+		}
+		a.notify(audit, old)
+		audits = append(audits, audit)
+	}
+	return audits, nil
+}
+
+type tokenSupply struct {
+	id          *big.Int
+	locked      []uint64
+	circulating []uint64
+}
+
+func (a *SupplyAuditor) auditCollection(name string, assets map[auditAsset]bool) (*models.NFTSupplyAudit, error) {
+	audit := &models.NFTSupplyAudit{
+		AssetBasicName: name,
+		Time:           time.Now().Unix(),
+		Issues:         make([]*models.NFTSupplyIssue, 0),
+	}
+	tokens := make(map[string]*tokenSupply)
+	relaying := make(map[string]bool)
+	since := uint64(0)
+	if now := uint64(audit.Time); now > a.cfg.Grace {
+		since = now - a.cfg.Grace
+	}
+	supply := func(id *big.Int) *tokenSupply {
+		token, ok := tokens[id.String()]
+		if !ok {
+			token = &tokenSupply{id: id}
+			tokens[id.String()] = token
+		}
+		return token
+	}
+	for asset := range assets {
+		node, proxy := a.nodes[asset.chainId], a.proxies[asset.chainId]
+		if node == nil {
+			audit.Partial = 1
+			continue
+		}
+		locked, all, truncated, err := a.readAsset(node, common.HexToAddress(asset.hash), proxy)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d asset %s: %v", asset.chainId, asset.hash, err)
+		}
+		pending, err := a.db.GetPendingLocks(asset.chainId, asset.hash, since)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d asset %s: %v", asset.chainId, asset.hash, err)
+		}
+		for _, transfer := range pending {
+			if transfer.Amount != nil {
+				relaying[transfer.Amount.String()] = true
+			}
+		}
+		audit.Chains++
+		if truncated {
+			audit.Truncated = 1
+			audit.Partial = 1
+		}
+		lockedIds := make(map[string]bool)
+		for _, id := range locked {
+			lockedIds[id.String()] = true
+			token := supply(id)
+			token.locked = append(token.locked, asset.chainId)
+		}
+		for _, id := range all {
+			if lockedIds[id.String()] {
+				continue
+			}
+			token := supply(id)
+			token.circulating = append(token.circulating, asset.chainId)
+		}
+	}
+	for _, token := range tokens {
+		audit.Tokens++
+		if len(token.locked) > 0 {
+			audit.Locked++
+		}
+		if audit.Partial == 1 {
+			continue
+		}
+		if len(token.circulating) > 1 {
+			audit.Double++
+			audit.Issues = append(audit.Issues, makeSupplyIssue(name, token.id, SupplyDouble, token.circulating))
+		} else if len(token.circulating) == 0 && len(token.locked) > 0 {
+			if relaying[token.id.String()] {
+				logs.Info("supply audit of %s, token %s is being relayed", name, token.id.String())
+				continue
+			}
+			audit.Missing++
+			audit.Issues = append(audit.Issues, makeSupplyIssue(name, token.id, SupplyMissing, token.locked))
+		}
+	}
+	sort.Slice(audit.Issues, func(i, j int) bool {
+		x, _ := new(big.Int).SetString(audit.Issues[i].TokenId, 10)
+		y, _ := new(big.Int).SetString(audit.Issues[j].TokenId, 10)
+		if c := x.Cmp(y); c != 0 {
+			return c < 0
+		}
+		return audit.Issues[i].Kind < audit.Issues[j].Kind
+	})
+	if audit.Partial == 1 {
+		logs.Warn("supply audit of %s is partial, %d of %d chains read, truncated: %v",
+			name, audit.Chains, len(assets), audit.Truncated == 1)
+	}
+	return audit, nil
+}
+
+// readAsset reads the tokens held by the proxy and all tokens of the asset, at
+// most MaxTokens of each.
+func (a *SupplyAuditor) readAsset(node NFTNode, asset, proxy common.Address) ([]*big.Int, []*big.Int, bool, error) {
+	truncated := false
+	balance, err := node.NFTBalance(asset, proxy)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if balance > a.cfg.MaxTokens {
+		balance = a.cfg.MaxTokens
+		truncated = true
+	}
+	locked := make([]*big.Int, 0, balance)
+	for start := 0; start < balance; start += a.cfg.PageSize {
+		end := start + a.cfg.PageSize
+		if end > balance {
+			end = balance
+		}
+		ids, err := node.GetNFTs(asset, proxy, start, end)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		locked = append(locked, ids...)
+	}
+
+	all := make([]*big.Int, 0)
+	for start := 0; ; start += a.cfg.PageSize {
+		if start >= a.cfg.MaxTokens {
+			truncated = true
+			break
+		}
+		ids, err := node.GetAssetNFTs(asset, start, start+a.cfg.PageSize)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		all = append(all, ids...)
+		if len(ids) < a.cfg.PageSize {
+			break
+		}
+	}
+	return locked, all, truncated, nil
+}
+
+func makeSupplyIssue(name string, id *big.Int, kind string, chains []uint64) *models.NFTSupplyIssue {
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+	list := make([]string, 0, len(chains))
+	for _, chain := range chains {
+		list = append(list, strconv.FormatUint(chain, 10))
+	}
+	return &models.NFTSupplyIssue{
+		AssetBasicName: name,
+		TokenId:        id.String(),
+		Kind:           kind,
+		Chains:         strings.Join(list, ","),
+	}
+}
+
+// notify alerts the issues which the last audit did not have.
+func (a *SupplyAuditor) notify(audit, old *models.NFTSupplyAudit) {
+	known := make(map[string]bool)
+	if old != nil {
+		for _, issue := range old.Issues {
+			known[issue.Kind+issue.TokenId] = true
+		}
+	}
+	fresh := make([]string, 0)
+	for _, issue := range audit.Issues {
+		if !known[issue.Kind+issue.TokenId] {
+			fresh = append(fresh, fmt.Sprintf("token %s %s on chains %s", issue.TokenId, issue.Kind, issue.Chains))
+		}
+	}
+	if len(fresh) == 0 {
+		return
+	}
+	a.notifier.Notify(&alert.Alert{
+		Level:  alert.LevelCritical,
+		Source: "supply",
+		Title:  fmt.Sprintf("nft supply of %s broken", audit.AssetBasicName),
+		Content: fmt.Sprintf("%d double, %d missing: %s", audit.Double, audit.Missing,
+			strings.Join(fresh, "; ")),
+	})
+}
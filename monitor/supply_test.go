@@ -0,0 +1,248 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// testNode keeps the owner of every token of one asset.
+type testNode struct {
+	owners map[int64]common.Address
+}
+
+func (n *testNode) ids(owner *common.Address) []*big.Int {
+	ids := make([]*big.Int, 0)
+	for id, o := range n.owners {
+		if owner == nil || o == *owner {
+			ids = append(ids, big.NewInt(id))
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Cmp(ids[j]) < 0 })
+	return ids
+}
+
+func page(ids []*big.Int, start, end int) []*big.Int {
+	if start > len(ids) {
+		start = len(ids)
+	}
+	if end > len(ids) {
+		end = len(ids)
+	}
+	return ids[start:end]
+}
+
+func (n *testNode) NFTBalance(asset, owner common.Address) (int, error) {
+	return len(n.ids(&owner)), nil
+}
+
+func (n *testNode) GetNFTs(asset, owner common.Address, start, end int) ([]*big.Int, error) {
+	return page(n.ids(&owner), start, end), nil
+}
+
+func (n *testNode) GetAssetNFTs(asset common.Address, start, end int) ([]*big.Int, error) {
+	return page(n.ids(nil), start, end), nil
+}
+
+func TestSupplyAuditor(t *testing.T) {
+	db, dao := newTestDB(t, "monitor_supply")
+	assert.NoError(t, db.Create(&models.NFTAsset{Hash: ethAsset, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, AssetBasicName: "dog"}).Error)
+	assert.NoError(t, db.Create(&models.NFTAsset{Hash: bscAsset, ChainId: basedef.BSC_CROSSCHAIN_ID, AssetBasicName: "dog"}).Error)
+
+	ethProxy := common.HexToAddress("e0")
+	bscProxy := common.HexToAddress("b0")
+	holder := common.HexToAddress(user)
+	eth := &testNode{owners: map[int64]common.Address{
+		1: ethProxy, // bridged and minted on bsc
+		2: ethProxy, // bridged, never minted on bsc
+		3: holder,   // circulates on eth and on bsc
+		4: holder,
+	}}
+	bsc := &testNode{owners: map[int64]common.Address{
+		1: holder,
+		3: holder,
+		5: bscProxy, // came back, bsc proxy holds it and eth has no 5
+	}}
+	sink := &recordSink{}
+	auditor := NewSupplyAuditor(&conf.SupplyAuditConfig{PageSize: 2},
+		dao,
+		map[uint64]NFTNode{basedef.ETHEREUM_CROSSCHAIN_ID: eth, basedef.BSC_CROSSCHAIN_ID: bsc},
+		map[uint64]common.Address{basedef.ETHEREUM_CROSSCHAIN_ID: ethProxy, basedef.BSC_CROSSCHAIN_ID: bscProxy},
+		alert.NewNotifier(sink))
+
+	audits, err := auditor.Audit()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(audits))
+	audit := audits[0]
+	assert.Equal(t, "dog", audit.AssetBasicName)
+	assert.Equal(t, int64(2), audit.Chains)
+	assert.Equal(t, int64(5), audit.Tokens)
+	assert.Equal(t, int64(3), audit.Locked)
+	assert.Equal(t, int64(1), audit.Double)
+	assert.Equal(t, int64(2), audit.Missing)
+	assert.Equal(t, int64(0), audit.Truncated)
+	assert.Equal(t, int64(0), audit.Partial)
+	both := fmt.Sprintf("%d,%d", basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID)
+	assert.Equal(t, []*models.NFTSupplyIssue{
+		{AssetBasicName: "dog", TokenId: "2", Kind: SupplyMissing, Chains: fmt.Sprint(basedef.ETHEREUM_CROSSCHAIN_ID)},
+		{AssetBasicName: "dog", TokenId: "3", Kind: SupplyDouble, Chains: both},
+		{AssetBasicName: "dog", TokenId: "5", Kind: SupplyMissing, Chains: fmt.Sprint(basedef.BSC_CROSSCHAIN_ID)},
+	}, audit.Issues)
+	assert.Equal(t, 1, len(sink.alerts))
+
+	saved, err := dao.GetSupplyAudit("dog")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(saved.Issues))
+
+	// known issues are not alerted again, fixed ones leave the saved audit
+	sink.alerts = nil
+	bsc.owners[2] = holder
+	audits, err = auditor.Audit()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), audits[0].Missing)
+	assert.Empty(t, sink.alerts)
+	saved, err = dao.GetSupplyAudit("dog")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(saved.Issues))
+
+	// a new double mint is alerted
+	bsc.owners[4] = holder
+	_, err = auditor.Audit()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sink.alerts))
+	assert.Contains(t, sink.alerts[0].Content, "token 4 double on chains "+both)
+}
+
+func TestSupplyAuditorPartial(t *testing.T) {
+	db, dao := newTestDB(t, "monitor_supply_partial")
+	assert.NoError(t, db.Create(&models.NFTAsset{Hash: ethAsset, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, AssetBasicName: "dog"}).Error)
+	assert.NoError(t, db.Create(&models.NFTAsset{Hash: bscAsset, ChainId: basedef.BSC_CROSSCHAIN_ID, AssetBasicName: "dog"}).Error)
+
+	ethProxy := common.HexToAddress("e0")
+	holder := common.HexToAddress(user)
+	// 1 circulates on bsc, which has no node
+	eth := &testNode{owners: map[int64]common.Address{1: ethProxy, 2: holder, 3: holder}}
+	sink := &recordSink{}
+	auditor := NewSupplyAuditor(&conf.SupplyAuditConfig{PageSize: 2},
+		dao,
+		map[uint64]NFTNode{basedef.ETHEREUM_CROSSCHAIN_ID: eth},
+		map[uint64]common.Address{basedef.ETHEREUM_CROSSCHAIN_ID: ethProxy},
+		alert.NewNotifier(sink))
+	audits, err := auditor.Audit()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(audits))
+	assert.Equal(t, int64(1), audits[0].Partial)
+	assert.Equal(t, int64(1), audits[0].Chains)
+	assert.Equal(t, int64(1), audits[0].Locked)
+	assert.Equal(t, int64(0), audits[0].Missing)
+	assert.Empty(t, audits[0].Issues)
+	assert.Empty(t, sink.alerts)
+
+	// the bsc tokens past MaxTokens are not read
+	bsc := &testNode{owners: map[int64]common.Address{4: holder, 5: holder, 6: holder}}
+	auditor = NewSupplyAuditor(&conf.SupplyAuditConfig{PageSize: 2, MaxTokens: 2},
+		dao,
+		map[uint64]NFTNode{basedef.ETHEREUM_CROSSCHAIN_ID: eth, basedef.BSC_CROSSCHAIN_ID: bsc},
+		map[uint64]common.Address{basedef.ETHEREUM_CROSSCHAIN_ID: ethProxy, basedef.BSC_CROSSCHAIN_ID: common.HexToAddress("b0")},
+		alert.NewNotifier(sink))
+	audits, err = auditor.Audit()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), audits[0].Truncated)
+	assert.Equal(t, int64(1), audits[0].Partial)
+	assert.Equal(t, int64(0), audits[0].Missing)
+	assert.Empty(t, sink.alerts)
+	saved, err := dao.GetSupplyAudit("dog")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), saved.Partial)
+}
+
+func TestSupplyAuditorRelaying(t *testing.T) {
+	db, dao := newTestDB(t, "monitor_supply_relaying")
+	assert.NoError(t, db.Create(&models.NFTAsset{Hash: ethAsset, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, AssetBasicName: "dog"}).Error)
+	assert.NoError(t, db.Create(&models.NFTAsset{Hash: bscAsset, ChainId: basedef.BSC_CROSSCHAIN_ID, AssetBasicName: "dog"}).Error)
+
+	// 1 was locked just now and is being relayed, 2 was locked long ago
+	now := uint64(time.Now().Unix())
+	lock := func(i byte, tt uint64, id int64) {
+		assert.NoError(t, db.Create(&models.SrcTransaction{
+			Hash:    string([]byte{'d', 'd', 'd', i}) + "000000000000000000000000000000000000000000000000000000000000",
+			ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Time: tt, Fee: models.NewBigIntFromInt(0),
+			DstChainId: basedef.BSC_CROSSCHAIN_ID, Key: string([]byte{'e', 'e', 'e', i}),
+			SrcTransfer: &models.SrcTransfer{
+				ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Time: tt, Asset: ethAsset, Amount: models.NewBigIntFromInt(id),
+				DstChainId: basedef.BSC_CROSSCHAIN_ID, DstAsset: bscAsset, DstUser: user,
+			},
+		}).Error)
+	}
+	lock('1', now, 1)
+	lock('2', now-7200, 2)
+	ethProxy := common.HexToAddress("e0")
+	eth := &testNode{owners: map[int64]common.Address{1: ethProxy, 2: ethProxy}}
+	bsc := &testNode{owners: map[int64]common.Address{}}
+	sink := &recordSink{}
+	auditor := NewSupplyAuditor(&conf.SupplyAuditConfig{PageSize: 2},
+		dao,
+		map[uint64]NFTNode{basedef.ETHEREUM_CROSSCHAIN_ID: eth, basedef.BSC_CROSSCHAIN_ID: bsc},
+		map[uint64]common.Address{basedef.ETHEREUM_CROSSCHAIN_ID: ethProxy, basedef.BSC_CROSSCHAIN_ID: common.HexToAddress("b0")},
+		alert.NewNotifier(sink))
+	audits, err := auditor.Audit()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), audits[0].Locked)
+	assert.Equal(t, int64(1), audits[0].Missing)
+	assert.Equal(t, []*models.NFTSupplyIssue{
+		{AssetBasicName: "dog", TokenId: "2", Kind: SupplyMissing, Chains: fmt.Sprint(basedef.ETHEREUM_CROSSCHAIN_ID)},
+	}, audits[0].Issues)
+
+	// the unlock of 1 was sent, yet bsc has no 1
+	assert.NoError(t, db.Create(&models.PolyTransaction{
+		Hash: "fff1", ChainId: basedef.POLY_CROSSCHAIN_ID, Time: now, Fee: models.NewBigIntFromInt(0),
+		SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcHash: "eee1", DstChainId: basedef.BSC_CROSSCHAIN_ID,
+	}).Error)
+	assert.NoError(t, db.Create(&models.DstTransaction{
+		Hash: "ccc1", ChainId: basedef.BSC_CROSSCHAIN_ID, Time: now, Fee: models.NewBigIntFromInt(0),
+		SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, PolyHash: "fff1",
+	}).Error)
+	audits, err = auditor.Audit()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), audits[0].Missing)
+}
+
+func TestSupplyAuditorTruncated(t *testing.T) {
+	_, dao := newTestDB(t, "monitor_supply_truncated")
+	owners := make(map[int64]common.Address)
+	for i := int64(0); i < 5; i++ {
+		owners[i] = common.HexToAddress(user)
+	}
+	auditor := NewSupplyAuditor(&conf.SupplyAuditConfig{PageSize: 2, MaxTokens: 4}, dao, nil, nil, alert.NewNotifier())
+	locked, all, truncated, err := auditor.readAsset(&testNode{owners: owners}, common.Address{}, common.HexToAddress(user))
+	assert.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Equal(t, 4, len(locked))
+	assert.Equal(t, 4, len(all))
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/monitordao"
+	"github.com/polynetwork/poly-nft-bridge/models"
+)
+
+const (
+	defaultUnlockInterval = 60
+	defaultUnlockGrace    = 600
+	unlockBatchSize       = 500
+)
+
+// UnlockMonitor checks that every unlock on a destination chain comes from a
+// poly transaction and a source lock of the same token to the same user. An
+// unlock is checked once it is older than the grace period, so that the poly
+// and source listeners have caught up, and is marked checked in
+// dst_transactions. Unlocks saved late by a lagging listener are still
+// unchecked and a restart loses nothing. Flagged unlocks are kept in
+// unmatched_unlocks and checked again until they match.
+type UnlockMonitor struct {
+	cfg      *conf.UnlockMonitorConfig
+	db       monitordao.MonitorDao
+	notifier *alert.Notifier
+	exit     chan bool
+}
+
+func NewUnlockMonitor(cfg *conf.UnlockMonitorConfig, db monitordao.MonitorDao, notifier *alert.Notifier) *UnlockMonitor {
+	if cfg == nil {
+		cfg = &conf.UnlockMonitorConfig{}
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultUnlockInterval
+	}
+	if cfg.Grace == 0 {
+		cfg.Grace = defaultUnlockGrace
+	}
+	return &UnlockMonitor{
+		cfg:      cfg,
+		db:       db,
+		notifier: notifier,
+		exit:     make(chan bool, 0),
+	}
+}
+
+func (m *UnlockMonitor) Start() {
+	logs.Info("start unlock monitor, dao: %s", m.db.Name())
+	go m.run()
+}
+
+func (m *UnlockMonitor) Stop() {
+	m.exit <- true
+	logs.Info("stop unlock monitor")
+}
+
+func (m *UnlockMonitor) run() {
+	ticker := time.NewTicker(time.Second * time.Duration(m.cfg.Interval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.safeCheck()
+		case <-m.exit:
+			return
+		}
+	}
+}
+
+func (m *UnlockMonitor) safeCheck() {
+	defer func() {
+		if r := recover(); r != nil {
+			logs.Error("unlock monitor, recover info: %s", string(debug.Stack()))
+		}
+	}()
+	if err := m.Check(uint64(time.Now().Unix())); err != nil {
+		logs.Error("unlock monitor check failed, err: %v", err)
+	}
+}
+
+// Check runs one round at the given time. Unlocks of a failed batch are
+// checked again, which is fine as flagging the same unlock twice does nothing.
+func (m *UnlockMonitor) Check(now uint64) error {
+	if err := m.recheck(); err != nil {
+		return err
+	}
+	if now <= m.cfg.Grace {
+		return nil
+	}
+	for {
+		txs, err := m.db.GetUncheckedDstTransactions(now-m.cfg.Grace, unlockBatchSize)
+		if err != nil {
+			return err
+		}
+		hashes := make([]string, 0, len(txs))
+		for _, tx := range txs {
+			unlock, err := m.checkUnlock(tx)
+			if err != nil {
+				return err
+			}
+			if unlock != nil {
+				if err := m.flag(unlock); err != nil {
+					return err
+				}
+			}
+			hashes = append(hashes, tx.Hash)
+		}
+		if err := m.db.SetDstTransactionsChecked(hashes); err != nil {
+			return err
+		}
+		if len(txs) < unlockBatchSize {
+			return nil
+		}
+	}
+}
+
+func (m *UnlockMonitor) recheck() error {
+	unlocks, err := m.db.GetUnmatchedUnlocks()
+	if err != nil {
+		return err
+	}
+	for _, old := range unlocks {
+		tx, err := m.db.GetDstTransaction(old.Hash)
+		if err != nil {
+			return err
+		}
+		if tx == nil {
+			continue
+		}
+		unlock, err := m.checkUnlock(tx)
+		if err != nil {
+			return err
+		}
+		if unlock == nil {
+			if err := m.db.ResolveUnmatchedUnlock(old.Hash); err != nil {
+				return err
+			}
+			m.notifier.Notify(&alert.Alert{
+				Level:   alert.LevelInfo,
+				Source:  "unlock",
+				Title:   fmt.Sprintf("unlock %s matched", old.Hash),
+				Content: fmt.Sprintf("unlock %s on chain %d matches its source now", old.Hash, old.ChainId),
+			})
+			continue
+		}
+		if unlock.Reason != old.Reason || unlock.SrcHash != old.SrcHash {
+			unlock.CreateTime = old.CreateTime
+			if err := m.db.SaveUnmatchedUnlock(unlock); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *UnlockMonitor) flag(unlock *models.UnmatchedUnlock) error {
+	old, err := m.db.GetUnmatchedUnlock(unlock.Hash)
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		return nil
+	}
+	unlock.CreateTime = uint64(time.Now().Unix())
+	if err := m.db.SaveUnmatchedUnlock(unlock); err != nil {
+		return err
+	}
+	m.notifier.Notify(&alert.Alert{
+		Level:  alert.LevelCritical,
+		Source: "unlock",
+		Title:  fmt.Sprintf("unmatched unlock %s", unlock.Hash),
+		Content: fmt.Sprintf("unlock %s on chain %d from chain %d, poly tx %s: %s",
+			unlock.Hash, unlock.ChainId, unlock.SrcChainId, unlock.PolyHash, unlock.Reason),
+	})
+	return nil
+}
+
+// checkUnlock returns nil when the unlock matches its poly transaction and
+// source lock, or the record to flag it with.
+func (m *UnlockMonitor) checkUnlock(tx *models.DstTransaction) (*models.UnmatchedUnlock, error) {
+	unlock := &models.UnmatchedUnlock{
+		Hash:       tx.Hash,
+		ChainId:    tx.ChainId,
+		SrcChainId: tx.SrcChainId,
+		PolyHash:   tx.PolyHash,
+		Time:       tx.Time,
+		Resolved:   basedef.UNLOCK_UNRESOLVED,
+	}
+	polyTx, err := m.db.GetPolyTransaction(tx.PolyHash)
+	if err != nil {
+		return nil, err
+	}
+	if polyTx == nil {
+		unlock.Reason = "no poly transaction"
+		return unlock, nil
+	}
+	unlock.SrcHash = polyTx.SrcHash
+	if polyTx.SrcChainId != tx.SrcChainId || polyTx.DstChainId != tx.ChainId {
+		unlock.Reason = fmt.Sprintf("poly transaction is from chain %d to chain %d", polyTx.SrcChainId, polyTx.DstChainId)
+		return unlock, nil
+	}
+	srcTx, err := m.db.GetSrcTransaction(polyTx.SrcChainId, polyTx.SrcHash)
+	if err != nil {
+		return nil, err
+	}
+	if srcTx == nil {
+		unlock.Reason = "no source transaction"
+		return unlock, nil
+	}
+	unlock.SrcHash = srcTx.Hash
+	reason, err := m.checkTransfer(srcTx.SrcTransfer, tx.DstTransfer, tx.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		return nil, nil
+	}
+	unlock.Reason = reason
+	return unlock, nil
+}
+
+func (m *UnlockMonitor) checkTransfer(src *models.SrcTransfer, dst *models.DstTransfer, chainId uint64) (string, error) {
+	if src == nil && dst == nil {
+		return "no source lock and no unlock transfer", nil
+	}
+	if src == nil {
+		return "no source lock", nil
+	}
+	if dst == nil {
+		return "no unlock transfer", nil
+	}
+	if src.DstChainId != chainId {
+		return fmt.Sprintf("source lock is to chain %d", src.DstChainId), nil
+	}
+	if !sameTokenId(src.Amount, dst.Amount) {
+		return fmt.Sprintf("token id %s, source locks %s", bigIntString(dst.Amount), bigIntString(src.Amount)), nil
+	}
+	if !strings.EqualFold(src.DstUser, dst.To) {
+		return fmt.Sprintf("recipient %s, source locks to %s", dst.To, src.DstUser), nil
+	}
+	assetMaps, err := m.db.GetAssetMaps(src.ChainId, src.Asset, chainId)
+	if err != nil {
+		return "", err
+	}
+	expected := make([]string, 0)
+	for _, assetMap := range assetMaps {
+		expected = append(expected, assetMap.DstAssetHash)
+	}
+	// fall back to the target the lock proxy put in the source lock
+	if len(expected) == 0 {
+		expected = append(expected, src.DstAsset)
+	}
+	for _, hash := range expected {
+		if strings.EqualFold(hash, dst.Asset) {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("asset %s, source asset %s maps to %s", dst.Asset, src.Asset, strings.Join(expected, ",")), nil
+}
+
+func sameTokenId(a, b *models.BigInt) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(&b.Int) == 0
+}
+
+func bigIntString(a *models.BigInt) string {
+	if a == nil {
+		return "nil"
+	}
+	return a.String()
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package monitor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/polynetwork/poly-nft-bridge/alert"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/dao/migration"
+	"github.com/polynetwork/poly-nft-bridge/dao/monitordao/swapdao"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	ethAsset = "1111111111111111111111111111111111111111"
+	bscAsset = "2222222222222222222222222222222222222222"
+	user     = "4444444444444444444444444444444444444444"
+)
+
+type recordSink struct {
+	alerts []*alert.Alert
+}
+
+func (s *recordSink) Send(a *alert.Alert) error {
+	s.alerts = append(s.alerts, a)
+	return nil
+}
+
+func (s *recordSink) Name() string {
+	return "record"
+}
+
+func newTestDB(t *testing.T, name string) (*gorm.DB, *swapdao.SwapDao) {
+	dbCfg := &conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:" + name + "?mode=memory&cache=shared"}
+	db := dbopen.MustOpen(dbCfg)
+	db.Logger = logger.Discard
+	_, err := migration.Up(db, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Save(&models.NFTAssetMap{
+		SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcAssetHash: ethAsset,
+		DstChainId: basedef.BSC_CROSSCHAIN_ID, DstAssetHash: bscAsset,
+	}).Error)
+	return db, swapdao.NewSwapDao(dbCfg)
+}
+
+// saveTransfer saves a lock of token 1 on eth, its poly transaction and the
+// unlock on bsc, the i-th of each.
+func saveTransfer(t *testing.T, db *gorm.DB, i byte, tt uint64, unlockTo string, unlockId int64) (string, string, string) {
+	srcHash := string([]byte{'a', 'a', 'a', i}) + "000000000000000000000000000000000000000000000000000000000000"
+	polyHash := string([]byte{'b', 'b', 'b', i}) + "000000000000000000000000000000000000000000000000000000000000"
+	dstHash := string([]byte{'c', 'c', 'c', i}) + "000000000000000000000000000000000000000000000000000000000000"
+	assert.NoError(t, db.Create(&models.SrcTransaction{
+		Hash: srcHash, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Time: tt, Fee: models.NewBigIntFromInt(0),
+		DstChainId: basedef.BSC_CROSSCHAIN_ID, Key: "00" + srcHash[2:],
+		SrcTransfer: &models.SrcTransfer{
+			ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Time: tt, Asset: ethAsset, Amount: models.NewBigIntFromInt(1),
+			DstChainId: basedef.BSC_CROSSCHAIN_ID, DstAsset: bscAsset, DstUser: user,
+		},
+	}).Error)
+	assert.NoError(t, db.Create(&models.PolyTransaction{
+		Hash: polyHash, ChainId: basedef.POLY_CROSSCHAIN_ID, Time: tt, Fee: models.NewBigIntFromInt(0),
+		SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcHash: "00" + srcHash[2:], DstChainId: basedef.BSC_CROSSCHAIN_ID,
+	}).Error)
+	assert.NoError(t, db.Create(&models.DstTransaction{
+		Hash: dstHash, ChainId: basedef.BSC_CROSSCHAIN_ID, Time: tt, Fee: models.NewBigIntFromInt(0),
+		SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, PolyHash: polyHash,
+		DstTransfer: &models.DstTransfer{
+			ChainId: basedef.BSC_CROSSCHAIN_ID, Time: tt, Asset: bscAsset, To: unlockTo, Amount: models.NewBigIntFromInt(unlockId),
+		},
+	}).Error)
+	return srcHash, polyHash, dstHash
+}
+
+func TestUnlockMonitor(t *testing.T) {
+	db, dao := newTestDB(t, "monitor_unlock")
+	sink := &recordSink{}
+	m := NewUnlockMonitor(&conf.UnlockMonitorConfig{Grace: 100}, dao, alert.NewNotifier(sink))
+
+	saveTransfer(t, db, '1', 1100, user, 1)
+	_, _, wrongUser := saveTransfer(t, db, '2', 1100, "5555555555555555555555555555555555555555", 1)
+	_, _, wrongId := saveTransfer(t, db, '3', 1100, user, 2)
+	forgedSrc, polyHash, forged := saveTransfer(t, db, '4', 1100, user, 1)
+	assert.NoError(t, db.Delete(&models.PolyTransaction{Hash: polyHash}).Error)
+	_, _, wrongAsset := saveTransfer(t, db, '5', 1100, user, 1)
+	assert.NoError(t, db.Model(&models.DstTransfer{}).Where("tx_hash = ?", wrongAsset).
+		Update("asset", "6666666666666666666666666666666666666666").Error)
+	// still in the grace period
+	_, _, late := saveTransfer(t, db, '6', 1150, "5555555555555555555555555555555555555555", 1)
+
+	assert.NoError(t, m.Check(1200))
+	unlocks, err := dao.GetUnmatchedUnlocks()
+	assert.NoError(t, err)
+	reasons := make(map[string]string)
+	for _, unlock := range unlocks {
+		reasons[unlock.Hash] = unlock.Reason
+	}
+	assert.Equal(t, 4, len(reasons))
+	assert.Contains(t, reasons[wrongUser], "recipient")
+	assert.Contains(t, reasons[wrongId], "token id")
+	assert.Equal(t, "no poly transaction", reasons[forged])
+	assert.Contains(t, reasons[wrongAsset], "asset")
+	assert.Equal(t, 4, len(sink.alerts))
+	for _, a := range sink.alerts {
+		assert.Equal(t, alert.LevelCritical, a.Level)
+	}
+
+	// flagged unlocks alert once, the late one is checked in the next round
+	sink.alerts = nil
+	assert.NoError(t, m.Check(1300))
+	assert.Equal(t, 1, len(sink.alerts))
+	assert.Contains(t, sink.alerts[0].Title, late)
+
+	// the poly listener catches up
+	assert.NoError(t, db.Create(&models.PolyTransaction{
+		Hash: polyHash, ChainId: basedef.POLY_CROSSCHAIN_ID, Fee: models.NewBigIntFromInt(0),
+		SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, DstChainId: basedef.BSC_CROSSCHAIN_ID,
+		SrcHash: forgedSrc,
+	}).Error)
+	sink.alerts = nil
+	assert.NoError(t, m.Check(1400))
+	assert.Equal(t, 1, len(sink.alerts))
+	assert.Equal(t, alert.LevelInfo, sink.alerts[0].Level)
+	unlock, err := dao.GetUnmatchedUnlock(forged)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(basedef.UNLOCK_RESOLVED), unlock.Resolved)
+
+	// after a restart only the unlock saved late by a lagging listener is new
+	_, _, lagging := saveTransfer(t, db, '7', 900, "5555555555555555555555555555555555555555", 1)
+	sink.alerts = nil
+	m = NewUnlockMonitor(&conf.UnlockMonitorConfig{Grace: 100}, dao, alert.NewNotifier(sink))
+	assert.NoError(t, m.Check(1500))
+	assert.Equal(t, 1, len(sink.alerts))
+	assert.Contains(t, sink.alerts[0].Title, lagging)
+	unchecked, err := dao.GetUncheckedDstTransactions(1400, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(unchecked))
+}
+
+func TestCheckTransferWithoutAssetMap(t *testing.T) {
+	_, dao := newTestDB(t, "monitor_transfer")
+	m := NewUnlockMonitor(nil, dao, alert.NewNotifier())
+	src := &models.SrcTransfer{
+		ChainId: basedef.HECO_CROSSCHAIN_ID, Asset: ethAsset, Amount: &models.BigInt{Int: *big.NewInt(7)},
+		DstChainId: basedef.BSC_CROSSCHAIN_ID, DstAsset: bscAsset, DstUser: user,
+	}
+	dst := &models.DstTransfer{Asset: bscAsset, To: user, Amount: &models.BigInt{Int: *big.NewInt(7)}}
+	reason, err := m.checkTransfer(src, dst, basedef.BSC_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+
+	dst.Asset = ethAsset
+	reason, err = m.checkTransfer(src, dst, basedef.BSC_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	assert.Contains(t, reason, "asset")
+
+	reason, err = m.checkTransfer(src, nil, basedef.BSC_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "no unlock transfer", reason)
+
+	reason, err = m.checkTransfer(nil, nil, basedef.BSC_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", reason)
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao/swapdao"
+	"github.com/polynetwork/poly-nft-bridge/dao/crosschaindao"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAssetBinds saves the bindings the way the listeners do and approves them
+// the way asset_tool does.
+func TestAssetBinds(t *testing.T) {
+	dbCfg := &conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:rpc_binds?mode=memory&cache=shared"}
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_SWAP, false, dbCfg)
+	saved := store.BridgeDao
+	store.BridgeDao = swapdao.NewSwapDao(dbCfg)
+	defer func() { store.BridgeDao = saved }()
+
+	asset := func(chainId uint64, hash string) *models.NFTAsset {
+		rsp := new(models.NFTAsset)
+		if post(t, "/nft/v1/asset/", &models.NFTAssetReq{ChainId: chainId, Hash: hash}, rsp) != http.StatusOK {
+			return nil
+		}
+		return rsp
+	}
+	maps := func(chainId uint64, hash string) map[string]int64 {
+		rsp := new(models.NFTAssetMapsRsp)
+		post(t, "/nft/v1/assetmap/", &models.NFTAssetMapReq{ChainId: chainId, Hash: hash}, rsp)
+		result := make(map[string]int64)
+		for _, m := range rsp.AssetMaps {
+			result[m.DstTokenHash] = m.Disable
+		}
+		return result
+	}
+	basics := func() int {
+		rsp := new(models.NFTAssetBasicsRsp)
+		assert.Equal(t, http.StatusOK, post(t, "/nft/v1/assetbasics/", &models.NFTAssetBasicsReq{}, rsp))
+		return len(rsp.AssetBasics)
+	}
+
+	// the eth listener sees the binding first
+	assert.NoError(t, dao.UpdateAssetBinds([]*models.AssetBindEvent{{
+		ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, AssetHash: ethAsset, Name: "Bird", Symbol: "BIRD", BaseUri: "https://bird.io/",
+		ToChainId: basedef.BSC_CROSSCHAIN_ID, ToAssetHash: bscAsset,
+	}}, []*models.ProxyBindEvent{{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, ToChainId: basedef.BSC_CROSSCHAIN_ID, ProxyHash: "AB"}}))
+	ethBasic := "BIRD-2-" + ethAsset
+	eth := asset(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset)
+	assert.Equal(t, ethBasic, eth.AssetBasicName)
+	assert.Equal(t, "https://bird.io/", eth.BaseUri)
+	assert.Equal(t, int64(basedef.ASSET_PENDING), eth.Disable)
+	assert.Equal(t, map[string]int64{bscAsset: basedef.ASSET_PENDING}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+	assert.Equal(t, 0, basics())
+
+	// approving before the bsc side is found leaves the map pending
+	assert.NoError(t, dao.ApproveAssets([]string{ethBasic}))
+	assert.Equal(t, int64(basedef.ASSET_ENABLE), asset(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset).Disable)
+	assert.Equal(t, map[string]int64{bscAsset: basedef.ASSET_PENDING}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+	assert.Equal(t, 1, basics())
+
+	// the bsc side gets a basic of its own, even with the same symbol
+	assert.NoError(t, dao.UpdateAssetBinds([]*models.AssetBindEvent{{
+		ChainId: basedef.BSC_CROSSCHAIN_ID, AssetHash: bscAsset, Name: "Bird", Symbol: "BIRD",
+		ToChainId: basedef.ETHEREUM_CROSSCHAIN_ID, ToAssetHash: ethAsset,
+	}}, nil))
+	bscBasic := "BIRD-79-" + bscAsset
+	assert.Equal(t, bscBasic, asset(basedef.BSC_CROSSCHAIN_ID, bscAsset).AssetBasicName)
+	assets, pendingMaps, err := dao.GetPendingAssets()
+	assert.NoError(t, err)
+	assert.Len(t, assets, 1)
+	assert.Len(t, pendingMaps, 2)
+	assert.Equal(t, 1, basics())
+
+	assert.NoError(t, dao.ApproveAssets([]string{bscBasic}))
+	assert.Equal(t, int64(basedef.ASSET_ENABLE), asset(basedef.BSC_CROSSCHAIN_ID, bscAsset).Disable)
+	assert.Equal(t, map[string]int64{bscAsset: basedef.ASSET_ENABLE}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+	assert.Equal(t, map[string]int64{ethAsset: basedef.ASSET_ENABLE}, maps(basedef.BSC_CROSSCHAIN_ID, bscAsset))
+
+	// seeing the binding again changes nothing
+	assert.NoError(t, dao.UpdateAssetBinds([]*models.AssetBindEvent{{
+		ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, AssetHash: ethAsset, Name: "Bird", Symbol: "BIRD",
+		ToChainId: basedef.BSC_CROSSCHAIN_ID, ToAssetHash: bscAsset,
+	}}, nil))
+	assert.Equal(t, map[string]int64{bscAsset: basedef.ASSET_ENABLE}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+	assert.Equal(t, "https://bird.io/", asset(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset).BaseUri)
+
+	// binding another target replaces the map
+	assert.NoError(t, dao.UpdateAssetBinds([]*models.AssetBindEvent{{
+		ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, AssetHash: ethAsset, ToChainId: basedef.BSC_CROSSCHAIN_ID, ToAssetHash: "6666",
+	}}, nil))
+	assert.Equal(t, map[string]int64{bscAsset: basedef.ASSET_DISABLE, "6666": basedef.ASSET_PENDING},
+		maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+
+	// unbinding
+	assert.NoError(t, dao.UpdateAssetBinds([]*models.AssetBindEvent{{
+		ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, AssetHash: ethAsset, ToChainId: basedef.BSC_CROSSCHAIN_ID,
+	}}, nil))
+	assert.Equal(t, map[string]int64{bscAsset: basedef.ASSET_DISABLE, "6666": basedef.ASSET_DISABLE},
+		maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+}
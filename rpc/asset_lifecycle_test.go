@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao/swapdao"
+	"github.com/polynetwork/poly-nft-bridge/dao/crosschaindao"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const hecoAsset = "5555555555555555555555555555555555555555"
+
+// TestAssetLifecycle writes the assets the way asset_tool does and reads them
+// back through the api.
+func TestAssetLifecycle(t *testing.T) {
+	dbCfg := &conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:rpc_lifecycle?mode=memory&cache=shared"}
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_SWAP, false, dbCfg)
+	saved := store.BridgeDao
+	store.BridgeDao = swapdao.NewSwapDao(dbCfg)
+	defer func() { store.BridgeDao = saved }()
+
+	cat := func(disable int64, baseUri string, chains ...uint64) []*models.NFTAssetBasic {
+		hashes := map[uint64]string{
+			basedef.ETHEREUM_CROSSCHAIN_ID: ethAsset,
+			basedef.BSC_CROSSCHAIN_ID:      bscAsset,
+			basedef.HECO_CROSSCHAIN_ID:     hecoAsset,
+		}
+		basic := &models.NFTAssetBasic{Name: "cat", Time: 1, Disable: disable}
+		for _, chainId := range chains {
+			basic.Assets = append(basic.Assets, &models.NFTAsset{Hash: hashes[chainId], ChainId: chainId, Name: "cat", BaseUri: baseUri})
+		}
+		return []*models.NFTAssetBasic{basic}
+	}
+	asset := func(chainId uint64, hash string) *models.NFTAssetRsp {
+		rsp := new(models.NFTAssetsRsp)
+		assert.Equal(t, http.StatusOK, post(t, "/nft/v1/assets/", &models.NFTAssetsReq{ChainId: chainId}, rsp))
+		for _, a := range rsp.Assets {
+			if a.Hash == hash {
+				return a
+			}
+		}
+		return nil
+	}
+	maps := func(chainId uint64, hash string) map[string]int64 {
+		rsp := new(models.NFTAssetMapsRsp)
+		post(t, "/nft/v1/assetmap/", &models.NFTAssetMapReq{ChainId: chainId, Hash: hash}, rsp)
+		result := make(map[string]int64)
+		for _, m := range rsp.AssetMaps {
+			result[m.DstTokenHash] = m.Disable
+		}
+		return result
+	}
+	basics := func() int {
+		rsp := new(models.NFTAssetBasicsRsp)
+		assert.Equal(t, http.StatusOK, post(t, "/nft/v1/assetbasics/", &models.NFTAssetBasicsReq{}, rsp))
+		return len(rsp.AssetBasics)
+	}
+
+	// create
+	assert.NoError(t, dao.AddAssets(cat(0, "https://cat.io/", basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID)))
+	assert.Equal(t, 1, basics())
+	assert.Equal(t, "https://cat.io/", asset(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset).BaseUri)
+	assert.Equal(t, map[string]int64{bscAsset: 0}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+	assert.Equal(t, map[string]int64{ethAsset: 0}, maps(basedef.BSC_CROSSCHAIN_ID, bscAsset))
+
+	// a later config adding a chain extends the mesh and updates the BaseUri
+	assert.NoError(t, dao.AddAssets(cat(0, "https://cat.io/v2/", basedef.HECO_CROSSCHAIN_ID, basedef.ETHEREUM_CROSSCHAIN_ID)))
+	assert.Equal(t, "https://cat.io/v2/", asset(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset).BaseUri)
+	assert.Equal(t, "https://cat.io/", asset(basedef.BSC_CROSSCHAIN_ID, bscAsset).BaseUri)
+	assert.Equal(t, map[string]int64{bscAsset: 0, hecoAsset: 0}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+	assert.Equal(t, map[string]int64{ethAsset: 0, bscAsset: 0}, maps(basedef.HECO_CROSSCHAIN_ID, hecoAsset))
+
+	// disable one asset
+	basic := cat(0, "https://cat.io/", basedef.BSC_CROSSCHAIN_ID)
+	basic[0].Assets[0].Disable = 1
+	assert.NoError(t, dao.AddAssets(basic))
+	assert.Equal(t, int64(1), asset(basedef.BSC_CROSSCHAIN_ID, bscAsset).Disable)
+	assert.Equal(t, map[string]int64{bscAsset: 1, hecoAsset: 0}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+	assert.Equal(t, map[string]int64{ethAsset: 1, hecoAsset: 1}, maps(basedef.BSC_CROSSCHAIN_ID, bscAsset))
+
+	// single direction
+	hecoToEth := []*models.NFTAssetMap{{SrcChainId: basedef.HECO_CROSSCHAIN_ID, SrcAssetHash: hecoAsset,
+		DstChainId: basedef.ETHEREUM_CROSSCHAIN_ID, DstAssetHash: ethAsset}}
+	assert.NoError(t, dao.RemoveAssetMaps(hecoToEth))
+	assert.Equal(t, map[string]int64{ethAsset: 3, bscAsset: 1}, maps(basedef.HECO_CROSSCHAIN_ID, hecoAsset))
+	assert.Equal(t, map[string]int64{bscAsset: 1, hecoAsset: 0}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+
+	// soft delete keeps the rows
+	assert.NoError(t, dao.RemoveAssets([]string{"cat", "unknown"}))
+	assert.Equal(t, 0, basics())
+	assert.Equal(t, int64(1), asset(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset).Disable)
+	assert.Equal(t, map[string]int64{bscAsset: 1, hecoAsset: 1}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+
+	// enable again, the removed direction stays removed
+	assert.NoError(t, dao.AddAssets(cat(0, "https://cat.io/", basedef.ETHEREUM_CROSSCHAIN_ID, basedef.BSC_CROSSCHAIN_ID, basedef.HECO_CROSSCHAIN_ID)))
+	assert.Equal(t, 1, basics())
+	assert.Equal(t, int64(0), asset(basedef.HECO_CROSSCHAIN_ID, hecoAsset).Disable)
+	assert.Equal(t, map[string]int64{bscAsset: 0, hecoAsset: 0}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+	assert.Equal(t, map[string]int64{ethAsset: 3, bscAsset: 0}, maps(basedef.HECO_CROSSCHAIN_ID, hecoAsset))
+
+	// until it is restored
+	assert.NoError(t, dao.RestoreAssetMaps(hecoToEth))
+	assert.Equal(t, map[string]int64{ethAsset: 0, bscAsset: 0}, maps(basedef.HECO_CROSSCHAIN_ID, hecoAsset))
+
+	// disabling the basic disables the mesh without touching the assets
+	assert.NoError(t, dao.AddAssets(cat(1, "https://cat.io/", basedef.ETHEREUM_CROSSCHAIN_ID)))
+	assert.Equal(t, 0, basics())
+	assert.Equal(t, int64(0), asset(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset).Disable)
+	assert.Equal(t, map[string]int64{bscAsset: 1, hecoAsset: 1}, maps(basedef.ETHEREUM_CROSSCHAIN_ID, ethAsset))
+}
@@ -19,11 +19,13 @@ package controllers
 
 import (
 	"github.com/astaxie/beego"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
 	"github.com/polynetwork/poly-nft-bridge/models"
 )
 
 type AssetController struct {
 	beego.Controller
+	Dao bridgedao.BridgeDao
 }
 
 func (c *AssetController) Assets() {
@@ -32,12 +34,11 @@ func (c *AssetController) Assets() {
 		return
 	}
 
-	assets := make([]*models.NFTAsset, 0)
-	db.Where("chain_id = ?", req.ChainId).
-		Preload("AssetBasic").
-		Preload("AssetMaps").
-		Preload("AssetMaps.DstAsset").
-		Find(&assets)
+	assets, err := c.Dao.GetAssets(req.ChainId)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
 	data := models.MakeNFTAssetsRsp(assets)
 
 	output(&c.Controller, data)
@@ -49,13 +50,12 @@ func (c *AssetController) Asset() {
 		return
 	}
 
-	asset := new(models.NFTAsset)
-	res := db.Where("hash = ? and chain_id = ?", req.Hash, req.ChainId).
-		Preload("AssetBasic").
-		Preload("AssetMaps").
-		Preload("AssetMaps.DstAsset").
-		First(asset)
-	if res.RowsAffected == 0 {
+	asset, err := c.Dao.GetAsset(req.ChainId, req.Hash)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	if asset == nil {
 		notExist(&c.Controller)
 		return
 	}
@@ -63,8 +63,11 @@ func (c *AssetController) Asset() {
 }
 
 func (c *AssetController) AssetBasics() {
-	assetBasics := make([]*models.NFTAssetBasic, 0)
-	db.Model(&models.NFTAssetBasic{}).Preload("Assets").Find(&assetBasics)
+	assetBasics, err := c.Dao.GetAssetBasics()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
 	data := models.MakeNFTAssetBasicsRsp(assetBasics)
 	output(&c.Controller, data)
 }
@@ -19,11 +19,13 @@ package controllers
 
 import (
 	"github.com/astaxie/beego"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
 	"github.com/polynetwork/poly-nft-bridge/models"
 )
 
 type AssetMapController struct {
 	beego.Controller
+	Dao bridgedao.BridgeDao
 }
 
 func (c *AssetMapController) AssetMap() {
@@ -32,12 +34,12 @@ func (c *AssetMapController) AssetMap() {
 		return
 	}
 
-	assetMaps := make([]*models.NFTAssetMap, 0)
-	res := db.Where("src_chain_id = ? and src_asset_hash = ?", req.ChainId, req.Hash).
-		Preload("SrcAsset").
-		Preload("DstAsset").
-		Find(&assetMaps)
-	if res.RowsAffected == 0 {
+	assetMaps, err := c.Dao.GetAssetMaps(req.ChainId, req.Hash)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	if len(assetMaps) == 0 {
 		notExist(&c.Controller)
 		return
 	}
@@ -52,12 +54,12 @@ func (c *AssetMapController) AssetMapReverse() {
 		return
 	}
 
-	assetMaps := make([]*models.NFTAssetMap, 0)
-	res := db.Where("dst_chain_id = ? and dst_asset_hash = ?", req.ChainId, req.Hash).
-		Preload("SrcAsset").
-		Preload("DstAsset").
-		Find(&assetMaps)
-	if res.RowsAffected == 0 {
+	assetMaps, err := c.Dao.GetAssetMapsReverse(req.ChainId, req.Hash)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	if len(assetMaps) == 0 {
 		notExist(&c.Controller)
 		return
 	}
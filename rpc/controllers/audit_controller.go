@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
+	"github.com/polynetwork/poly-nft-bridge/models"
+)
+
+type AuditController struct {
+	beego.Controller
+	Dao bridgedao.BridgeDao
+}
+
+func (c *AuditController) SupplyAudits() {
+	audits, err := c.Dao.GetSupplyAudits()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	data := models.MakeNFTSupplyAuditsRsp(audits)
+	output(&c.Controller, data)
+}
+
+func (c *AuditController) GovernanceEvents() {
+	var req models.GovernanceEventsReq
+	if !input(&c.Controller, &req) {
+		return
+	}
+
+	events, eventNum, err := c.Dao.GetGovernanceEvents(req.ChainId, req.PageNo, req.PageSize)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	paused, err := c.Dao.GetPausedWrappers()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+
+	totalPage := (int(eventNum) + req.PageSize - 1) / req.PageSize
+	totalCount := int(eventNum)
+	data := models.MakeGovernanceEventsRsp(req.PageSize, req.PageNo, totalPage, totalCount, events, paused)
+	output(&c.Controller, data)
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/astaxie/beego"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
+	"github.com/polynetwork/poly-nft-bridge/models"
+)
+
+type BalanceController struct {
+	beego.Controller
+	Dao bridgedao.BridgeDao
+}
+
+func (c *BalanceController) Balances() {
+	balances, err := c.Dao.GetAccountBalances()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	data := models.MakeAccountBalancesRsp(balances)
+	output(&c.Controller, data)
+}
+
+// Metrics exposes the account balances in the prometheus text format.
+func (c *BalanceController) Metrics() {
+	balances, err := c.Dao.GetAccountBalances()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	var body strings.Builder
+	gauge := func(name, help string, value func(balance *models.AccountBalance) string) {
+		fmt.Fprintf(&body, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, balance := range balances {
+			fmt.Fprintf(&body, "%s{chain=\"%d\",role=\"%s\",address=\"%s\",token=\"%s\",name=\"%s\"} %s\n", name,
+				balance.ChainId, balance.Role, balance.Address, balance.Token, balance.Name, value(balance))
+		}
+	}
+	gauge("bridge_account_balance", "Balance of the watched account in whole tokens.", func(balance *models.AccountBalance) string {
+		return models.FormatAmount(balance.Balance, balance.Precision)
+	})
+	gauge("bridge_account_burn_per_day", "Tokens the account spends a day.", func(balance *models.AccountBalance) string {
+		return models.FormatAmount(balance.Burn, balance.Precision)
+	})
+	gauge("bridge_account_runway_seconds", "Seconds the balance lasts, -1 when nothing is spent.", func(balance *models.AccountBalance) string {
+		return fmt.Sprint(balance.Runway)
+	})
+	gauge("bridge_account_low", "1 when the balance is below its minimum.", func(balance *models.AccountBalance) string {
+		return fmt.Sprint(balance.Low)
+	})
+	gauge("bridge_account_updated_seconds", "Time the balance was read.", func(balance *models.AccountBalance) string {
+		return fmt.Sprint(balance.Time)
+	})
+	c.Ctx.Output.Header("Content-Type", "text/plain; version=0.0.4")
+	c.Ctx.Output.Body([]byte(body.String()))
+}
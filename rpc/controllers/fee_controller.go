@@ -18,15 +18,22 @@
 package controllers
 
 import (
+	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/astaxie/beego"
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
 	"github.com/polynetwork/poly-nft-bridge/models"
 )
 
+// MaxFeeChecks is the most transactions a checkfee request may check at once.
+const MaxFeeChecks = 100
+
 type FeeController struct {
 	beego.Controller
+	Dao bridgedao.BridgeDao
 }
 
 // todo: use bridge sdk to get fee
@@ -36,16 +43,22 @@ func (c *FeeController) GetFee() {
 		return
 	}
 
-	token := new(models.Token)
-	res := db.Where("hash = ? and chain_id = ?", req.Hash, req.SrcChainId).Preload("TokenBasic").First(token)
-	if res.RowsAffected == 0 {
+	token, err := c.Dao.GetFeeToken(req.SrcChainId, req.Hash)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	if token == nil {
 		notExist(&c.Controller)
 		return
 	}
 
-	chainFee := new(models.ChainFee)
-	res = db.Where("chain_id = ?", req.DstChainId).Preload("TokenBasic").First(chainFee)
-	if res.RowsAffected == 0 {
+	chainFee, err := c.Dao.GetChainFee(req.DstChainId)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	if chainFee == nil {
 		notExist(&c.Controller)
 		return
 	}
@@ -59,107 +72,137 @@ func (c *FeeController) GetFee() {
 	tokenFee = new(big.Float).Quo(tokenFee, new(big.Float).SetInt64(token.TokenBasic.Price))
 	tokenFeeWithPrecision := new(big.Float).Mul(tokenFee, new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
 
+	paused, err := c.Dao.GetPausedWrappers()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+
 	data := models.MakeGetFeeRsp(req.SrcChainId, req.Hash, req.DstChainId, usdtFee, tokenFee, tokenFeeWithPrecision)
+	data.Paused = paused[req.SrcChainId]
 	output(&c.Controller, data)
 }
 
-//func (c *FeeController) CheckFee() {
-//	logs.Debug("check fee request: %s", string(c.Ctx.Input.RequestBody))
-//	var checkFeesReq models.CheckFeesReq
-//	var err error
-//	if err = json.Unmarshal(c.Ctx.Input.RequestBody, &checkFeesReq); err != nil {
-//		c.Data["json"] = models.MakeErrorRsp(fmt.Sprintf("request parameter is invalid!"))
-//		c.Ctx.ResponseWriter.WriteHeader(400)
-//		c.ServeJSON()
-//	}
-//	hash2ChainId := make(map[string]uint64, 0)
-//	requestHashs := make([]string, 0)
-//	for _, check := range checkFeesReq.Checks {
-//		hash2ChainId[check.Hash] = check.ChainId
-//		requestHashs = append(requestHashs, check.Hash)
-//		requestHashs = append(requestHashs, basedef.HexStringReverse(check.Hash))
-//	}
-//	srcTransactions := make([]*models.SrcTransaction, 0)
-//	db.Model(&models.SrcTransaction{}).Where("(`key` in ? or `hash` in ?)", requestHashs, requestHashs).Find(&srcTransactions)
-//	key2Txhash := make(map[string]string, 0)
-//	for _, srcTransaction := range srcTransactions {
-//		prefix := srcTransaction.Key[0:8]
-//		if prefix == "00000000" {
-//			chainId, ok := hash2ChainId[srcTransaction.Key]
-//			if ok && chainId == srcTransaction.ChainId {
-//				key2Txhash[srcTransaction.Key] = srcTransaction.Hash
-//			}
-//		} else {
-//			key2Txhash[srcTransaction.Hash] = srcTransaction.Hash
-//			key2Txhash[basedef.HexStringReverse(srcTransaction.Hash)] = srcTransaction.Hash
-//		}
-//	}
-//	checkHashes := make([]string, 0)
-//	for _, check := range checkFeesReq.Checks {
-//		newHash, ok := key2Txhash[check.Hash]
-//		if ok {
-//			checkHashes = append(checkHashes, newHash)
-//		}
-//	}
-//	wrapperTransactionWithTokens := make([]*models.WrapperTransactionWithToken, 0)
-//	db.Table("wrapper_transactions").Where("hash in ?", checkHashes).Preload("FeeToken").Preload("FeeToken.AssetBasic").Find(&wrapperTransactionWithTokens)
-//	txHash2WrapperTransaction := make(map[string]*models.WrapperTransactionWithToken, 0)
-//	for _, wrapperTransactionWithToken := range wrapperTransactionWithTokens {
-//		txHash2WrapperTransaction[wrapperTransactionWithToken.Hash] = wrapperTransactionWithToken
-//	}
-//	chainFees := make([]*models.ChainFee, 0)
-//	db.Preload("AssetBasic").Find(&chainFees)
-//	chain2Fees := make(map[uint64]*models.ChainFee, 0)
-//	for _, chainFee := range chainFees {
-//		chain2Fees[chainFee.ChainId] = chainFee
-//	}
-//	checkFees := make([]*models.CheckFee, 0)
-//	for _, check := range checkFeesReq.Checks {
-//		checkFee := &models.CheckFee{}
-//		checkFee.Hash = check.Hash
-//		checkFee.ChainId = check.ChainId
-//		checkFee.Amount = new(big.Float).SetInt64(0)
-//		checkFee.MinProxyFee = new(big.Float).SetInt64(0)
-//		_, ok := chain2Fees[check.ChainId]
-//		if !ok {
-//			checkFee.PayState = -1
-//			checkFees = append(checkFees, checkFee)
-//			continue
-//		}
-//		newHash, ok := key2Txhash[check.Hash]
-//		if !ok {
-//			checkFee.PayState = 0
-//			checkFees = append(checkFees, checkFee)
-//			continue
-//		}
-//		wrapperTransactionWithToken, ok := txHash2WrapperTransaction[newHash]
-//		if !ok {
-//			checkFee.PayState = -1
-//			checkFees = append(checkFees, checkFee)
-//			continue
-//		}
-//		chainFee, ok := chain2Fees[wrapperTransactionWithToken.DstChainId]
-//		if !ok {
-//			checkFee.PayState = -1
-//			checkFees = append(checkFees, checkFee)
-//			continue
-//		}
-//		x := new(big.Int).Mul(&wrapperTransactionWithToken.FeeAmount.Int, big.NewInt(wrapperTransactionWithToken.FeeToken.TokenBasic.Price))
-//		feePay := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.Int64FromFigure(int(wrapperTransactionWithToken.FeeToken.Precision))))
-//		feePay = new(big.Float).Quo(feePay, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-//		x = new(big.Int).Mul(&chainFee.MinFee.Int, big.NewInt(chainFee.TokenBasic.Price))
-//		feeMin := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.PRICE_PRECISION))
-//		feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.FEE_PRECISION))
-//		feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
-//		if feePay.Cmp(feeMin) >= 0 {
-//			checkFee.PayState = 1
-//		} else {
-//			checkFee.PayState = -1
-//		}
-//		checkFee.Amount = feePay
-//		checkFee.MinProxyFee = feeMin
-//		checkFees = append(checkFees, checkFee)
-//	}
-//	c.Data["json"] = models.MakeCheckFeesRsp(checkFees)
-//	c.ServeJSON()
-//}
+// CheckFee tells the relayer whether the fee paid for the transactions, with
+// their speed ups, covers the min fee of the destination chain. PayState is 1
+// when it does, 0 when the transaction is not known yet and -1 otherwise.
+func (c *FeeController) CheckFee() {
+	var req models.CheckFeesReq
+	if !input(&c.Controller, &req) {
+		return
+	}
+	if len(req.Checks) > MaxFeeChecks {
+		customInput(&c.Controller, ErrCodeRequest, fmt.Sprintf("at most %d checks per request", MaxFeeChecks))
+		return
+	}
+
+	hash2ChainId := make(map[string]uint64, 0)
+	requestHashes := make([]string, 0)
+	for _, check := range req.Checks {
+		hash2ChainId[check.Hash] = check.ChainId
+		requestHashes = append(requestHashes, check.Hash)
+		requestHashes = append(requestHashes, basedef.HexStringReverse(check.Hash))
+	}
+	srcTransactions, err := c.Dao.GetSrcTransactionsOfHashes(requestHashes)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	key2TxHash := make(map[string]string, 0)
+	for _, srcTransaction := range srcTransactions {
+		if strings.HasPrefix(srcTransaction.Key, "00000000") {
+			chainId, ok := hash2ChainId[srcTransaction.Key]
+			if ok && chainId == srcTransaction.ChainId {
+				key2TxHash[srcTransaction.Key] = srcTransaction.Hash
+			}
+		} else {
+			key2TxHash[srcTransaction.Hash] = srcTransaction.Hash
+			key2TxHash[basedef.HexStringReverse(srcTransaction.Hash)] = srcTransaction.Hash
+		}
+	}
+	checkHashes := make([]string, 0)
+	for _, check := range req.Checks {
+		if hash, ok := key2TxHash[check.Hash]; ok {
+			checkHashes = append(checkHashes, hash)
+		}
+	}
+	wrapperTransactions, err := c.Dao.GetWrapperTransactionsOfHashes(checkHashes)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	txHash2WrapperTransaction := make(map[string]*models.WrapperTransaction, 0)
+	srcChainIds := make([]uint64, 0)
+	dstChainIds := make([]uint64, 0)
+	feeTokenHashes := make([]string, 0)
+	for _, wrapperTransaction := range wrapperTransactions {
+		txHash2WrapperTransaction[wrapperTransaction.Hash] = wrapperTransaction
+		srcChainIds = append(srcChainIds, wrapperTransaction.SrcChainId)
+		dstChainIds = append(dstChainIds, wrapperTransaction.DstChainId)
+		feeTokenHashes = append(feeTokenHashes, wrapperTransaction.FeeTokenHash)
+	}
+	tokens, err := c.Dao.GetFeeTokens(srcChainIds, feeTokenHashes)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	feeTokens := make(map[uint64]map[string]*models.Token, 0)
+	for _, token := range tokens {
+		if feeTokens[token.ChainId] == nil {
+			feeTokens[token.ChainId] = make(map[string]*models.Token, 0)
+		}
+		feeTokens[token.ChainId][token.Hash] = token
+	}
+	chainFees, err := c.Dao.GetChainFees(dstChainIds)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	dstChainFees := make(map[uint64]*models.ChainFee, 0)
+	for _, chainFee := range chainFees {
+		dstChainFees[chainFee.ChainId] = chainFee
+	}
+
+	checkFees := make([]*models.CheckFee, 0)
+	for _, check := range req.Checks {
+		checkFee := &models.CheckFee{
+			Hash:        check.Hash,
+			ChainId:     check.ChainId,
+			PayState:    -1,
+			Amount:      new(big.Float),
+			MinProxyFee: new(big.Float),
+		}
+		checkFees = append(checkFees, checkFee)
+		hash, ok := key2TxHash[check.Hash]
+		if !ok {
+			checkFee.PayState = 0
+			continue
+		}
+		wrapperTransaction, ok := txHash2WrapperTransaction[hash]
+		if !ok {
+			continue
+		}
+		token := feeTokens[wrapperTransaction.SrcChainId][wrapperTransaction.FeeTokenHash]
+		chainFee := dstChainFees[wrapperTransaction.DstChainId]
+		if token == nil || chainFee == nil {
+			continue
+		}
+		totalFee := wrapperTransaction.TotalFee
+		if totalFee == nil {
+			totalFee = wrapperTransaction.FeeAmount
+		}
+		x := new(big.Int).Mul(&totalFee.Int, big.NewInt(token.TokenBasic.Price))
+		feePay := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.Int64FromFigure(int(token.Precision))))
+		feePay = new(big.Float).Quo(feePay, new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+		x = new(big.Int).Mul(&chainFee.MinFee.Int, big.NewInt(chainFee.TokenBasic.Price))
+		feeMin := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt64(basedef.PRICE_PRECISION))
+		feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.FEE_PRECISION))
+		feeMin = new(big.Float).Quo(feeMin, new(big.Float).SetInt64(basedef.Int64FromFigure(int(chainFee.TokenBasic.Precision))))
+		if feePay.Cmp(feeMin) >= 0 {
+			checkFee.PayState = 1
+		}
+		checkFee.Amount = feePay
+		checkFee.MinProxyFee = feeMin
+	}
+	output(&c.Controller, models.MakeCheckFeesRsp(checkFees))
+}
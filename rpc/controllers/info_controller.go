@@ -23,6 +23,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/astaxie/beego"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
 	"github.com/polynetwork/poly-nft-bridge/models"
 	"github.com/polynetwork/poly-nft-bridge/utils/net"
 )
@@ -34,6 +35,8 @@ var (
 
 type InfoController struct {
 	beego.Controller
+	Dao   bridgedao.BridgeDao
+	Nodes Nodes
 }
 
 func (c *InfoController) Get() {
@@ -58,14 +61,17 @@ func (c *InfoController) Home() {
 		return
 	}
 
-	sdk := selectNode(req.ChainId)
+	sdk := c.Nodes.selectNode(req.ChainId)
 	if sdk == nil {
 		customInput(&c.Controller, ErrCodeRequest, "chain id not exist")
 		return
 	}
 
-	assets := make([]*models.NFTAsset, 0)
-	db.Where("chain_id = ?", req.ChainId).Find(&assets)
+	assets, err := c.Dao.GetAssets(req.ChainId)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
 	totalCnt := len(assets)
 
 	assetItems := make([]*models.AssetItems, 0)
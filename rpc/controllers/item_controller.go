@@ -25,6 +25,7 @@ import (
 
 type ItemController struct {
 	beego.Controller
+	Nodes Nodes
 }
 
 // todo: cache url and token ids
@@ -34,7 +35,7 @@ func (c *ItemController) Items() {
 		return
 	}
 
-	sdk := selectNode(req.ChainId)
+	sdk := c.Nodes.selectNode(req.ChainId)
 	if sdk == nil {
 		customInput(&c.Controller, ErrCodeRequest, "chain id not exist")
 		return
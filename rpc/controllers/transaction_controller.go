@@ -19,11 +19,13 @@ package controllers
 
 import (
 	"github.com/astaxie/beego"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
 	"github.com/polynetwork/poly-nft-bridge/models"
 )
 
 type TransactionController struct {
 	beego.Controller
+	Dao bridgedao.BridgeDao
 }
 
 func (c *TransactionController) Transactions() {
@@ -32,18 +34,24 @@ func (c *TransactionController) Transactions() {
 		return
 	}
 
-	transactions := make([]*models.WrapperTransaction, 0)
-	db.Limit(req.PageSize).
-		Offset(req.PageSize * req.PageNo).
-		Order("time asc").
-		Find(&transactions)
+	transactions, transactionNum, err := c.Dao.GetWrapperTransactions(req.PageNo, req.PageSize)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
 
-	var transactionNum int64
-	db.Model(&models.WrapperTransaction{}).Count(&transactionNum)
+	paused, err := c.Dao.GetPausedWrappers()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
 
 	totalPage := (int(transactionNum) + req.PageSize - 1) / req.PageSize
 	totalCnt := int(transactionNum)
 	data := models.MakeWrapperTransactionsRsp(req.PageSize, req.PageNo, totalPage, totalCnt, transactions)
+	for _, transaction := range data.Transactions {
+		transaction.Paused = paused[transaction.SrcChainId]
+	}
 	output(&c.Controller, data)
 }
 
@@ -54,52 +62,29 @@ func (c *TransactionController) TransactionsOfAddress() {
 		return
 	}
 
-	// load relations
-	srcPolyDstRelations := make([]*models.SrcPolyDstRelation, 0)
-	db.Table("(?) as u", db.Model(&models.SrcTransfer{}).
-		Select("src_transfers.tx_hash as hash, src_transfers.asset as asset, wrapper_transactions.fee_token_hash as fee_token_hash").
-		Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-		Where("`from` in ? or src_transfers.dst_user in ?", req.Addresses, req.Addresses)).
-		Select("src_transactions.hash as src_hash, " +
-			"poly_transactions.hash as poly_hash, " +
-			"dst_transactions.hash as dst_hash, " +
-			"src_transactions.chain_id as chain_id," +
-			"u.asset as asset_hash, u.fee_token_hash as fee_token_hash").
-		Joins("left join src_transactions on u.hash = src_transactions.hash").
-		Joins("left join poly_transactions on src_transactions.hash = poly_transactions.src_hash").
-		Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
-		Preload("WrapperTransaction").
-		Preload("Asset").
-		Preload("Asset.AssetBasic").
-		Preload("FeeToken").
-		Preload("FeeToken.TokenBasic").
-		Preload("SrcTransaction").
-		Preload("SrcTransaction.SrcTransfer").
-		Preload("PolyTransaction").
-		Preload("DstTransaction").
-		Preload("DstTransaction.DstTransfer").
-		Limit(req.PageSize).Offset(req.PageSize * req.PageNo).
-		Order("src_transactions.time desc").
-		Find(&srcPolyDstRelations)
-
-	// get transaction number
-	var transactionNum int64
-	db.Model(&models.SrcTransfer{}).
-		Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-		Where("`from` in ? or src_transfers.dst_user in ?", req.Addresses, req.Addresses).
-		Count(&transactionNum)
-
-	// get chains
-	chains := make([]*models.Chain, 0)
-	db.Model(&models.Chain{}).Find(&chains)
-	chainsMap := make(map[uint64]*models.Chain)
-	for _, chain := range chains {
-		chainsMap[chain.ChainId] = chain
+	srcPolyDstRelations, transactionNum, err := c.Dao.GetTransactionsOfAddress(req.Addresses, req.PageNo, req.PageSize)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+
+	chainsMap, err := c.Dao.GetChains()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	paused, err := c.Dao.GetPausedWrappers()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
 	}
 
 	totalPage := (int(transactionNum) + req.PageSize - 1) / req.PageSize
 	totalCnt := int(transactionNum)
 	data := models.MakeTransactionsOfUserRsp(req.PageSize, req.PageNo, totalPage, totalCnt, srcPolyDstRelations, chainsMap)
+	for _, transaction := range data.Transactions {
+		transaction.Paused = paused[transaction.SrcChainId]
+	}
 	output(&c.Controller, data)
 }
 
@@ -110,45 +95,44 @@ func (c *TransactionController) TransactionOfHash() {
 		return
 	}
 
-	srcPolyDstRelation := new(models.SrcPolyDstRelation)
-	res := db.Table("(?) as u", db.Model(&models.SrcTransfer{}).
-		Select("src_transfers.tx_hash as hash, src_transfers.asset as asset, wrapper_transactions.fee_token_hash as fee_token_hash").
-		Joins("inner join wrapper_transactions on src_transfers.tx_hash = wrapper_transactions.hash").
-		Where("src_transfers.tx_hash =?", req.Hash)).
-		Select("src_transactions.hash as src_hash, " +
-			"poly_transactions.hash as poly_hash, " +
-			"dst_transactions.hash as dst_hash, " +
-			"src_transactions.chain_id as chain_id," +
-			"u.asset as asset_hash, u.fee_token_hash as fee_token_hash").
-		Joins("left join src_transactions on u.hash = src_transactions.hash").
-		Joins("left join poly_transactions on src_transactions.hash = poly_transactions.src_hash").
-		Joins("left join dst_transactions on poly_transactions.hash = dst_transactions.poly_hash").
-		Preload("WrapperTransaction").
-		Preload("Asset").
-		Preload("Asset.AssetBasic").
-		Preload("FeeToken").
-		Preload("FeeToken.TokenBasic").
-		Preload("SrcTransaction").
-		Preload("SrcTransaction.SrcTransfer").
-		Preload("PolyTransaction").
-		Preload("DstTransaction").
-		Preload("DstTransaction.DstTransfer").
-		Order("src_transactions.time desc").
-		Find(srcPolyDstRelation)
-
-	if res.RowsAffected == 0 {
+	srcPolyDstRelation, err := c.Dao.GetTransactionOfHash(req.Hash)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	if srcPolyDstRelation == nil {
 		notExist(&c.Controller)
 		return
 	}
 
-	chains := make([]*models.Chain, 0)
-	db.Model(&models.Chain{}).Find(&chains)
-	chainsMap := make(map[uint64]*models.Chain)
-	for _, chain := range chains {
-		chainsMap[chain.ChainId] = chain
+	chainsMap, err := c.Dao.GetChains()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+	paused, err := c.Dao.GetPausedWrappers()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
+
+	speedUps, err := c.Dao.GetWrapperSpeedUps(srcPolyDstRelation.SrcHash)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
 	}
 
 	data := models.MakeTransactionRsp(srcPolyDstRelation, chainsMap)
+	data.Paused = paused[data.SrcChainId]
+	data.FeeHistory = models.MakeFeeHistoryRsp(srcPolyDstRelation.WrapperTransaction, speedUps, srcPolyDstRelation.FeeToken)
+	if srcPolyDstRelation.PolyHash != "" {
+		failures, err := c.Dao.GetRelayFailures(srcPolyDstRelation.PolyHash)
+		if err != nil {
+			dbInvalid(&c.Controller)
+			return
+		}
+		data.RelayFailures = models.MakeRelayFailuresRsp(failures)
+	}
 	output(&c.Controller, data)
 }
 
@@ -158,20 +142,23 @@ func (c *TransactionController) TransactionsOfState() {
 		return
 	}
 
-	transactions := make([]*models.WrapperTransaction, 0)
-	db.Where("status = ?", req.State).
-		Limit(req.PageSize).
-		Offset(req.PageSize * req.PageNo).
-		Order("time asc").
-		Find(&transactions)
+	transactions, transactionNum, err := c.Dao.GetWrapperTransactionsOfState(req.State, req.PageNo, req.PageSize)
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
 
-	var transactionNum int64
-	db.Model(&models.WrapperTransaction{}).
-		Where("status = ?", req.State).
-		Count(&transactionNum)
+	paused, err := c.Dao.GetPausedWrappers()
+	if err != nil {
+		dbInvalid(&c.Controller)
+		return
+	}
 
 	totalPage := (int(transactionNum) + req.PageSize - 1) / req.PageSize
 	totalCount := int(transactionNum)
 	data := models.MakeTransactionsOfStateRsp(req.PageSize, req.PageNo, totalPage, totalCount, transactions)
+	for _, transaction := range data.Transactions {
+		transaction.Paused = paused[transaction.SrcChainId]
+	}
 	output(&c.Controller, data)
 }
@@ -19,78 +19,54 @@ package controllers
 
 import (
 	"encoding/json"
+	"math/big"
 
 	"github.com/astaxie/beego"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/polynetwork/poly-nft-bridge/conf"
 	"github.com/polynetwork/poly-nft-bridge/models"
 	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-var (
-	db   = newDB()
-	sdks = make(map[uint64]*eth_sdk.EthereumSdkPro)
-)
-
-func newDB() *gorm.DB {
-	user := beego.AppConfig.String("mysqluser")
-	password := beego.AppConfig.String("mysqlpass")
-	url := beego.AppConfig.String("mysqlurls")
-	scheme := beego.AppConfig.String("mysqldb")
-	mode := beego.AppConfig.String("runmode")
-	Logger := logger.Default
-	if mode == "dev" {
-		Logger = Logger.LogMode(logger.Info)
-	}
-	db, err := gorm.Open(mysql.Open(user+":"+password+"@tcp("+url+")/"+scheme+"?charset=utf8"), &gorm.Config{Logger: Logger})
-	if err != nil {
-		panic(err)
-	}
-	return db
+// NFTNode is the chain side used by the routes which query nft contracts.
+type NFTNode interface {
+	NFTBalance(asset, owner common.Address) (int, error)
+	GetNFTs(asset, owner common.Address, start, end int) ([]*big.Int, error)
+	GetAssetNFTs(asset common.Address, start, end int) ([]*big.Int, error)
+	GetNFTURLs(asset common.Address, tokenIds []*big.Int) (map[uint64]string, error)
 }
 
-func Initialize(c *conf.Config) {
-	//var err error
-	//Logger := logger.Default
-	//if c.DBConfig.Debug {
-	//	Logger = Logger.LogMode(logger.Info)
-	//}
-	//link := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8",
-	//	c.DBConfig.User,
-	//	c.DBConfig.Password,
-	//	c.DBConfig.URL,
-	//	c.DBConfig.Scheme,
-	//)
-	//if db, err = gorm.Open(mysql.Open(link), &gorm.Config{Logger: Logger}); err != nil {
-	//	panic(err)
-	//}
+type Nodes map[uint64]NFTNode
 
+func NewNodes(c *conf.Config) Nodes {
+	nodes := make(Nodes)
 	for _, v := range c.ChainListenConfig {
 		pro := eth_sdk.NewEthereumSdkPro(v.GetNodesUrl(), v.ListenSlot, v.ChainId)
-		sdks[v.ChainId] = pro
+		nodes[v.ChainId] = pro
 	}
+	return nodes
 }
 
-func selectNode(chainID uint64) *eth_sdk.EthereumSdkPro {
-	pro, ok := sdks[chainID]
+func (n Nodes) selectNode(chainID uint64) NFTNode {
+	node, ok := n[chainID]
 	if !ok {
 		return nil
 	}
-	return pro
+	return node
 }
 
 const (
 	ErrCodeRequest     int = 400
 	ErrCodeNotExist    int = 404
 	ErrCodeNodeInvalid int = 500
+	ErrCodeDBInvalid   int = 503
 )
 
 var errMap = map[int]string{
 	ErrCodeRequest:     "request parameter is invalid!",
 	ErrCodeNotExist:    "not found",
 	ErrCodeNodeInvalid: "blockchain node exception",
+	ErrCodeDBInvalid:   "database exception",
 }
 
 func input(c *beego.Controller, req interface{}) bool {
@@ -123,6 +99,13 @@ func nodeInvalid(c *beego.Controller) {
 	c.ServeJSON()
 }
 
+func dbInvalid(c *beego.Controller) {
+	code := ErrCodeDBInvalid
+	c.Data["json"] = models.MakeErrorRsp(errMap[code])
+	c.Ctx.ResponseWriter.WriteHeader(code)
+	c.ServeJSON()
+}
+
 func output(c *beego.Controller, data interface{}) {
 	c.Data["json"] = data
 	c.ServeJSON()
@@ -0,0 +1,196 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package filters
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/astaxie/beego/context"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	"github.com/polynetwork/poly-nft-bridge/models"
+)
+
+const (
+	HeaderApiKey = "X-Api-Key"
+	QueryApiKey  = "apikey"
+)
+
+var defaultNodeRoutes = []string{"/nft/v1/items/", "/nft/v1/assetshow/"}
+
+type apiKey struct {
+	name      string
+	limit     *conf.RateLimit
+	nodeLimit *conf.RateLimit
+}
+
+// ApiFilter authenticates api keys and rate limits every request, per key when
+// a key is presented and per client ip otherwise. Node-backed routes draw on
+// their own buckets so that they can be budgeted tighter than db queries.
+type ApiFilter struct {
+	cfg        *conf.HttpConfig
+	nodeRoutes map[string]bool
+	lock       sync.RWMutex
+	cfgKeys    map[string]*apiKey
+	keys       map[string]*apiKey
+	keyLimiter *limiter
+	ipLimiter  *limiter
+	now        func() time.Time
+}
+
+func NewApiFilter(cfg *conf.HttpConfig) *ApiFilter {
+	f := &ApiFilter{
+		cfg:        cfg,
+		nodeRoutes: make(map[string]bool),
+		cfgKeys:    make(map[string]*apiKey),
+		keyLimiter: newLimiter(),
+		ipLimiter:  newLimiter(),
+		now:        time.Now,
+	}
+	routes := cfg.NodeRoutes
+	if len(routes) == 0 {
+		routes = defaultNodeRoutes
+	}
+	for _, route := range routes {
+		f.nodeRoutes[normalizeRoute(route)] = true
+	}
+	for _, key := range cfg.ApiKeys {
+		f.cfgKeys[key.Key] = &apiKey{
+			name:      key.Name,
+			limit:     key.Limit,
+			nodeLimit: key.NodeLimit,
+		}
+	}
+	f.SetKeys(nil)
+	return f
+}
+
+// SetKeys replaces the keys loaded from db. Keys from the config file are always kept.
+func (f *ApiFilter) SetKeys(keys []*models.ApiKey) {
+	all := make(map[string]*apiKey, len(f.cfgKeys)+len(keys))
+	for _, key := range keys {
+		if key.Disable != 0 {
+			continue
+		}
+		k := &apiKey{name: key.Name}
+		if key.Rate > 0 {
+			k.limit = &conf.RateLimit{Rate: key.Rate, Burst: int(key.Burst)}
+		}
+		if key.NodeRate > 0 {
+			k.nodeLimit = &conf.RateLimit{Rate: key.NodeRate, Burst: int(key.NodeBurst)}
+		}
+		all[key.Key] = k
+	}
+	for key, k := range f.cfgKeys {
+		all[key] = k
+	}
+	f.lock.Lock()
+	f.keys = all
+	f.lock.Unlock()
+}
+
+func (f *ApiFilter) lookup(key string) *apiKey {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.keys[key]
+}
+
+func (f *ApiFilter) Filter(ctx *context.Context) {
+	if ctx.Input.Method() == http.MethodOptions {
+		return
+	}
+	node := f.nodeRoutes[normalizeRoute(ctx.Input.URL())]
+	key := ctx.Input.Header(HeaderApiKey)
+	if key == "" {
+		key = ctx.Request.URL.Query().Get(QueryApiKey)
+	}
+	if key != "" {
+		k := f.lookup(key)
+		if k == nil {
+			abort(ctx, http.StatusUnauthorized, "invalid api key")
+			return
+		}
+		limit := pickLimit(k.limit, f.cfg.KeyLimit)
+		if node {
+			limit = pickLimit(k.nodeLimit, f.cfg.KeyNodeLimit)
+		}
+		f.limit(ctx, f.keyLimiter, bucketName(key, node), limit)
+		return
+	}
+	if f.cfg.AuthRequired {
+		abort(ctx, http.StatusUnauthorized, "api key required")
+		return
+	}
+	limit := f.cfg.IpLimit
+	if node {
+		limit = f.cfg.IpNodeLimit
+	}
+	f.limit(ctx, f.ipLimiter, bucketName(f.clientIP(ctx), node), limit)
+}
+
+func (f *ApiFilter) limit(ctx *context.Context, l *limiter, client string, limit *conf.RateLimit) {
+	if unlimited(limit) {
+		return
+	}
+	ok, remaining, wait := l.allow(client, limit, f.now())
+	ctx.Output.Header("X-RateLimit-Limit", fmt.Sprintf("%d", int(burstOf(limit))))
+	ctx.Output.Header("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
+	if !ok {
+		ctx.Output.Header("Retry-After", fmt.Sprintf("%d", int(math.Ceil(wait.Seconds()))))
+		abort(ctx, http.StatusTooManyRequests, "too many requests")
+	}
+}
+
+func (f *ApiFilter) clientIP(ctx *context.Context) string {
+	if f.cfg.TrustProxy {
+		return ctx.Input.IP()
+	}
+	host, _, err := net.SplitHostPort(ctx.Request.RemoteAddr)
+	if err != nil {
+		return ctx.Request.RemoteAddr
+	}
+	return host
+}
+
+func pickLimit(limit, fallback *conf.RateLimit) *conf.RateLimit {
+	if limit != nil {
+		return limit
+	}
+	return fallback
+}
+
+func bucketName(client string, node bool) string {
+	if node {
+		return "node:" + client
+	}
+	return "api:" + client
+}
+
+func normalizeRoute(route string) string {
+	return strings.TrimSuffix(route, "/")
+}
+
+func abort(ctx *context.Context, code int, msg string) {
+	ctx.Output.SetStatus(code)
+	ctx.Output.JSON(models.MakeErrorRsp(msg), false, false)
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/astaxie/beego"
+	"github.com/astaxie/beego/context"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testServer struct {
+	handler *beego.ControllerRegister
+	filter  *ApiFilter
+	clock   time.Time
+}
+
+func newTestServer(cfg *conf.HttpConfig) *testServer {
+	s := &testServer{
+		handler: beego.NewControllerRegister(),
+		filter:  NewApiFilter(cfg),
+		clock:   time.Unix(1600000000, 0),
+	}
+	s.filter.now = func() time.Time { return s.clock }
+	s.handler.InsertFilter("*", beego.BeforeRouter, s.filter.Filter)
+	ok := func(ctx *context.Context) { ctx.Output.Body([]byte("ok")) }
+	s.handler.Post("/nft/v1/assets/", ok)
+	s.handler.Post("/nft/v1/items/", ok)
+	s.handler.Post("/nft/v1/assetshow/", ok)
+	return s
+}
+
+func (s *testServer) do(path string, ip string, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.RemoteAddr = ip + ":52000"
+	if key != "" {
+		req.Header.Set(HeaderApiKey, key)
+	}
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIpRateLimit(t *testing.T) {
+	s := newTestServer(&conf.HttpConfig{
+		IpLimit: &conf.RateLimit{Rate: 1, Burst: 2},
+	})
+	assert.Equal(t, http.StatusOK, s.do("/nft/v1/assets/", "10.0.0.1", "").Code)
+	rec := s.do("/nft/v1/assets/", "10.0.0.1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+
+	rec = s.do("/nft/v1/assets/", "10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"Message":"too many requests"}`, rec.Body.String())
+
+	// another client has its own bucket
+	assert.Equal(t, http.StatusOK, s.do("/nft/v1/assets/", "10.0.0.2", "").Code)
+
+	s.clock = s.clock.Add(time.Second)
+	assert.Equal(t, http.StatusOK, s.do("/nft/v1/assets/", "10.0.0.1", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, s.do("/nft/v1/assets/", "10.0.0.1", "").Code)
+}
+
+func TestNodeRouteBudget(t *testing.T) {
+	s := newTestServer(&conf.HttpConfig{
+		IpLimit:     &conf.RateLimit{Rate: 10, Burst: 10},
+		IpNodeLimit: &conf.RateLimit{Rate: 0.1, Burst: 1},
+	})
+	assert.Equal(t, http.StatusOK, s.do("/nft/v1/items/", "10.0.0.1", "").Code)
+	rec := s.do("/nft/v1/assetshow/", "10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("Retry-After"))
+	// db backed routes are not affected by the node budget
+	assert.Equal(t, http.StatusOK, s.do("/nft/v1/assets/", "10.0.0.1", "").Code)
+}
+
+func TestApiKeyAuth(t *testing.T) {
+	s := newTestServer(&conf.HttpConfig{
+		AuthRequired: true,
+		ApiKeys: []*conf.ApiKeyConfig{
+			{Key: "cfg-key", Name: "wallet", Limit: &conf.RateLimit{Rate: 1, Burst: 1}},
+		},
+		KeyLimit: &conf.RateLimit{Rate: 1, Burst: 3},
+	})
+	assert.Equal(t, http.StatusUnauthorized, s.do("/nft/v1/assets/", "10.0.0.1", "").Code)
+	rec := s.do("/nft/v1/assets/", "10.0.0.1", "unknown")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{"Message":"invalid api key"}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusOK, s.do("/nft/v1/assets/", "10.0.0.1", "cfg-key").Code)
+	// the key is limited regardless of the ip it comes from
+	assert.Equal(t, http.StatusTooManyRequests, s.do("/nft/v1/assets/", "10.0.0.2", "cfg-key").Code)
+
+	// keys from db fall back to the default key limit and can be passed as a query
+	s.filter.SetKeys([]*models.ApiKey{{Key: "db-key", Name: "explorer"}, {Key: "off-key", Disable: 1}})
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/nft/v1/assets/?"+QueryApiKey+"=db-key", nil)
+		rec := httptest.NewRecorder()
+		s.handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, s.do("/nft/v1/assets/", "10.0.0.1", "db-key").Code)
+	assert.Equal(t, http.StatusUnauthorized, s.do("/nft/v1/assets/", "10.0.0.1", "off-key").Code)
+	assert.Equal(t, http.StatusTooManyRequests, s.do("/nft/v1/assets/", "10.0.0.1", "cfg-key").Code)
+}
+
+func TestOptionalAuth(t *testing.T) {
+	s := newTestServer(&conf.HttpConfig{
+		ApiKeys: []*conf.ApiKeyConfig{{Key: "cfg-key"}},
+		IpLimit: &conf.RateLimit{Rate: 1, Burst: 1},
+	})
+	assert.Equal(t, http.StatusOK, s.do("/nft/v1/assets/", "10.0.0.1", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, s.do("/nft/v1/assets/", "10.0.0.1", "").Code)
+	// a valid key without a key limit is not limited at all
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, s.do("/nft/v1/assets/", "10.0.0.1", "cfg-key").Code)
+	}
+	assert.Equal(t, http.StatusUnauthorized, s.do("/nft/v1/assets/", "10.0.0.1", "bad-key").Code)
+
+	req := httptest.NewRequest(http.MethodOptions, "/nft/v1/assets/", nil)
+	req.RemoteAddr = "10.0.0.1:52000"
+	rec := httptest.NewRecorder()
+	s.handler.ServeHTTP(rec, req)
+	assert.NotEqual(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestTrustProxy(t *testing.T) {
+	s := newTestServer(&conf.HttpConfig{
+		TrustProxy: true,
+		IpLimit:    &conf.RateLimit{Rate: 1, Burst: 1},
+	})
+	do := func(forwarded string) int {
+		req := httptest.NewRequest(http.MethodPost, "/nft/v1/assets/", nil)
+		req.RemoteAddr = "127.0.0.1:52000"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		rec := httptest.NewRecorder()
+		s.handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, do("10.0.0.1"))
+	assert.Equal(t, http.StatusOK, do("10.0.0.2"))
+	assert.Equal(t, http.StatusTooManyRequests, do("10.0.0.1"))
+}
+
+func TestLimiterSweep(t *testing.T) {
+	l := newLimiter()
+	limit := &conf.RateLimit{Rate: 1, Burst: 2}
+	now := time.Unix(1600000000, 0)
+	l.allow("a", limit, now)
+	l.allow("b", limit, now)
+	assert.Equal(t, 2, l.size())
+	l.allow("b", limit, now.Add(sweepInterval+time.Second))
+	assert.Equal(t, 1, l.size())
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package filters
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+)
+
+const (
+	sweepInterval = time.Minute
+)
+
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// limiter keeps one token bucket per client. Buckets that have been idle long
+// enough to refill completely carry no state and are dropped on sweep.
+type limiter struct {
+	lock      sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func newLimiter() *limiter {
+	return &limiter{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// unlimited reports whether the limit disables rate limiting.
+func unlimited(limit *conf.RateLimit) bool {
+	return limit == nil || limit.Rate <= 0
+}
+
+func burstOf(limit *conf.RateLimit) float64 {
+	if limit.Burst > 0 {
+		return float64(limit.Burst)
+	}
+	return math.Max(1, math.Ceil(limit.Rate))
+}
+
+// allow takes one token from the client bucket. It returns the tokens left and,
+// when the request is rejected, how long until the next token is available.
+func (l *limiter) allow(client string, limit *conf.RateLimit, now time.Time) (bool, int, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if now.Sub(l.lastSweep) > sweepInterval {
+		l.sweep(now)
+		l.lastSweep = now
+	}
+
+	rate, burst := limit.Rate, burstOf(limit)
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[client] = b
+	}
+	b.rate, b.burst = rate, burst
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, int(b.tokens), 0
+	}
+	wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	return false, 0, wait
+}
+
+func (l *limiter) sweep(now time.Time) {
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+func (l *limiter) size() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.buckets)
+}
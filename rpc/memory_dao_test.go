@@ -0,0 +1,281 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"strings"
+
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/models"
+)
+
+// memoryDao serves the rpc controllers from preloaded models.
+type memoryDao struct {
+	chains    map[uint64]*models.Chain
+	basics    []*models.NFTAssetBasic
+	assets    []*models.NFTAsset
+	assetMaps []*models.NFTAssetMap
+	tokens    []*models.Token
+	chainFees []*models.ChainFee
+	wrappers  []*models.WrapperTransaction
+	speedUps  []*models.WrapperSpeedUp  // ordered by height
+	failures  []*models.DstRelayFailure // ordered by height
+	relations []*models.SrcPolyDstRelation
+	apiKeys   []*models.ApiKey
+	audits    []*models.NFTSupplyAudit
+	events    []*models.GovernanceEvent // ordered by height
+	balances  []*models.AccountBalance
+}
+
+func (dao *memoryDao) GetAssets(chainId uint64) ([]*models.NFTAsset, error) {
+	assets := make([]*models.NFTAsset, 0)
+	for _, asset := range dao.assets {
+		if asset.ChainId == chainId {
+			assets = append(assets, asset)
+		}
+	}
+	return assets, nil
+}
+
+func (dao *memoryDao) GetAsset(chainId uint64, hash string) (*models.NFTAsset, error) {
+	for _, asset := range dao.assets {
+		if asset.ChainId == chainId && asset.Hash == hash {
+			return asset, nil
+		}
+	}
+	return nil, nil
+}
+
+func (dao *memoryDao) GetAssetBasics() ([]*models.NFTAssetBasic, error) {
+	return dao.basics, nil
+}
+
+func (dao *memoryDao) GetAssetMaps(srcChainId uint64, srcHash string) ([]*models.NFTAssetMap, error) {
+	assetMaps := make([]*models.NFTAssetMap, 0)
+	for _, assetMap := range dao.assetMaps {
+		if assetMap.SrcChainId == srcChainId && assetMap.SrcAssetHash == srcHash {
+			assetMaps = append(assetMaps, assetMap)
+		}
+	}
+	return assetMaps, nil
+}
+
+func (dao *memoryDao) GetAssetMapsReverse(dstChainId uint64, dstHash string) ([]*models.NFTAssetMap, error) {
+	assetMaps := make([]*models.NFTAssetMap, 0)
+	for _, assetMap := range dao.assetMaps {
+		if assetMap.DstChainId == dstChainId && assetMap.DstAssetHash == dstHash {
+			assetMaps = append(assetMaps, assetMap)
+		}
+	}
+	return assetMaps, nil
+}
+
+func (dao *memoryDao) GetFeeToken(chainId uint64, hash string) (*models.Token, error) {
+	for _, token := range dao.tokens {
+		if token.ChainId == chainId && token.Hash == hash {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (dao *memoryDao) GetChainFee(chainId uint64) (*models.ChainFee, error) {
+	for _, chainFee := range dao.chainFees {
+		if chainFee.ChainId == chainId {
+			return chainFee, nil
+		}
+	}
+	return nil, nil
+}
+
+func (dao *memoryDao) GetFeeTokens(chainIds []uint64, hashes []string) ([]*models.Token, error) {
+	tokens := make([]*models.Token, 0)
+	for _, token := range dao.tokens {
+		if containsChain(chainIds, token.ChainId) && contains(hashes, token.Hash) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (dao *memoryDao) GetChainFees(chainIds []uint64) ([]*models.ChainFee, error) {
+	chainFees := make([]*models.ChainFee, 0)
+	for _, chainFee := range dao.chainFees {
+		if containsChain(chainIds, chainFee.ChainId) {
+			chainFees = append(chainFees, chainFee)
+		}
+	}
+	return chainFees, nil
+}
+
+func (dao *memoryDao) GetChains() (map[uint64]*models.Chain, error) {
+	return dao.chains, nil
+}
+
+func page(total, pageNo, pageSize int) (int, int) {
+	start, end := pageNo*pageSize, (pageNo+1)*pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func (dao *memoryDao) GetWrapperTransactions(pageNo, pageSize int) ([]*models.WrapperTransaction, int64, error) {
+	start, end := page(len(dao.wrappers), pageNo, pageSize)
+	return dao.wrappers[start:end], int64(len(dao.wrappers)), nil
+}
+
+func (dao *memoryDao) GetWrapperTransactionsOfState(state uint64, pageNo, pageSize int) ([]*models.WrapperTransaction, int64, error) {
+	transactions := make([]*models.WrapperTransaction, 0)
+	for _, wrapper := range dao.wrappers {
+		if wrapper.Status == state {
+			transactions = append(transactions, wrapper)
+		}
+	}
+	start, end := page(len(transactions), pageNo, pageSize)
+	return transactions[start:end], int64(len(transactions)), nil
+}
+
+func (dao *memoryDao) GetTransactionsOfAddress(addresses []string, pageNo, pageSize int) ([]*models.SrcPolyDstRelation, int64, error) {
+	relations := make([]*models.SrcPolyDstRelation, 0)
+	for _, relation := range dao.relations {
+		transfer := relation.SrcTransaction.SrcTransfer
+		for _, address := range addresses {
+			if strings.EqualFold(transfer.From, address) || strings.EqualFold(transfer.DstUser, address) {
+				relations = append(relations, relation)
+				break
+			}
+		}
+	}
+	start, end := page(len(relations), pageNo, pageSize)
+	return relations[start:end], int64(len(relations)), nil
+}
+
+func (dao *memoryDao) GetTransactionOfHash(hash string) (*models.SrcPolyDstRelation, error) {
+	for _, relation := range dao.relations {
+		if relation.SrcHash == hash {
+			return relation, nil
+		}
+	}
+	return nil, nil
+}
+
+func (dao *memoryDao) GetWrapperSpeedUps(hash string) ([]*models.WrapperSpeedUp, error) {
+	speedUps := make([]*models.WrapperSpeedUp, 0)
+	for _, speedUp := range dao.speedUps {
+		if speedUp.SrcHash == hash {
+			speedUps = append(speedUps, speedUp)
+		}
+	}
+	return speedUps, nil
+}
+
+func (dao *memoryDao) GetRelayFailures(polyHash string) ([]*models.DstRelayFailure, error) {
+	failures := make([]*models.DstRelayFailure, 0)
+	for _, failure := range dao.failures {
+		if failure.PolyHash == polyHash {
+			failures = append(failures, failure)
+		}
+	}
+	return failures, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsChain(chainIds []uint64, chainId uint64) bool {
+	for _, id := range chainIds {
+		if id == chainId {
+			return true
+		}
+	}
+	return false
+}
+
+func (dao *memoryDao) GetWrapperTransactionsOfHashes(hashes []string) ([]*models.WrapperTransaction, error) {
+	transactions := make([]*models.WrapperTransaction, 0)
+	for _, wrapper := range dao.wrappers {
+		if contains(hashes, wrapper.Hash) {
+			transactions = append(transactions, wrapper)
+		}
+	}
+	return transactions, nil
+}
+
+func (dao *memoryDao) GetSrcTransactionsOfHashes(hashes []string) ([]*models.SrcTransaction, error) {
+	transactions := make([]*models.SrcTransaction, 0)
+	for _, relation := range dao.relations {
+		src := relation.SrcTransaction
+		if contains(hashes, src.Hash) || contains(hashes, src.Key) {
+			transactions = append(transactions, src)
+		}
+	}
+	return transactions, nil
+}
+
+func (dao *memoryDao) GetApiKeys() ([]*models.ApiKey, error) {
+	return dao.apiKeys, nil
+}
+
+func (dao *memoryDao) GetSupplyAudits() ([]*models.NFTSupplyAudit, error) {
+	return dao.audits, nil
+}
+
+func (dao *memoryDao) GetGovernanceEvents(chainId uint64, pageNo, pageSize int) ([]*models.GovernanceEvent, int64, error) {
+	events := make([]*models.GovernanceEvent, 0)
+	for i := len(dao.events) - 1; i >= 0; i-- {
+		if chainId == 0 || dao.events[i].ChainId == chainId {
+			events = append(events, dao.events[i])
+		}
+	}
+	start, end := page(len(events), pageNo, pageSize)
+	return events[start:end], int64(len(events)), nil
+}
+
+func (dao *memoryDao) GetPausedWrappers() (map[uint64]bool, error) {
+	paused := make(map[uint64]bool)
+	for _, event := range dao.events {
+		if event.Contract != basedef.GOVERNANCE_WRAPPER {
+			continue
+		}
+		switch event.Event {
+		case basedef.GOVERNANCE_PAUSED:
+			paused[event.ChainId] = true
+		case basedef.GOVERNANCE_UNPAUSED:
+			delete(paused, event.ChainId)
+		}
+	}
+	return paused, nil
+}
+
+func (dao *memoryDao) GetAccountBalances() ([]*models.AccountBalance, error) {
+	return dao.balances, nil
+}
+
+func (dao *memoryDao) Name() string {
+	return basedef.SERVER_POLY_SWAP
+}
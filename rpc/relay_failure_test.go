@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao/swapdao"
+	"github.com/polynetwork/poly-nft-bridge/dao/crosschaindao"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRelayFailures saves the reverted relays the way the listeners do, a
+// block listened again and a removed poly transaction included.
+func TestRelayFailures(t *testing.T) {
+	dbCfg := &conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:rpc_relay_failures?mode=memory&cache=shared"}
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_SWAP, false, dbCfg)
+	bridge := swapdao.NewSwapDao(dbCfg)
+
+	failure := func(txHash string, height uint64) *models.DstRelayFailure {
+		return &models.DstRelayFailure{TxHash: txHash, ChainId: basedef.BSC_CROSSCHAIN_ID, PolyHash: polyTxHash,
+			SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcKey: srcTxHash, Relayer: dstUser, Reason: "execution reverted",
+			Fee: models.NewBigIntFromInt(21000), Height: height}
+	}
+	assert.NoError(t, dao.UpdateRelayFailures([]*models.DstRelayFailure{failure("cccc", 198)}))
+	assert.NoError(t, dao.UpdateRelayFailures([]*models.DstRelayFailure{failure("bbbb", 197), failure("cccc", 198)}))
+	assert.NoError(t, dao.UpdateRelayFailures(nil))
+
+	failures, err := bridge.GetRelayFailures(polyTxHash)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(failures))
+	assert.Equal(t, []string{"bbbb", "cccc"}, []string{failures[0].TxHash, failures[1].TxHash})
+	assert.Equal(t, "21000", failures[0].Fee.String())
+
+	assert.NoError(t, dao.RemoveEvents(nil, []string{polyTxHash}, nil))
+	failures, err = bridge.GetRelayFailures(polyTxHash)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(failures))
+}
@@ -19,25 +19,44 @@ package rpc
 
 import (
 	"github.com/astaxie/beego"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
 	"github.com/polynetwork/poly-nft-bridge/rpc/controllers"
 )
 
-func init() {
-	ns := beego.NewNamespace("/nft/v1",
-		beego.NSRouter("/", &controllers.InfoController{}, "*:Get"),
-		beego.NSRouter("/assetshow/", &controllers.InfoController{}, "post:Home"),
-		beego.NSRouter("/asset/", &controllers.AssetController{}, "post:Asset"),
-		beego.NSRouter("/assets/", &controllers.AssetController{}, "post:Assets"),
-		beego.NSRouter("/assetbasics/", &controllers.AssetController{}, "post:AssetBasics"),
-		beego.NSRouter("/assetmap/", &controllers.AssetMapController{}, "post:AssetMap"),
-		beego.NSRouter("/assetmapreverse/", &controllers.AssetMapController{}, "post:AssetMapReverse"),
-		beego.NSRouter("/items/", &controllers.ItemController{}, "post:Items"),
-		beego.NSRouter("/getfee/", &controllers.FeeController{}, "post:GetFee"),
-		beego.NSRouter("/transactions/", &controllers.TransactionController{}, "post:Transactions"),
-		beego.NSRouter("/transactionsofaddress/", &controllers.TransactionController{}, "post:TransactionsOfAddress"),
-		beego.NSRouter("/transactionofhash/", &controllers.TransactionController{}, "post:TransactionOfHash"),
-		beego.NSRouter("/transactionsofstate/", &controllers.TransactionController{}, "post:TransactionsOfState"),
+// Register adds the routes of the api to the beego app, the root answers the
+// same info as the namespace.
+func Register(dao bridgedao.BridgeDao, nodes controllers.Nodes) {
+	beego.AddNamespace(NewRouter(dao, nodes))
+	beego.Router("/", &controllers.InfoController{Dao: dao, Nodes: nodes}, "*:Get")
+}
+
+func NewRouter(dao bridgedao.BridgeDao, nodes controllers.Nodes) *beego.Namespace {
+	info := &controllers.InfoController{Dao: dao, Nodes: nodes}
+	asset := &controllers.AssetController{Dao: dao}
+	assetMap := &controllers.AssetMapController{Dao: dao}
+	item := &controllers.ItemController{Nodes: nodes}
+	fee := &controllers.FeeController{Dao: dao}
+	transaction := &controllers.TransactionController{Dao: dao}
+	audit := &controllers.AuditController{Dao: dao}
+	balance := &controllers.BalanceController{Dao: dao}
+	return beego.NewNamespace("/nft/v1",
+		beego.NSRouter("/", info, "*:Get"),
+		beego.NSRouter("/assetshow/", info, "post:Home"),
+		beego.NSRouter("/asset/", asset, "post:Asset"),
+		beego.NSRouter("/assets/", asset, "post:Assets"),
+		beego.NSRouter("/assetbasics/", asset, "post:AssetBasics"),
+		beego.NSRouter("/assetmap/", assetMap, "post:AssetMap"),
+		beego.NSRouter("/assetmapreverse/", assetMap, "post:AssetMapReverse"),
+		beego.NSRouter("/items/", item, "post:Items"),
+		beego.NSRouter("/getfee/", fee, "post:GetFee"),
+		beego.NSRouter("/checkfee/", fee, "post:CheckFee"),
+		beego.NSRouter("/transactions/", transaction, "post:Transactions"),
+		beego.NSRouter("/transactionsofaddress/", transaction, "post:TransactionsOfAddress"),
+		beego.NSRouter("/transactionofhash/", transaction, "post:TransactionOfHash"),
+		beego.NSRouter("/transactionsofstate/", transaction, "post:TransactionsOfState"),
+		beego.NSRouter("/supplyaudits/", audit, "post:SupplyAudits"),
+		beego.NSRouter("/governanceevents/", audit, "post:GovernanceEvents"),
+		beego.NSRouter("/balances/", balance, "post:Balances"),
+		beego.NSRouter("/metrics/", balance, "get:Metrics"),
 	)
-	beego.AddNamespace(ns)
-	beego.Router("/", &controllers.InfoController{}, "*:Get")
 }
@@ -0,0 +1,626 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/astaxie/beego"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao/swapdao"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/dao/migration"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly-nft-bridge/rpc/controllers"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	ethAsset   = "1111111111111111111111111111111111111111"
+	bscAsset   = "2222222222222222222222222222222222222222"
+	feeToken   = "0000000000000000000000000000000000000000"
+	userAddr   = "3333333333333333333333333333333333333333"
+	dstUser    = "4444444444444444444444444444444444444444"
+	srcTxHash  = "aaaa000000000000000000000000000000000000000000000000000000000001"
+	polyTxHash = "bbbb000000000000000000000000000000000000000000000000000000000001"
+	dstTxHash  = "cccc000000000000000000000000000000000000000000000000000000000001"
+)
+
+type testNode struct {
+	owned map[common.Address][]*big.Int
+	all   []*big.Int
+}
+
+func (n *testNode) NFTBalance(asset, owner common.Address) (int, error) {
+	return len(n.owned[owner]), nil
+}
+
+func (n *testNode) GetNFTs(asset, owner common.Address, start, end int) ([]*big.Int, error) {
+	return n.owned[owner][start:end], nil
+}
+
+func (n *testNode) GetAssetNFTs(asset common.Address, start, end int) ([]*big.Int, error) {
+	if end > len(n.all) {
+		end = len(n.all)
+	}
+	return n.all[start:end], nil
+}
+
+func (n *testNode) GetNFTURLs(asset common.Address, tokenIds []*big.Int) (map[uint64]string, error) {
+	urls := make(map[uint64]string)
+	for _, id := range tokenIds {
+		urls[id.Uint64()] = fmt.Sprintf("https://nft.io/%d", id.Uint64())
+	}
+	return urls, nil
+}
+
+func TestMain(m *testing.M) {
+	node := &testNode{
+		owned: map[common.Address][]*big.Int{
+			common.HexToAddress(userAddr): {big.NewInt(1), big.NewInt(2), big.NewInt(3)},
+		},
+		all: []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)},
+	}
+	beego.BConfig.CopyRequestBody = true
+	controllers.SetBaseInfo("prod", 8080)
+	memory := newMemoryDao()
+	stores["memory"] = memory
+	stores["sqlite"] = newSqliteDao(memory)
+	Register(store, controllers.Nodes{basedef.ETHEREUM_CROSSCHAIN_ID: node})
+	os.Exit(m.Run())
+}
+
+// storeSwitch lets every route run against each store behind one router.
+type storeSwitch struct {
+	bridgedao.BridgeDao
+}
+
+var (
+	store  = &storeSwitch{}
+	stores = make(map[string]bridgedao.BridgeDao)
+)
+
+func eachStore(t *testing.T, test func(t *testing.T)) {
+	for _, name := range []string{"memory", "sqlite"} {
+		store.BridgeDao = stores[name]
+		t.Run(name, test)
+	}
+}
+
+// newSqliteDao loads the rows of the memory store into an in-memory sqlite db.
+func newSqliteDao(memory *memoryDao) bridgedao.BridgeDao {
+	dbCfg := &conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:rpc_test?mode=memory&cache=shared"}
+	db := dbopen.MustOpen(dbCfg)
+	db.Logger = logger.Discard
+	if _, err := migration.Up(db, 0); err != nil {
+		panic(err)
+	}
+	create := func(value interface{}) {
+		if err := db.Omit(clause.Associations).Create(value).Error; err != nil {
+			panic(err)
+		}
+	}
+	for _, chain := range memory.chains {
+		create(chain)
+	}
+	create(memory.basics)
+	create(memory.assets)
+	create(memory.assetMaps)
+	for _, token := range memory.tokens {
+		create(token.TokenBasic)
+		create(token)
+	}
+	for _, chainFee := range memory.chainFees {
+		create(chainFee.TokenBasic)
+		create(chainFee)
+	}
+	create(memory.wrappers)
+	create(memory.speedUps)
+	create(memory.failures)
+	for _, relation := range memory.relations {
+		create(relation.SrcTransaction)
+		create(relation.SrcTransaction.SrcTransfer)
+		create(relation.PolyTransaction)
+		create(relation.DstTransaction)
+		create(relation.DstTransaction.DstTransfer)
+	}
+	for _, audit := range memory.audits {
+		create(audit)
+		for _, issue := range audit.Issues {
+			create(issue)
+		}
+	}
+	for _, event := range memory.events {
+		create(event)
+	}
+	for _, balance := range memory.balances {
+		create(balance)
+	}
+	return swapdao.NewSwapDao(dbCfg)
+}
+
+func newMemoryDao() *memoryDao {
+	// rows are loaded without back references, as the gorm preloads do
+	shallow := func(asset *models.NFTAsset) *models.NFTAsset {
+		return &models.NFTAsset{Hash: asset.Hash, ChainId: asset.ChainId, Name: asset.Name, BaseUri: asset.BaseUri,
+			AssetBasicName: asset.AssetBasicName}
+	}
+	basic := &models.NFTAssetBasic{Name: "dog", Time: 1}
+	ethDog := &models.NFTAsset{Hash: ethAsset, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Name: "dog", BaseUri: "https://nft.io/",
+		AssetBasicName: "dog", AssetBasic: basic}
+	bscDog := &models.NFTAsset{Hash: bscAsset, ChainId: basedef.BSC_CROSSCHAIN_ID, Name: "dog", BaseUri: "https://nft.io/",
+		AssetBasicName: "dog", AssetBasic: basic}
+	basics := []*models.NFTAssetBasic{{Name: "dog", Time: 1, Assets: []*models.NFTAsset{shallow(ethDog), shallow(bscDog)}}}
+	ethToBsc := &models.NFTAssetMap{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcAssetHash: ethAsset, SrcAsset: shallow(ethDog),
+		DstChainId: basedef.BSC_CROSSCHAIN_ID, DstAssetHash: bscAsset, DstAsset: shallow(bscDog)}
+	bscToEth := &models.NFTAssetMap{SrcChainId: basedef.BSC_CROSSCHAIN_ID, SrcAssetHash: bscAsset, SrcAsset: shallow(bscDog),
+		DstChainId: basedef.ETHEREUM_CROSSCHAIN_ID, DstAssetHash: ethAsset, DstAsset: shallow(ethDog)}
+	ethDog.AssetMaps = []*models.NFTAssetMap{ethToBsc}
+	bscDog.AssetMaps = []*models.NFTAssetMap{bscToEth}
+
+	eth := &models.TokenBasic{Name: "ETH", Precision: 18, Price: 200000000000}
+	bnb := &models.TokenBasic{Name: "BNB", Precision: 18, Price: 50000000000}
+	token := &models.Token{Hash: feeToken, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Name: "ETH", Precision: 18,
+		TokenBasicName: "ETH", TokenBasic: eth}
+	chainFee := &models.ChainFee{ChainId: basedef.BSC_CROSSCHAIN_ID, TokenBasicName: "BNB", TokenBasic: bnb,
+		MaxFee: models.NewBigIntFromInt(0), MinFee: models.NewBigInt(new(big.Int).Mul(big.NewInt(5000000000000000000), big.NewInt(basedef.FEE_PRECISION))),
+		ProxyFee: models.NewBigIntFromInt(2000000000000000000)}
+
+	finished := &models.WrapperTransaction{Hash: srcTxHash, User: userAddr, SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, BlockHeight: 90,
+		Time: 1000, DstChainId: basedef.BSC_CROSSCHAIN_ID, DstUser: dstUser, FeeTokenHash: feeToken,
+		FeeAmount: models.NewBigIntFromInt(1000000000000000000), TotalFee: models.NewBigIntFromInt(1500000000000000000),
+		Status: basedef.STATE_FINISHED}
+	pending := &models.WrapperTransaction{Hash: "dddd", User: userAddr, SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, BlockHeight: 95,
+		Time: 1100, DstChainId: basedef.BSC_CROSSCHAIN_ID, DstUser: dstUser, FeeTokenHash: feeToken,
+		FeeAmount: models.NewBigIntFromInt(1000000000000000000), TotalFee: models.NewBigIntFromInt(1000000000000000000),
+		Status: basedef.STATE_SOURCE_DONE}
+	src := &models.SrcTransaction{Hash: srcTxHash, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Time: 1000, Fee: models.NewBigIntFromInt(1),
+		Height: 90, User: userAddr, DstChainId: basedef.BSC_CROSSCHAIN_ID, CrossChainArgs: models.CrossChainArgs{Method: "unlock",
+			ArgsAsset: bscAsset, ArgsUser: dstUser, ArgsTokenId: "3", ArgsTokenUri: "https://nft.io/3"}, SrcTransfer: &models.SrcTransfer{TxHash: srcTxHash,
+			ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Time: 1000, Asset: ethAsset, From: userAddr, To: ethAsset,
+			Amount: models.NewBigIntFromInt(3), DstChainId: basedef.BSC_CROSSCHAIN_ID, DstAsset: bscAsset, DstUser: dstUser}}
+	poly := &models.PolyTransaction{Hash: polyTxHash, ChainId: basedef.POLY_CROSSCHAIN_ID, Time: 1010, Fee: models.NewBigIntFromInt(0),
+		Height: 45, SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, SrcHash: srcTxHash, DstChainId: basedef.BSC_CROSSCHAIN_ID}
+	dst := &models.DstTransaction{Hash: dstTxHash, ChainId: basedef.BSC_CROSSCHAIN_ID, Time: 1020, Fee: models.NewBigIntFromInt(1),
+		Height: 199, SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, PolyHash: polyTxHash, DstTransfer: &models.DstTransfer{TxHash: dstTxHash,
+			ChainId: basedef.BSC_CROSSCHAIN_ID, Time: 1020, Asset: bscAsset, From: bscAsset, To: dstUser, Amount: models.NewBigIntFromInt(3)}}
+
+	return &memoryDao{
+		chains: map[uint64]*models.Chain{
+			basedef.ETHEREUM_CROSSCHAIN_ID: {ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Height: 100, BackwardBlockNumber: 12},
+			basedef.BSC_CROSSCHAIN_ID:      {ChainId: basedef.BSC_CROSSCHAIN_ID, Height: 200, BackwardBlockNumber: 15},
+		},
+		basics:    basics,
+		assets:    []*models.NFTAsset{ethDog, bscDog},
+		assetMaps: []*models.NFTAssetMap{ethToBsc, bscToEth},
+		tokens:    []*models.Token{token},
+		chainFees: []*models.ChainFee{chainFee},
+		wrappers:  []*models.WrapperTransaction{finished, pending},
+		speedUps: []*models.WrapperSpeedUp{
+			{TxHash: "eeee", SrcHash: srcTxHash, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, User: userAddr, FeeTokenHash: feeToken,
+				FeeAmount: models.NewBigIntFromInt(500000000000000000), Height: 92, Time: 1005},
+			{TxHash: "ffff", SrcHash: srcTxHash, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, User: userAddr, FeeTokenHash: ethAsset,
+				FeeAmount: models.NewBigIntFromInt(1), Height: 93, Time: 1006},
+		},
+		failures: []*models.DstRelayFailure{
+			{TxHash: "cccc", ChainId: basedef.BSC_CROSSCHAIN_ID, PolyHash: polyTxHash, SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID,
+				SrcKey: srcTxHash, Relayer: dstUser, Reason: "execution reverted: EthCrossChain call business contract failed",
+				Fee: models.NewBigIntFromInt(21000), Height: 197, Time: 1015},
+		},
+		relations: []*models.SrcPolyDstRelation{{
+			SrcHash: srcTxHash, WrapperTransaction: finished, SrcTransaction: src,
+			PolyHash: polyTxHash, PolyTransaction: poly,
+			DstHash: dstTxHash, DstTransaction: dst,
+			ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, AssetHash: ethAsset, Asset: ethDog,
+			FeeTokenHash: feeToken, FeeToken: token,
+		}},
+		audits: []*models.NFTSupplyAudit{
+			{AssetBasicName: "cat", Time: 1100, Chains: 2, Tokens: 3, Issues: []*models.NFTSupplyIssue{}},
+			{AssetBasicName: "dog", Time: 1100, Chains: 2, Tokens: 4, Locked: 2, Double: 1, Issues: []*models.NFTSupplyIssue{
+				{AssetBasicName: "dog", TokenId: "3", Kind: "double", Chains: "2,6"},
+			}},
+		},
+		events: []*models.GovernanceEvent{
+			governanceEvent(basedef.ETHEREUM_CROSSCHAIN_ID, basedef.GOVERNANCE_ECCM, basedef.GOVERNANCE_OWNERSHIP_TRANSFERRED, 70),
+			governanceEvent(basedef.ETHEREUM_CROSSCHAIN_ID, basedef.GOVERNANCE_WRAPPER, basedef.GOVERNANCE_PAUSED, 80),
+			governanceEvent(basedef.ETHEREUM_CROSSCHAIN_ID, basedef.GOVERNANCE_WRAPPER, basedef.GOVERNANCE_UNPAUSED, 85),
+			governanceEvent(basedef.ETHEREUM_CROSSCHAIN_ID, basedef.GOVERNANCE_WRAPPER, basedef.GOVERNANCE_PAUSED, 95),
+			governanceEvent(basedef.BSC_CROSSCHAIN_ID, basedef.GOVERNANCE_WRAPPER, basedef.GOVERNANCE_PAUSED, 150),
+			governanceEvent(basedef.BSC_CROSSCHAIN_ID, basedef.GOVERNANCE_WRAPPER, basedef.GOVERNANCE_UNPAUSED, 160),
+		},
+		balances: []*models.AccountBalance{
+			{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Role: basedef.ACCOUNT_FEE_COLLECTOR, Address: userAddr, Token: feeToken,
+				Name: "ETH", Precision: 18, Balance: models.NewBigIntFromInt(3000000000000000000), Min: models.NewBigIntFromInt(0),
+				Burn: models.NewBigIntFromInt(0), Runway: -1, Time: 1100},
+			{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Role: basedef.ACCOUNT_RELAYER, Address: dstUser, Token: basedef.NATIVE_TOKEN,
+				Name: "native", Precision: 18, Balance: models.NewBigIntFromInt(500000000000000000),
+				Min: models.NewBigIntFromInt(1000000000000000000), Burn: models.NewBigIntFromInt(250000000000000000),
+				Runway: 172800, Low: 1, Time: 1100},
+		},
+	}
+}
+
+func governanceEvent(chainId uint64, contract, event string, height uint64) *models.GovernanceEvent {
+	return &models.GovernanceEvent{TxHash: fmt.Sprintf("%064x", height), ChainId: chainId, Contract: contract,
+		Address: contract, Event: event, Height: height, Time: 1000 + height}
+}
+
+func post(t *testing.T, path string, req interface{}, rsp interface{}) int {
+	body, err := json.Marshal(req)
+	assert.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	beego.BeeApp.Handlers.ServeHTTP(rec, r)
+	if rsp != nil && rec.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), rsp), rec.Body.String())
+	}
+	return rec.Code
+}
+
+func TestInfo(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		for _, path := range []string{"/", "/nft/v1/"} {
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			rec := httptest.NewRecorder()
+			beego.BeeApp.Handlers.ServeHTTP(rec, r)
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+			assert.JSONEq(t, `{"Version":"v1","URL":"http://bridge.poly.network/nft"}`, rec.Body.String(), path)
+		}
+	})
+}
+
+func TestAssetShow(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.HomeRsp)
+		code := post(t, "/nft/v1/assetshow/", &models.HomeReq{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Size: 2}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(1), rsp.TotalCount)
+		assert.Equal(t, ethAsset, rsp.Assets[0].Asset.Hash)
+		assert.Len(t, rsp.Assets[0].Items, 2)
+
+		code = post(t, "/nft/v1/assetshow/", &models.HomeReq{ChainId: basedef.BSC_CROSSCHAIN_ID, Size: 2}, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestAssets(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.NFTAssetsRsp)
+		code := post(t, "/nft/v1/assets/", &models.NFTAssetsReq{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(1), rsp.TotalCount)
+		assert.Equal(t, "dog", rsp.Assets[0].AssetBasic.Name)
+		assert.Equal(t, bscAsset, rsp.Assets[0].AssetMaps[0].DstToken.Hash)
+	})
+}
+
+func TestAsset(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.NFTAsset)
+		code := post(t, "/nft/v1/asset/", &models.NFTAssetReq{ChainId: basedef.BSC_CROSSCHAIN_ID, Hash: bscAsset}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "dog", rsp.AssetBasicName)
+		assert.Len(t, rsp.AssetMaps, 1)
+
+		code = post(t, "/nft/v1/asset/", &models.NFTAssetReq{ChainId: basedef.BSC_CROSSCHAIN_ID, Hash: ethAsset}, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestAssetBasics(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.NFTAssetBasicsRsp)
+		code := post(t, "/nft/v1/assetbasics/", &models.NFTAssetBasicsReq{}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(1), rsp.TotalCount)
+		assert.Len(t, rsp.AssetBasics[0].Assets, 2)
+	})
+}
+
+func TestAssetMap(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.NFTAssetMapsRsp)
+		code := post(t, "/nft/v1/assetmap/", &models.NFTAssetMapReq{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Hash: ethAsset}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(1), rsp.TotalCount)
+		assert.Equal(t, bscAsset, rsp.AssetMaps[0].DstToken.Hash)
+
+		rsp = new(models.NFTAssetMapsRsp)
+		code = post(t, "/nft/v1/assetmapreverse/", &models.NFTAssetMapReq{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Hash: ethAsset}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, bscAsset, rsp.AssetMaps[0].SrcToken.Hash)
+
+		code = post(t, "/nft/v1/assetmap/", &models.NFTAssetMapReq{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Hash: bscAsset}, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+		code = post(t, "/nft/v1/assetmapreverse/", &models.NFTAssetMapReq{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Hash: bscAsset}, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestItems(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.ItemsOfAddressRsp)
+		code := post(t, "/nft/v1/items/", &models.ItemsOfAddressReq{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID,
+			Asset: ethAsset, Address: userAddr, PageSize: 2, PageNo: 1}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 3, rsp.TotalCount)
+		assert.Equal(t, 2, rsp.TotalPage)
+		assert.Equal(t, []*models.Item{{TokenId: 3, Url: "https://nft.io/3"}}, rsp.Items)
+
+		rsp = new(models.ItemsOfAddressRsp)
+		code = post(t, "/nft/v1/items/", &models.ItemsOfAddressReq{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID,
+			Asset: ethAsset, Address: userAddr, PageSize: 2, PageNo: 2}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, rsp.Items)
+
+		code = post(t, "/nft/v1/items/", &models.ItemsOfAddressReq{ChainId: basedef.BSC_CROSSCHAIN_ID, PageSize: 2}, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestGetFee(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.GetFeeRsp)
+		code := post(t, "/nft/v1/getfee/", &models.GetFeeReq{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Hash: feeToken,
+			DstChainId: basedef.BSC_CROSSCHAIN_ID}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, feeToken, rsp.Hash)
+		assert.NotEmpty(t, rsp.UsdtAmount)
+
+		code = post(t, "/nft/v1/getfee/", &models.GetFeeReq{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Hash: feeToken,
+			DstChainId: basedef.ETHEREUM_CROSSCHAIN_ID}, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+		code = post(t, "/nft/v1/getfee/", &models.GetFeeReq{SrcChainId: basedef.BSC_CROSSCHAIN_ID, Hash: feeToken,
+			DstChainId: basedef.BSC_CROSSCHAIN_ID}, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestTransactions(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.WrapperTransactionsRsp)
+		code := post(t, "/nft/v1/transactions/", &models.WrapperTransactionsReq{PageSize: 1, PageNo: 0}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 2, rsp.TotalCount)
+		assert.Equal(t, 2, rsp.TotalPage)
+		assert.Equal(t, srcTxHash, rsp.Transactions[0].Hash)
+		assert.Equal(t, "1000000000000000000", rsp.Transactions[0].FeeAmount)
+	})
+}
+
+func TestTransactionsOfState(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.WrapperTransactionsRsp)
+		code := post(t, "/nft/v1/transactionsofstate/", &models.TransactionsOfStateReq{State: basedef.STATE_SOURCE_DONE, PageSize: 10}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, rsp.TotalCount)
+		assert.Equal(t, "dddd", rsp.Transactions[0].Hash)
+	})
+}
+
+func TestTransactionsOfAddress(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.TransactionsOfAddressRsp)
+		code := post(t, "/nft/v1/transactionsofaddress/", &models.TransactionsOfAddressReq{Addresses: []string{dstUser}, PageSize: 10}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, rsp.TotalCount)
+		tx := rsp.Transactions[0]
+		assert.Equal(t, srcTxHash, tx.Hash)
+		assert.Equal(t, "3", tx.TokenId)
+		assert.Equal(t, "1", tx.FeeAmount)
+		assert.Equal(t, ethAsset, tx.Asset.Hash)
+		assert.Equal(t, []string{srcTxHash, polyTxHash, dstTxHash},
+			[]string{tx.TransactionState[0].Hash, tx.TransactionState[1].Hash, tx.TransactionState[2].Hash})
+
+		rsp = new(models.TransactionsOfAddressRsp)
+		code = post(t, "/nft/v1/transactionsofaddress/", &models.TransactionsOfAddressReq{Addresses: []string{feeToken}, PageSize: 10}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 0, rsp.TotalCount)
+	})
+}
+
+func TestTransactionOfHash(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.TransactionRsp)
+		code := post(t, "/nft/v1/transactionofhash/", &models.TransactionOfHashReq{Hash: srcTxHash}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, dstUser, rsp.DstUser)
+		assert.Equal(t, uint64(10), rsp.TransactionState[0].Blocks)
+		assert.Equal(t, uint64(12), rsp.TransactionState[0].NeedBlocks)
+		assert.Equal(t, uint64(1), rsp.TransactionState[2].Blocks)
+		assert.Equal(t, "3", rsp.Args.TokenId)
+		assert.Equal(t, "https://nft.io/3", rsp.Args.TokenUri)
+		assert.Equal(t, dstUser, rsp.Args.User)
+		assert.Equal(t, 1, len(rsp.RelayFailures))
+		assert.Equal(t, "cccc", rsp.RelayFailures[0].Hash)
+		assert.Equal(t, "execution reverted: EthCrossChain call business contract failed", rsp.RelayFailures[0].Reason)
+		assert.Equal(t, "21000", rsp.RelayFailures[0].Fee)
+		assert.Equal(t, uint64(197), rsp.RelayFailures[0].Height)
+
+		code = post(t, "/nft/v1/transactionofhash/", &models.TransactionOfHashReq{Hash: "eeee"}, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestFeeHistory(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.TransactionRsp)
+		code := post(t, "/nft/v1/transactionofhash/", &models.TransactionOfHashReq{Hash: srcTxHash}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "1", rsp.FeeAmount)
+		assert.Equal(t, "1.5", rsp.TotalFee)
+		assert.Equal(t, 3, len(rsp.FeeHistory))
+		assert.Equal(t, []string{srcTxHash, "eeee", "ffff"},
+			[]string{rsp.FeeHistory[0].Hash, rsp.FeeHistory[1].Hash, rsp.FeeHistory[2].Hash})
+		assert.Equal(t, []string{"1", "1.5", "1.5"},
+			[]string{rsp.FeeHistory[0].TotalFee, rsp.FeeHistory[1].TotalFee, rsp.FeeHistory[2].TotalFee})
+		assert.Equal(t, "0.5", rsp.FeeHistory[1].FeeAmount)
+		assert.Equal(t, uint64(1005), rsp.FeeHistory[1].Time)
+
+		wrappers := new(models.WrapperTransactionsRsp)
+		code = post(t, "/nft/v1/transactions/", &models.WrapperTransactionsReq{PageSize: 10, PageNo: 0}, wrappers)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "1500000000000000000", wrappers.Transactions[0].TotalFee)
+		assert.Equal(t, "1000000000000000000", wrappers.Transactions[1].TotalFee)
+	})
+}
+
+func TestCheckFee(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.CheckFeesRsp)
+		code := post(t, "/nft/v1/checkfee/", &models.CheckFeesReq{Checks: []*models.CheckFeeReq{
+			{Hash: srcTxHash, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID},
+			{Hash: basedef.HexStringReverse(srcTxHash), ChainId: basedef.ETHEREUM_CROSSCHAIN_ID},
+			{Hash: "dddd", ChainId: basedef.ETHEREUM_CROSSCHAIN_ID},
+		}}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(3), rsp.TotalCount)
+		// 1 ETH does not cover 5 BNB, the speed up makes it 1.5 ETH
+		assert.Equal(t, 1, rsp.CheckFees[0].PayState)
+		assert.Equal(t, "3000", rsp.CheckFees[0].Amount)
+		assert.Equal(t, "2500", rsp.CheckFees[0].MinProxyFee)
+		assert.Equal(t, 1, rsp.CheckFees[1].PayState)
+		assert.Equal(t, 0, rsp.CheckFees[2].PayState)
+	})
+}
+
+func TestCheckFeeTooMany(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		checks := make([]*models.CheckFeeReq, controllers.MaxFeeChecks+1)
+		for i := range checks {
+			checks[i] = &models.CheckFeeReq{Hash: srcTxHash, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID}
+		}
+		rsp := new(models.CheckFeesRsp)
+		code := post(t, "/nft/v1/checkfee/", &models.CheckFeesReq{Checks: checks}, rsp)
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code = post(t, "/nft/v1/checkfee/", &models.CheckFeesReq{Checks: checks[:controllers.MaxFeeChecks]}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(controllers.MaxFeeChecks), rsp.TotalCount)
+		assert.Equal(t, 1, rsp.CheckFees[controllers.MaxFeeChecks-1].PayState)
+	})
+}
+
+func TestBadRequest(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/nft/v1/assets/", bytes.NewReader([]byte("{")))
+		rec := httptest.NewRecorder()
+		beego.BeeApp.Handlers.ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestSupplyAudits(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.NFTSupplyAuditsRsp)
+		code := post(t, "/nft/v1/supplyaudits/", &models.NFTSupplyAuditsReq{}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(2), rsp.TotalCount)
+		assert.Equal(t, uint64(1), rsp.Broken)
+		assert.Equal(t, "dog", rsp.Audits[1].Name)
+		assert.Equal(t, []*models.NFTSupplyIssueRsp{{TokenId: "3", Kind: "double", Chains: "2,6"}}, rsp.Audits[1].Issues)
+		assert.Empty(t, rsp.Audits[0].Issues)
+	})
+}
+
+func TestGovernanceEvents(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.GovernanceEventsRsp)
+		code := post(t, "/nft/v1/governanceevents/", &models.GovernanceEventsReq{PageSize: 2}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 6, rsp.TotalCount)
+		assert.Equal(t, 3, rsp.TotalPage)
+		assert.Equal(t, []uint64{160, 150}, []uint64{rsp.Events[0].Height, rsp.Events[1].Height})
+		assert.Equal(t, []uint64{basedef.ETHEREUM_CROSSCHAIN_ID}, rsp.Paused)
+
+		rsp = new(models.GovernanceEventsRsp)
+		code = post(t, "/nft/v1/governanceevents/", &models.GovernanceEventsReq{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, PageSize: 10, PageNo: 0}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 4, rsp.TotalCount)
+		assert.Equal(t, basedef.GOVERNANCE_PAUSED, rsp.Events[0].Event)
+		assert.Equal(t, basedef.GOVERNANCE_OWNERSHIP_TRANSFERRED, rsp.Events[3].Event)
+	})
+}
+
+func TestWrapperPaused(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		fee := new(models.GetFeeRsp)
+		code := post(t, "/nft/v1/getfee/", &models.GetFeeReq{SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Hash: feeToken,
+			DstChainId: basedef.BSC_CROSSCHAIN_ID}, fee)
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, fee.Paused)
+
+		tx := new(models.TransactionRsp)
+		code = post(t, "/nft/v1/transactionofhash/", &models.TransactionOfHashReq{Hash: srcTxHash}, tx)
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, tx.Paused)
+
+		txs := new(models.WrapperTransactionsRsp)
+		code = post(t, "/nft/v1/transactions/", &models.WrapperTransactionsReq{PageSize: 10}, txs)
+		assert.Equal(t, http.StatusOK, code)
+		for _, tx := range txs.Transactions {
+			assert.True(t, tx.Paused)
+		}
+	})
+}
+
+func TestAccountBalances(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		rsp := new(models.AccountBalancesRsp)
+		code := post(t, "/nft/v1/balances/", &models.AccountBalancesReq{}, rsp)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, uint64(2), rsp.TotalCount)
+		assert.Equal(t, uint64(1), rsp.Low)
+		relayer := rsp.Balances[1]
+		assert.Equal(t, basedef.ACCOUNT_RELAYER, relayer.Role)
+		assert.Equal(t, "0.5", relayer.Balance)
+		assert.Equal(t, "1", relayer.Min)
+		assert.Equal(t, "0.25", relayer.BurnPerDay)
+		assert.Equal(t, int64(172800), relayer.Runway)
+		assert.True(t, relayer.Low)
+		assert.Equal(t, "3", rsp.Balances[0].Balance)
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	eachStore(t, func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/nft/v1/metrics/", nil)
+		rec := httptest.NewRecorder()
+		beego.BeeApp.Handlers.ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+		body := rec.Body.String()
+		assert.Contains(t, body, "# TYPE bridge_account_balance gauge\n")
+		assert.Contains(t, body, fmt.Sprintf("bridge_account_balance{chain=\"%d\",role=\"relayer\",address=\"%s\",token=\"%s\",name=\"native\"} 0.5\n",
+			basedef.ETHEREUM_CROSSCHAIN_ID, dstUser, basedef.NATIVE_TOKEN))
+		assert.Contains(t, body, fmt.Sprintf("bridge_account_low{chain=\"%d\",role=\"relayer\",address=\"%s\",token=\"%s\",name=\"native\"} 1\n",
+			basedef.ETHEREUM_CROSSCHAIN_ID, dstUser, basedef.NATIVE_TOKEN))
+	})
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao/swapdao"
+	"github.com/polynetwork/poly-nft-bridge/dao/crosschaindao"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/test/simulation"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSimulatedLock locks a token on a simulated chain, saves what the eth
+// listener finds and reads the transaction back from the api.
+func TestSimulatedLock(t *testing.T) {
+	dbCfg := &conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:rpc_simulation?mode=memory&cache=shared"}
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_SWAP, false, dbCfg)
+	saved := store.BridgeDao
+	store.BridgeDao = swapdao.NewSwapDao(dbCfg)
+	defer func() { store.BridgeDao = saved }()
+
+	user, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	userAddr := crypto.PubkeyToAddress(user.PublicKey)
+	src, err := simulation.NewChain(basedef.ETHEREUM_CROSSCHAIN_ID, userAddr)
+	assert.NoError(t, err)
+	dst, err := simulation.NewChain(basedef.BSC_CROSSCHAIN_ID, userAddr)
+	assert.NoError(t, err)
+	assert.NoError(t, src.Deploy("cat", "CAT"))
+	assert.NoError(t, dst.Deploy("cat", "CAT"))
+	assert.NoError(t, simulation.Bind(src, dst))
+	_, err = src.Mint(userAddr, big.NewInt(7), "https://nft.poly.network/cat/7")
+	assert.NoError(t, err)
+	hash, err := src.Lock(eth_sdk.NewKeySigner(user), dst, userAddr, big.NewInt(7), big.NewInt(1e15))
+	assert.NoError(t, err)
+
+	height, err := src.Height()
+	assert.NoError(t, err)
+	wrappers, srcs, polys, dsts, err := src.Listener().HandleNewBlock(height)
+	assert.NoError(t, err)
+	chain := &models.Chain{ChainId: basedef.ETHEREUM_CROSSCHAIN_ID, Height: height}
+	assert.NoError(t, dao.UpdateEvents(chain, wrappers, srcs, polys, dsts))
+
+	rsp := new(models.TransactionRsp)
+	assert.Equal(t, http.StatusOK, post(t, "/nft/v1/transactionofhash/", &models.TransactionOfHashReq{Hash: hash.String()[2:]}, rsp))
+	assert.Equal(t, basedef.ETHEREUM_CROSSCHAIN_ID, rsp.SrcChainId)
+	assert.Equal(t, basedef.BSC_CROSSCHAIN_ID, rsp.DstChainId)
+	assert.Equal(t, height, rsp.BlockHeight)
+	assert.Equal(t, "7", rsp.TokenId)
+	if assert.NotNil(t, rsp.Args) {
+		assert.Equal(t, "unlock", rsp.Args.Method)
+		assert.Equal(t, "https://nft.poly.network/cat/7", rsp.Args.TokenUri)
+	}
+	// poly and the destination chain have not seen it yet
+	if assert.Equal(t, 3, len(rsp.TransactionState)) {
+		assert.Equal(t, hash.String()[2:], rsp.TransactionState[0].Hash)
+		assert.Equal(t, "", rsp.TransactionState[1].Hash)
+		assert.Equal(t, "", rsp.TransactionState[2].Hash)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/dao/bridgedao/swapdao"
+	"github.com/polynetwork/poly-nft-bridge/dao/crosschaindao"
+	"github.com/polynetwork/poly-nft-bridge/dao/dbopen"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSpeedUps saves the speed ups the way the listeners do, before and after
+// the wrapper transaction they pay for.
+func TestSpeedUps(t *testing.T) {
+	dbCfg := &conf.DBConfig{Driver: dbopen.DriverSqlite, URL: "file:rpc_speed_ups?mode=memory&cache=shared"}
+	dao := crosschaindao.NewCrossChainDao(basedef.SERVER_POLY_SWAP, false, dbCfg)
+	saved := store.BridgeDao
+	store.BridgeDao = swapdao.NewSwapDao(dbCfg)
+	defer func() { store.BridgeDao = saved }()
+
+	speedUp := func(txHash string, index uint64, token string, amount int64) *models.WrapperSpeedUp {
+		return &models.WrapperSpeedUp{TxHash: txHash, LogIndex: index, SrcHash: srcTxHash, ChainId: basedef.ETHEREUM_CROSSCHAIN_ID,
+			User: userAddr, FeeTokenHash: token, FeeAmount: models.NewBigIntFromInt(amount), Height: 91}
+	}
+	totalFee := func() string {
+		rsp := new(models.WrapperTransactionsRsp)
+		assert.Equal(t, http.StatusOK, post(t, "/nft/v1/transactions/", &models.WrapperTransactionsReq{PageSize: 10}, rsp))
+		if len(rsp.Transactions) == 0 {
+			return ""
+		}
+		assert.Equal(t, "100", rsp.Transactions[0].FeeAmount)
+		return rsp.Transactions[0].TotalFee
+	}
+
+	// a speed up seen before the lock is counted when the lock is saved
+	assert.NoError(t, dao.UpdateSpeedUps([]*models.WrapperSpeedUp{speedUp("eeee", 0, feeToken, 10)}))
+	assert.Equal(t, "", totalFee())
+	wrapper := &models.WrapperTransaction{Hash: srcTxHash, User: userAddr, SrcChainId: basedef.ETHEREUM_CROSSCHAIN_ID, BlockHeight: 90,
+		DstChainId: basedef.BSC_CROSSCHAIN_ID, DstUser: dstUser, FeeTokenHash: feeToken, FeeAmount: models.NewBigIntFromInt(100),
+		TotalFee: models.NewBigIntFromInt(100), Status: basedef.STATE_SOURCE_DONE}
+	assert.NoError(t, dao.UpdateEvents(nil, []*models.WrapperTransaction{wrapper}, nil, nil, nil))
+	assert.Equal(t, "110", totalFee())
+
+	// a later one adds to the wrapper transaction, and neither a saved one nor
+	// one paid in another token changes the total
+	assert.NoError(t, dao.UpdateSpeedUps([]*models.WrapperSpeedUp{
+		speedUp("eeee", 0, feeToken, 10),
+		speedUp("ffff", 1, feeToken, 20),
+		speedUp("ffff", 2, ethAsset, 30),
+	}))
+	assert.Equal(t, "130", totalFee())
+
+	// saving the lock again keeps the speed ups
+	assert.NoError(t, dao.UpdateEvents(nil, []*models.WrapperTransaction{wrapper}, nil, nil, nil))
+	assert.Equal(t, "130", totalFee())
+
+	speedUps, err := store.GetWrapperSpeedUps(srcTxHash)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(speedUps))
+}
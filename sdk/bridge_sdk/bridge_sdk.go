@@ -22,7 +22,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -58,8 +60,22 @@ type CheckFeesRsp struct {
 	CheckFees []*CheckFeeRsp `json:"CheckFees"`
 }
 
+// HeaderApiKey is the header the bridge reads the api key from.
+const HeaderApiKey = "X-Api-Key"
+
+// RateLimitError is returned when the bridge answers 429, RetryAfter is zero
+// when it did not tell how long to wait.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %s", e.RetryAfter.String())
+}
+
 type BridgeSdk struct {
-	url string
+	url    string
+	apiKey string
 }
 
 func NewBridgeSdk(url string) *BridgeSdk {
@@ -68,6 +84,42 @@ func NewBridgeSdk(url string) *BridgeSdk {
 	}
 }
 
+// SetApiKey sends the key with every request, a bridge requiring auth
+// rejects the requests without one and limits them per ip otherwise.
+func (sdk *BridgeSdk) SetApiKey(key string) {
+	sdk.apiKey = key
+}
+
+func (sdk *BridgeSdk) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Accepts", "application/json")
+	if sdk.apiKey != "" {
+		req.Header.Set(HeaderApiKey, sdk.apiKey)
+	}
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		resp.Body.Close()
+		return nil, &RateLimitError{RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	}
+	return resp, nil
+}
+
+// retryAfter reads the seconds or the date of a Retry-After header.
+func retryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
 func (sdk *BridgeSdk) CheckFee(checks []*CheckFeeReq) ([]*CheckFeeRsp, error) {
 	checkFeesReq := &CheckFeesReq{Checks: checks}
 	requestJson, err := json.Marshal(checkFeesReq)
@@ -78,11 +130,9 @@ func (sdk *BridgeSdk) CheckFee(checks []*CheckFeeReq) ([]*CheckFeeRsp, error) {
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accepts", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := sdk.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -104,10 +154,7 @@ func (sdk *BridgeSdk) Info() (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	req.Header.Set("Accepts", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := sdk.do(req)
 	if err != nil {
 		return false, err
 	}
@@ -123,3 +170,52 @@ func (sdk *BridgeSdk) Info() (bool, error) {
 	}
 	return true, nil
 }
+
+type TransactionOfHashReq struct {
+	Hash string `json:"Hash"`
+}
+
+// TransactionRsp is the part of the nft bridge transaction the tools follow,
+// State is one of the basedef STATE_ values.
+type TransactionRsp struct {
+	Hash       string `json:"Hash"`
+	SrcChainId uint64 `json:"SrcChainId"`
+	DstChainId uint64 `json:"DstChainId"`
+	TokenId    string `json:"TokenId"`
+	State      uint64 `json:"State"`
+}
+
+// TransactionOfHash asks the nft bridge for the cross chain transaction of a
+// wrapper transaction, the url is the `/nft/v1/` namespace. It returns nil
+// when the bridge has not indexed the transaction yet, and a *RateLimitError
+// when the bridge asks to slow down.
+func (sdk *BridgeSdk) TransactionOfHash(hash string) (*TransactionRsp, error) {
+	requestJson, err := json.Marshal(&TransactionOfHashReq{Hash: strings.TrimPrefix(hash, "0x")})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", sdk.url+"transactionofhash/", strings.NewReader(string(requestJson)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := sdk.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("response status code: %d", resp.StatusCode)
+	}
+	respBody, _ := ioutil.ReadAll(resp.Body)
+	transactionRsp := new(TransactionRsp)
+	err = json.Unmarshal(respBody, transactionRsp)
+	if err != nil {
+		return nil, err
+	}
+	return transactionRsp, nil
+}
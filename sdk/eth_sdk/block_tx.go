@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eth_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlockTransaction is a transaction of a block as the node returns it. Only
+// the fields every transaction type has are read, so the typed transactions
+// which types.Transaction of this go-ethereum can not decode are read too.
+// GasPrice is the price the transaction paid.
+type BlockTransaction struct {
+	Hash     common.Hash
+	From     common.Address
+	To       *common.Address
+	Gas      uint64
+	GasPrice *big.Int
+	Value    *big.Int
+	Data     []byte
+}
+
+type rpcBlockTransaction struct {
+	Hash     common.Hash     `json:"hash"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Input    hexutil.Bytes   `json:"input"`
+}
+
+func (tx *BlockTransaction) UnmarshalJSON(input []byte) error {
+	var dec rpcBlockTransaction
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	tx.Hash, tx.From, tx.To, tx.Gas, tx.Data = dec.Hash, dec.From, dec.To, uint64(dec.Gas), dec.Input
+	tx.GasPrice, tx.Value = new(big.Int), new(big.Int)
+	if dec.GasPrice != nil {
+		tx.GasPrice = dec.GasPrice.ToInt()
+	}
+	if dec.Value != nil {
+		tx.Value = dec.Value.ToInt()
+	}
+	return nil
+}
+
+// NewBlockTransaction reads a decoded transaction, the sender is recovered
+// from its signature.
+func NewBlockTransaction(tx *types.Transaction) (*BlockTransaction, error) {
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.NewEIP155Signer(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockTransaction{
+		Hash:     tx.Hash(),
+		From:     from,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}, nil
+}
+
+// GetBlockTransaction returns the transaction of the hash, read as
+// GetBlockTransactions reads the transactions of a block.
+func (ec *EthereumSdk) GetBlockTransaction(hash common.Hash) (*BlockTransaction, error) {
+	if ec.rpcClient == nil {
+		tx, _, err := ec.rawClient.TransactionByHash(context.Background(), hash)
+		if err != nil {
+			return nil, err
+		}
+		return NewBlockTransaction(tx)
+	}
+	var tx *BlockTransaction
+	err := ec.rpcClient.CallContext(context.Background(), &tx, "eth_getTransactionByHash", hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, ethereum.NotFound
+	}
+	return tx, nil
+}
+
+// GetBlockTransactions returns the transactions of the block without
+// decoding them as types.Transaction.
+func (ec *EthereumSdk) GetBlockTransactions(number uint64) ([]*BlockTransaction, error) {
+	if ec.rpcClient == nil {
+		block, err := ec.rawClient.BlockByNumber(context.Background(), new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, err
+		}
+		txs := make([]*BlockTransaction, 0, len(block.Transactions()))
+		for _, tx := range block.Transactions() {
+			blockTx, err := NewBlockTransaction(tx)
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, blockTx)
+		}
+		return txs, nil
+	}
+	var block *struct {
+		Transactions []*BlockTransaction `json:"transactions"`
+	}
+	err := ec.rpcClient.CallContext(context.Background(), &block, "eth_getBlockByNumber", toBlockNumArg(new(big.Int).SetUint64(number)), true)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+	return block.Transactions, nil
+}
@@ -20,14 +20,16 @@
 package eth_sdk
 
 import (
-	"crypto/ecdsa"
+	"context"
 	"fmt"
 	"math/big"
 	"time"
 
 	log "github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/polynetwork/poly-nft-bridge/go_abi/eccd_abi"
 	"github.com/polynetwork/poly-nft-bridge/go_abi/eccm_abi"
 	"github.com/polynetwork/poly-nft-bridge/go_abi/eccmp_abi"
@@ -35,7 +37,7 @@ import (
 	nftlp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_lock_proxy_abi"
 	nftmapping "github.com/polynetwork/poly-nft-bridge/go_abi/nft_mapping_abi"
 	nftwrap "github.com/polynetwork/poly-nft-bridge/go_abi/nft_wrap_abi"
-	xecdsa "github.com/polynetwork/poly-nft-bridge/utils/ecdsa"
+	polycm "github.com/polynetwork/poly/common"
 )
 
 var (
@@ -44,77 +46,77 @@ var (
 	DefaultGasLimit = 7000000
 )
 
-func (s *EthereumSdk) DeployECCDContract(key *ecdsa.PrivateKey) (common.Address, error) {
-	auth, err := s.makeAuth(key)
+func (s *EthereumSdk) DeployECCDContract(signer Signer) (common.Address, common.Hash, error) {
+	auth, err := s.makeAuth(signer)
 	if err != nil {
-		return EmptyAddress, fmt.Errorf("make auth failed")
+		return EmptyAddress, EmptyHash, fmt.Errorf("make auth failed")
 	}
 	contractAddr, tx, _, err := eccd_abi.DeployEthCrossChainData(auth, s.backend())
 	if err != nil {
-		return EmptyAddress, err
+		return EmptyAddress, EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
-		return EmptyAddress, err
+	if tx, err = s.waitTxConfirm(tx); err != nil {
+		return EmptyAddress, EmptyHash, err
 	}
-	return contractAddr, nil
+	return contractAddr, tx.Hash(), nil
 }
 
 func (s *EthereumSdk) DeployECCMContract(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	eccd common.Address,
 	chainID uint64,
-) (common.Address, error) {
+) (common.Address, common.Hash, error) {
 
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
-		return EmptyAddress, err
+		return EmptyAddress, EmptyHash, err
 	}
 	contractAddress, tx, _, err := eccm_abi.DeployEthCrossChainManager(auth, s.backend(), eccd, chainID)
 	if err != nil {
-		return EmptyAddress, err
+		return EmptyAddress, EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
-		return EmptyAddress, err
+	if tx, err = s.waitTxConfirm(tx); err != nil {
+		return EmptyAddress, EmptyHash, err
 	}
-	return contractAddress, nil
+	return contractAddress, tx.Hash(), nil
 }
 
-func (s *EthereumSdk) DeployECCMPContract(key *ecdsa.PrivateKey, eccmAddress common.Address) (common.Address, error) {
-	auth, err := s.makeAuth(key)
+func (s *EthereumSdk) DeployECCMPContract(signer Signer, eccmAddress common.Address) (common.Address, common.Hash, error) {
+	auth, err := s.makeAuth(signer)
 	if err != nil {
-		return EmptyAddress, err
+		return EmptyAddress, EmptyHash, err
 	}
 	contractAddress, tx, _, err := eccmp_abi.DeployEthCrossChainManagerProxy(auth, s.backend(), eccmAddress)
 	if err != nil {
-		return EmptyAddress, err
+		return EmptyAddress, EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
-		return EmptyAddress, err
+	if tx, err = s.waitTxConfirm(tx); err != nil {
+		return EmptyAddress, EmptyHash, err
 	}
-	return contractAddress, nil
+	return contractAddress, tx.Hash(), nil
 }
 
-func (s *EthereumSdk) DeployNFTLockProxy(key *ecdsa.PrivateKey) (common.Address, error) {
-	auth, err := s.makeAuth(key)
+func (s *EthereumSdk) DeployNFTLockProxy(signer Signer) (common.Address, common.Hash, error) {
+	auth, err := s.makeAuth(signer)
 	if err != nil {
-		return EmptyAddress, err
+		return EmptyAddress, EmptyHash, err
 	}
 	contractAddr, tx, _, err := nftlp.DeployPolyNFTLockProxy(auth, s.backend())
 	if err != nil {
-		return EmptyAddress, err
+		return EmptyAddress, EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
-		return EmptyAddress, err
+	if tx, err = s.waitTxConfirm(tx); err != nil {
+		return EmptyAddress, EmptyHash, err
 	}
-	return contractAddr, nil
+	return contractAddr, tx.Hash(), nil
 }
 
-func (s *EthereumSdk) NFTLockProxySetCCMP(key *ecdsa.PrivateKey, proxyAddr, ccmpAddr common.Address) (common.Hash, error) {
+func (s *EthereumSdk) NFTLockProxySetCCMP(signer Signer, proxyAddr, ccmpAddr common.Address) (common.Hash, error) {
 	proxy, err := nftlp.NewPolyNFTLockProxy(proxyAddr, s.backend())
 	if err != nil {
 		return EmptyHash, err
 	}
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -122,7 +124,7 @@ func (s *EthereumSdk) NFTLockProxySetCCMP(key *ecdsa.PrivateKey, proxyAddr, ccmp
 	if err != nil {
 		return EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
@@ -138,12 +140,12 @@ func (s *EthereumSdk) GetLockProxyNFTCCMP(proxyAddr common.Address) (common.Addr
 }
 
 func (s *EthereumSdk) DeployNFT(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	lockProxy common.Address,
 	name, symbol string,
 ) (common.Address, error) {
 
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyAddress, err
 	}
@@ -151,7 +153,7 @@ func (s *EthereumSdk) DeployNFT(
 	if err != nil {
 		return EmptyAddress, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyAddress, err
 	}
 	nameAfterDeploy, err := inst.Name(nil)
@@ -165,7 +167,7 @@ func (s *EthereumSdk) DeployNFT(
 }
 
 func (s *EthereumSdk) BindNFTAsset(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	lockProxyAddr,
 	fromAssetHash,
 	toAssetHash common.Address,
@@ -177,7 +179,7 @@ func (s *EthereumSdk) BindNFTAsset(
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -185,7 +187,7 @@ func (s *EthereumSdk) BindNFTAsset(
 	if err != nil {
 		return EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
@@ -211,7 +213,7 @@ func (s *EthereumSdk) GetBoundNFTAsset(
 }
 
 func (s *EthereumSdk) BindLockProxy(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	localLockProxy,
 	targetLockProxy common.Address,
 	targetSideChainID uint64,
@@ -221,7 +223,7 @@ func (s *EthereumSdk) BindLockProxy(
 	if err != nil {
 		return EmptyHash, err
 	}
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -230,7 +232,7 @@ func (s *EthereumSdk) BindLockProxy(
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
@@ -254,13 +256,13 @@ func (s *EthereumSdk) GetBoundNFTProxy(
 	return common.BytesToAddress(bz), nil
 }
 
-func (s *EthereumSdk) TransferECCDOwnership(key *ecdsa.PrivateKey, eccd, eccm common.Address) (common.Hash, error) {
+func (s *EthereumSdk) TransferECCDOwnership(signer Signer, eccd, eccm common.Address) (common.Hash, error) {
 
 	eccdContract, err := eccd_abi.NewEthCrossChainData(eccd, s.backend())
 	if err != nil {
 		return EmptyHash, err
 	}
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -268,7 +270,7 @@ func (s *EthereumSdk) TransferECCDOwnership(key *ecdsa.PrivateKey, eccd, eccm co
 	if err != nil {
 		return EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
@@ -283,13 +285,58 @@ func (s *EthereumSdk) GetECCDOwnership(eccdAddr common.Address) (common.Address,
 	return eccd.Owner(nil)
 }
 
-func (s *EthereumSdk) TransferECCMOwnership(key *ecdsa.PrivateKey, eccm, ccmp common.Address) (common.Hash, error) {
+// GetECCDEpoch is the poly epoch the eccd keeps, the poly height it starts at
+// and the book keepers which sign the poly headers of it. There are no keepers
+// before the poly genesis header is synced to the side chain.
+func (s *EthereumSdk) GetECCDEpoch(eccdAddr common.Address) (uint32, []common.Address, error) {
+	eccd, err := eccd_abi.NewEthCrossChainData(eccdAddr, s.backend())
+	if err != nil {
+		return 0, nil, err
+	}
+	height, err := eccd.GetCurEpochStartHeight(nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	raw, err := eccd.GetCurEpochConPubKeyBytes(nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(raw) == 0 {
+		return height, nil, nil
+	}
+	keepers, err := decodeKeepers(raw)
+	if err != nil {
+		return 0, nil, err
+	}
+	return height, keepers, nil
+}
+
+// decodeKeepers reverses ECCUtils.serializeKeepers, a uint64 count followed
+// by the var bytes of every address.
+func decodeKeepers(raw []byte) ([]common.Address, error) {
+	source := polycm.NewZeroCopySource(raw)
+	n, eof := source.NextUint64()
+	if eof {
+		return nil, fmt.Errorf("decode keepers, count eof")
+	}
+	keepers := make([]common.Address, 0)
+	for i := uint64(0); i < n; i++ {
+		addr, eof := source.NextVarBytes()
+		if eof || len(addr) != common.AddressLength {
+			return nil, fmt.Errorf("decode keepers, invalid keeper %d", i)
+		}
+		keepers = append(keepers, common.BytesToAddress(addr))
+	}
+	return keepers, nil
+}
+
+func (s *EthereumSdk) TransferECCMOwnership(signer Signer, eccm, ccmp common.Address) (common.Hash, error) {
 
 	eccmContract, err := eccm_abi.NewEthCrossChainManager(eccm, s.backend())
 	if err != nil {
 		return EmptyHash, err
 	}
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -297,7 +344,7 @@ func (s *EthereumSdk) TransferECCMOwnership(key *ecdsa.PrivateKey, eccm, ccmp co
 	if err != nil {
 		return EmptyHash, fmt.Errorf("TransferECCMOwnership err: %v", err)
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
@@ -311,8 +358,17 @@ func (s *EthereumSdk) GetECCMOwnership(eccmAddr common.Address) (common.Address,
 	return eccm.Owner(nil)
 }
 
+// GetECCMData is the eccd the eccm keeps its data in.
+func (s *EthereumSdk) GetECCMData(eccmAddr common.Address) (common.Address, error) {
+	eccm, err := eccm_abi.NewEthCrossChainManager(eccmAddr, s.backend())
+	if err != nil {
+		return EmptyAddress, err
+	}
+	return eccm.EthCrossChainDataAddress(nil)
+}
+
 func (s *EthereumSdk) TransferCCMPOwnership(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	ccmpAddr, newOwner common.Address,
 ) (common.Hash, error) {
 
@@ -321,7 +377,7 @@ func (s *EthereumSdk) TransferCCMPOwnership(
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -329,7 +385,7 @@ func (s *EthereumSdk) TransferCCMPOwnership(
 	if err != nil {
 		return EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
@@ -343,8 +399,17 @@ func (s *EthereumSdk) GetCCMPOwnership(ccmpAddr common.Address) (common.Address,
 	return ccmp.Owner(nil)
 }
 
+// GetCCMPManager is the eccm the ccmp points to.
+func (s *EthereumSdk) GetCCMPManager(ccmpAddr common.Address) (common.Address, error) {
+	ccmp, err := eccmp_abi.NewEthCrossChainManagerProxy(ccmpAddr, s.backend())
+	if err != nil {
+		return EmptyAddress, err
+	}
+	return ccmp.GetEthCrossChainManager(nil)
+}
+
 func (s *EthereumSdk) TransferNFTProxyOwnership(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	proxyAddr, newOwner common.Address,
 ) (common.Hash, error) {
 
@@ -353,7 +418,7 @@ func (s *EthereumSdk) TransferNFTProxyOwnership(
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -361,7 +426,7 @@ func (s *EthereumSdk) TransferNFTProxyOwnership(
 	if err != nil {
 		return EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
@@ -375,13 +440,13 @@ func (s *EthereumSdk) NFTProxyOwnership(proxyAddr common.Address) (common.Addres
 	return proxy.Owner(nil)
 }
 
-func (s *EthereumSdk) InitGenesisBlock(key *ecdsa.PrivateKey, eccmAddr common.Address, rawHdr, publickeys []byte) (common.Hash, error) {
+func (s *EthereumSdk) InitGenesisBlock(signer Signer, eccmAddr common.Address, rawHdr, publickeys []byte) (common.Hash, error) {
 	eccm, err := eccm_abi.NewEthCrossChainManager(eccmAddr, s.backend())
 	if err != nil {
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -390,32 +455,32 @@ func (s *EthereumSdk) InitGenesisBlock(key *ecdsa.PrivateKey, eccmAddr common.Ad
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
 }
 
-func (s *EthereumSdk) DeployWrapContract(key *ecdsa.PrivateKey, chainId uint64) (common.Address, error) {
-	auth, err := s.makeAuth(key)
+func (s *EthereumSdk) DeployWrapContract(signer Signer, chainId uint64) (common.Address, common.Hash, error) {
+	auth, err := s.makeAuth(signer)
 	if err != nil {
-		return EmptyAddress, err
+		return EmptyAddress, EmptyHash, err
 	}
 	owner := auth.From
 	addr, tx, _, err := nftwrap.DeployPolyNFTWrapper(auth, s.backend(), owner, new(big.Int).SetUint64(chainId))
 	if err != nil {
-		return EmptyAddress, err
+		return EmptyAddress, EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
-		return EmptyAddress, err
+	if tx, err = s.waitTxConfirm(tx); err != nil {
+		return EmptyAddress, EmptyHash, err
 	}
 
-	return addr, nil
+	return addr, tx.Hash(), nil
 }
 
 func (s *EthereumSdk) SetWrapFeeCollector(
-	ownerKey *ecdsa.PrivateKey,
+	signer Signer,
 	wrapAddr, feeCollector common.Address,
 ) (common.Hash, error) {
 
@@ -424,7 +489,7 @@ func (s *EthereumSdk) SetWrapFeeCollector(
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(ownerKey)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -434,15 +499,23 @@ func (s *EthereumSdk) SetWrapFeeCollector(
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 
 	return tx.Hash(), nil
 }
 
+func (s *EthereumSdk) GetWrapFeeCollector(wrapAddr common.Address) (common.Address, error) {
+	wrapper, err := nftwrap.NewPolyNFTWrapper(wrapAddr, s.backend())
+	if err != nil {
+		return EmptyAddress, err
+	}
+	return wrapper.FeeCollector(nil)
+}
+
 func (s *EthereumSdk) SetWrapLockProxy(
-	ownerKey *ecdsa.PrivateKey,
+	signer Signer,
 	wrapAddr, nftLockProxyAddr common.Address,
 ) (common.Hash, error) {
 
@@ -451,7 +524,7 @@ func (s *EthereumSdk) SetWrapLockProxy(
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(ownerKey)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -461,15 +534,40 @@ func (s *EthereumSdk) SetWrapLockProxy(
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 
 	return tx.Hash(), nil
 }
 
-func (s *EthereumSdk) DeployERC20(key *ecdsa.PrivateKey) (common.Address, error) {
-	auth, err := s.makeAuth(key)
+func (s *EthereumSdk) GetWrapLockProxy(wrapAddr common.Address) (common.Address, error) {
+	wrapper, err := nftwrap.NewPolyNFTWrapper(wrapAddr, s.backend())
+	if err != nil {
+		return EmptyAddress, err
+	}
+	return wrapper.LockProxy(nil)
+}
+
+func (s *EthereumSdk) GetWrapPaused(wrapAddr common.Address) (bool, error) {
+	wrapper, err := nftwrap.NewPolyNFTWrapper(wrapAddr, s.backend())
+	if err != nil {
+		return false, err
+	}
+	return wrapper.Paused(nil)
+}
+
+// HasCode tells whether a contract is deployed at the address.
+func (s *EthereumSdk) HasCode(addr common.Address) (bool, error) {
+	code, err := s.backend().CodeAt(context.Background(), addr, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+func (s *EthereumSdk) DeployERC20(signer Signer) (common.Address, error) {
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyAddress, err
 	}
@@ -479,7 +577,7 @@ func (s *EthereumSdk) DeployERC20(key *ecdsa.PrivateKey) (common.Address, error)
 		return EmptyAddress, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyAddress, err
 	}
 
@@ -507,21 +605,29 @@ func (s *EthereumSdk) dumpTx(hash common.Hash) error {
 	return nil
 }
 
-func (s *EthereumSdk) makeAuth(key *ecdsa.PrivateKey) (*bind.TransactOpts, error) {
-	authAddress := xecdsa.Key2address(key)
-	nonce, err := s.NonceAt(authAddress)
+func (s *EthereumSdk) makeAuth(signer Signer) (*bind.TransactOpts, error) {
+	authAddress := signer.Address()
+	gasPrice, err := s.gasPrice()
 	if err != nil {
-		return nil, fmt.Errorf("makeAuth, addr %s, err %v", authAddress.Hex(), err)
+		return nil, fmt.Errorf("makeAuth, get gas price err: %v", err)
 	}
 
-	gasPrice, err := s.SuggestGasPrice()
+	chainId, err := s.chainId()
 	if err != nil {
-		return nil, fmt.Errorf("makeAuth, get suggest gas price err: %v", err)
+		return nil, fmt.Errorf("makeAuth, get chain id err: %v", err)
 	}
 
-	auth := bind.NewKeyedTransactor(key)
-	auth.From = authAddress
-	auth.Nonce = big.NewInt(int64(nonce))
+	auth := &bind.TransactOpts{
+		From: authAddress,
+		Signer: func(_ types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != authAddress {
+				return nil, fmt.Errorf("makeAuth, %s can not sign for %s", authAddress.Hex(), address.Hex())
+			}
+			return s.signTx(signer, chainId, tx)
+		},
+	}
+	// the nonce is taken by the nonce manager when the transaction is signed
+	auth.Nonce = big.NewInt(0)
 	auth.Value = big.NewInt(int64(0))       // in wei
 	auth.GasLimit = uint64(DefaultGasLimit) // in units
 	auth.GasPrice = gasPrice
@@ -529,27 +635,63 @@ func (s *EthereumSdk) makeAuth(key *ecdsa.PrivateKey) (*bind.TransactOpts, error
 	return auth, nil
 }
 
-func (s *EthereumSdk) waitTxConfirm(hash common.Hash) error {
+// waitTxConfirm waits until the transaction or a replacement of it is mined
+// and returns the mined one, also along with the error of its receipt. The
+// transaction is replaced with a higher gas price when it is pending longer
+// than the BumpAfter of the gas strategy.
+func (s *EthereumSdk) waitTxConfirm(tx *types.Transaction) (*types.Transaction, error) {
+	sent := []*types.Transaction{tx}
+	defer func() { s.sent.remove(sent...) }()
+
+	bumpAfter := s.gasStrategy().BumpAfter
+	bumpAt := time.Now().Add(bumpAfter)
 	for {
-		time.Sleep(time.Second * 1)
-		_, ispending, err := s.TransactionByHash(hash)
-		if err != nil {
-			log.Error("failed to call TransactionByHash: %v", err)
-			continue
-		}
-		if ispending == true {
-			continue
-		} else {
+		if mined := s.minedTx(sent); mined != nil {
+			tx = mined
 			break
 		}
+		if bumpAfter > 0 && time.Now().After(bumpAt) {
+			last := sent[len(sent)-1]
+			if replacement, err := s.replaceTx(last); err != nil {
+				log.Error("failed to replace tx %s: %v", last.Hash().Hex(), err)
+			} else if replacement != nil {
+				log.Info("tx %s replaced by %s, gas price %s", last.Hash().Hex(), replacement.Hash().Hex(), replacement.GasPrice().String())
+				sent = append(sent, replacement)
+			}
+			bumpAt = time.Now().Add(bumpAfter)
+		}
+		time.Sleep(txPollInterval)
+	}
+	log.Info("tx %s confirmed", tx.Hash().Hex())
+	if err := s.dumpTx(tx.Hash()); err != nil {
+		return tx, err
 	}
-	log.Info("tx %s confirmed", hash.Hex())
-	if err := s.dumpTx(hash); err != nil {
-		return err
+	return tx, nil
+}
+
+// WaitTxConfirm waits for a transaction sent by the sdk, a replacement with
+// a higher gas price may be the one mined. The hash of the mined transaction
+// is returned with the error when its receipt is missing or failed.
+func (s *EthereumSdk) WaitTxConfirm(tx *types.Transaction) (common.Hash, error) {
+	mined, err := s.waitTxConfirm(tx)
+	return mined.Hash(), err
+}
+
+// minedTx is the one of the transactions sharing a nonce which is mined.
+func (s *EthereumSdk) minedTx(sent []*types.Transaction) *types.Transaction {
+	for i := len(sent) - 1; i >= 0; i-- {
+		_, ispending, err := s.TransactionByHash(sent[i].Hash())
+		if err != nil {
+			if err != ethereum.NotFound {
+				log.Error("failed to call TransactionByHash: %v", err)
+			}
+		} else if !ispending {
+			return sent[i]
+		}
 	}
 	return nil
 }
 
 func (s *EthereumSdk) backend() bind.ContractBackend {
-	return s.rawClient
+	return &managedBackend{Backend: s.rawClient, sdk: s}
 }
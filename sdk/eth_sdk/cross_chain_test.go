@@ -0,0 +1,58 @@
+package eth_sdk
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/polynetwork/poly-nft-bridge/go_abi/eccd_abi"
+	polycm "github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetECCDEpoch(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{owner: {Balance: big.NewInt(1e18)}}, 12000000)
+	s := NewEthereumSdkWithBackend(committer{backend})
+	signer := NewKeySigner(key)
+
+	eccdAddr, _, err := s.DeployECCDContract(signer)
+	assert.NoError(t, err)
+	height, keepers, err := s.GetECCDEpoch(eccdAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), height)
+	assert.Empty(t, keepers)
+
+	expect := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+	sink := polycm.NewZeroCopySink(nil)
+	sink.WriteUint64(uint64(len(expect)))
+	for _, keeper := range expect {
+		sink.WriteVarBytes(keeper.Bytes())
+	}
+	eccd, err := eccd_abi.NewEthCrossChainData(eccdAddr, s.backend())
+	assert.NoError(t, err)
+	auth, err := s.makeAuth(signer)
+	assert.NoError(t, err)
+	tx, err := eccd.PutCurEpochStartHeight(auth, 100)
+	assert.NoError(t, err)
+	_, err = s.waitTxConfirm(tx)
+	assert.NoError(t, err)
+	auth, err = s.makeAuth(signer)
+	assert.NoError(t, err)
+	tx, err = eccd.PutCurEpochConPubKeyBytes(auth, sink.Bytes())
+	assert.NoError(t, err)
+	_, err = s.waitTxConfirm(tx)
+	assert.NoError(t, err)
+
+	height, keepers, err = s.GetECCDEpoch(eccdAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(100), height)
+	assert.Equal(t, expect, keepers)
+
+	_, err = decodeKeepers(sink.Bytes()[:len(sink.Bytes())-1])
+	assert.Error(t, err)
+}
@@ -33,10 +33,25 @@ import (
 	"github.com/polynetwork/poly-nft-bridge/go_abi/usdt_abi"
 )
 
+// Backend is the node which the sdk works on, the ethclient of a node or a
+// simulated backend in the tests.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
 type EthereumSdk struct {
 	rpcClient *rpc.Client
-	rawClient *ethclient.Client
+	rawClient Backend
 	url       string
+
+	nonces *NonceManager
+	gas    *GasStrategy
+	sent   *sentTxs
 }
 
 func NewEthereumSdk(url string) (*EthereumSdk, error) {
@@ -49,14 +64,33 @@ func NewEthereumSdk(url string) (*EthereumSdk, error) {
 		rpcClient: rpcClient,
 		rawClient: rawClient,
 		url:       url,
+		nonces:    NewNonceManager(rawClient),
+		sent:      newSentTxs(),
 	}, nil
 }
 
-func (ec *EthereumSdk) GetClient() *ethclient.Client {
+// NewEthereumSdkWithBackend works on the backend only, the calls which go to
+// the rpc client of a node are made through the backend.
+func NewEthereumSdkWithBackend(backend Backend) *EthereumSdk {
+	return &EthereumSdk{
+		rawClient: backend,
+		nonces:    NewNonceManager(backend),
+		sent:      newSentTxs(),
+	}
+}
+
+func (ec *EthereumSdk) GetClient() Backend {
 	return ec.rawClient
 }
 
 func (ec *EthereumSdk) GetCurrentBlockHeight() (uint64, error) {
+	if ec.rpcClient == nil {
+		header, err := ec.rawClient.HeaderByNumber(context.Background(), nil)
+		if err != nil {
+			return 0, err
+		}
+		return header.Number.Uint64(), nil
+	}
 	var result hexutil.Big
 	err := ec.rpcClient.CallContext(context.Background(), &result, "eth_blockNumber")
 	for err != nil {
@@ -81,6 +115,9 @@ func (ec *EthereumSdk) GetHeaderByNumber(number uint64) (*types.Header, error) {
 	} else {
 		newNumber = big.NewInt(int64(number))
 	}
+	if ec.rpcClient == nil {
+		return ec.rawClient.HeaderByNumber(context.Background(), newNumber)
+	}
 	err := ec.rpcClient.CallContext(context.Background(), &header, "eth_getBlockByNumber", toBlockNumArg(newNumber), false)
 	for err != nil {
 		return nil, err
@@ -108,6 +145,10 @@ func (ec *EthereumSdk) GetTransactionReceipt(hash common.Hash) (*types.Receipt,
 	return receipt, nil
 }
 
+func (ec *EthereumSdk) FilterLogs(query ethereum.FilterQuery) ([]types.Log, error) {
+	return ec.rawClient.FilterLogs(context.Background(), query)
+}
+
 func (ec *EthereumSdk) NonceAt(addr common.Address) (uint64, error) {
 	nonce, err := ec.rawClient.PendingNonceAt(context.Background(), addr)
 	for err != nil {
@@ -117,6 +158,9 @@ func (ec *EthereumSdk) NonceAt(addr common.Address) (uint64, error) {
 }
 
 func (ec *EthereumSdk) SendRawTransaction(tx *types.Transaction) error {
+	if ec.rpcClient == nil {
+		return ec.rawClient.SendTransaction(context.Background(), tx)
+	}
 	data, err := rlp.EncodeToBytes(tx)
 	if err != nil {
 		return err
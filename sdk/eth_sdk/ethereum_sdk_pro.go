@@ -29,7 +29,6 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 type EthereumInfo struct {
@@ -66,6 +65,18 @@ func NewEthereumSdkPro(urls []string, slot uint64, id uint64) *EthereumSdkPro {
 	return pro
 }
 
+// NewEthereumSdkProWithBackend selects the backend only, the simulated chains
+// of the tests are listened through it.
+func NewEthereumSdkProWithBackend(backend Backend, slot uint64, id uint64) *EthereumSdkPro {
+	infos := map[string]*EthereumInfo{
+		"backend": {sdk: NewEthereumSdkWithBackend(backend)},
+	}
+	pro := &EthereumSdkPro{infos: infos, selectionSlot: slot, id: id}
+	pro.selection()
+	go pro.NodeSelection()
+	return pro
+}
+
 func (pro *EthereumSdkPro) NodeSelection() {
 	for {
 		pro.nodeSelection()
@@ -135,7 +146,7 @@ func (pro *EthereumSdkPro) GetLatest() *EthereumInfo {
 	return latestInfo
 }
 
-func (pro *EthereumSdkPro) GetClient() *ethclient.Client {
+func (pro *EthereumSdkPro) GetClient() Backend {
 	info := pro.GetLatest()
 	if info == nil {
 		return nil
@@ -169,6 +180,45 @@ func (pro *EthereumSdkPro) GetHeaderByNumber(number uint64) (*types.Header, erro
 	return nil, fmt.Errorf("all node is not working")
 }
 
+// GetBlockTransactions reads the transactions of the block from the latest
+// node, an error does not mark the node as not working. It is used by the
+// reads which are only a diagnosis.
+func (pro *EthereumSdkPro) GetBlockTransactions(number uint64) ([]*BlockTransaction, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+	return info.sdk.GetBlockTransactions(number)
+}
+
+// GetTransactionReceiptOnce reads the receipt from the latest node as
+// GetBlockTransactions does.
+func (pro *EthereumSdkPro) GetTransactionReceiptOnce(hash common.Hash) (*types.Receipt, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+	return info.sdk.GetTransactionReceipt(hash)
+}
+
+func (pro *EthereumSdkPro) GetBlockTransaction(hash common.Hash) (*BlockTransaction, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		tx, err := info.sdk.GetBlockTransaction(hash)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return tx, nil
+		}
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
 func (pro *EthereumSdkPro) GetTransactionByHash(hash common.Hash) (*types.Transaction, error) {
 	info := pro.GetLatest()
 	if info == nil {
@@ -205,6 +255,24 @@ func (pro *EthereumSdkPro) GetTransactionReceipt(hash common.Hash) (*types.Recei
 	return nil, fmt.Errorf("all node is not working")
 }
 
+func (pro *EthereumSdkPro) FilterLogs(query ethereum.FilterQuery) ([]types.Log, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		result, err := info.sdk.FilterLogs(query)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
 func (pro *EthereumSdkPro) NonceAt(addr common.Address) (uint64, error) {
 	info := pro.GetLatest()
 	if info == nil {
@@ -330,6 +398,85 @@ func (pro *EthereumSdkPro) NFTBalance(asset, owner common.Address) (int, error)
 	return 0, fmt.Errorf("all node is not working")
 }
 
+func (pro *EthereumSdkPro) NativeBalance(owner common.Address) (*big.Int, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		balance, err := info.sdk.GetNativeBalance(owner)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return balance, nil
+		}
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
+func (pro *EthereumSdkPro) ERC20Balance(asset, owner common.Address) (*big.Int, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		balance, err := info.sdk.GetERC20Balance(asset, owner)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return balance, nil
+		}
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
+
+func (pro *EthereumSdkPro) WrapFeeCollector(wrapper common.Address) (common.Address, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return common.Address{}, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		collector, err := info.sdk.GetWrapFeeCollector(wrapper)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return collector, nil
+		}
+	}
+	return common.Address{}, fmt.Errorf("all node is not working")
+}
+
+// ECCDEpoch is the poly epoch kept in the eccd of the eccm.
+func (pro *EthereumSdkPro) ECCDEpoch(eccm common.Address) (uint32, []common.Address, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return 0, nil, fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		eccd, err := info.sdk.GetECCMData(eccm)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+			continue
+		}
+		height, keepers, err := info.sdk.GetECCDEpoch(eccd)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return height, keepers, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("all node is not working")
+}
+
 func (pro *EthereumSdkPro) GetNFTs(asset, owner common.Address, start, end int) ([]*big.Int, error) {
 	info := pro.GetLatest()
 	if info == nil {
@@ -384,6 +531,24 @@ func (pro *EthereumSdkPro) GetNFTURLs(asset common.Address, tokenIds []*big.Int)
 	return nil, fmt.Errorf("all node is not working")
 }
 
+func (pro *EthereumSdkPro) GetNFTInfo(asset common.Address) (string, string, string, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return "", "", "", fmt.Errorf("all node is not working")
+	}
+
+	for info != nil {
+		name, symbol, baseUri, err := info.sdk.GetNFTInfo(asset)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return name, symbol, baseUri, nil
+		}
+	}
+	return "", "", "", fmt.Errorf("all node is not working")
+}
+
 func (pro *EthereumSdkPro) WaitTransactionConfirm(hash common.Hash) bool {
 	num := 0
 	for num < 300 {
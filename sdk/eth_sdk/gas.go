@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eth_sdk
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	log "github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type FeeMode string
+
+const (
+	// FeeModeLegacy prices the transactions with the gas price suggested by
+	// the node.
+	FeeModeLegacy FeeMode = "legacy"
+	// FeeModeBaseFee prices the legacy transactions with the base fee of the
+	// latest block and a priority fee, which is what a london chain charges
+	// them. The go-ethereum used here can not encode dynamic fee transactions.
+	FeeModeBaseFee FeeMode = "basefee"
+
+	// DefaultBumpPercent is the least price raise geth takes for replacing a
+	// transaction in its pool.
+	DefaultBumpPercent = 10
+)
+
+// GasStrategy prices the transactions sent by the sdk and replaces the ones
+// stuck in the pool.
+type GasStrategy struct {
+	Mode FeeMode
+	// MaxGasPrice caps the gas price, including the raised ones, nil for no cap.
+	MaxGasPrice *big.Int
+	// TipCap is the priority fee in basefee mode, nil for the one suggested
+	// by the node.
+	TipCap *big.Int
+	// BumpAfter is how long a transaction is pending before it is replaced
+	// with a higher price, zero never replaces.
+	BumpAfter time.Duration
+	// BumpPercent raises the price of a replacement, DefaultBumpPercent when
+	// zero.
+	BumpPercent uint64
+}
+
+// txPollInterval is how often the sdk looks whether a transaction is mined.
+var txPollInterval = time.Second
+
+func (s *EthereumSdk) SetGasStrategy(strategy *GasStrategy) error {
+	switch strategy.Mode {
+	case "":
+		strategy.Mode = FeeModeLegacy
+	case FeeModeLegacy, FeeModeBaseFee:
+	default:
+		return fmt.Errorf("invalid fee mode %s", strategy.Mode)
+	}
+	s.gas = strategy
+	return nil
+}
+
+func (s *EthereumSdk) gasStrategy() *GasStrategy {
+	if s.gas == nil {
+		return &GasStrategy{Mode: FeeModeLegacy}
+	}
+	return s.gas
+}
+
+// gasPrice is the price of a new transaction, capped by the strategy.
+func (s *EthereumSdk) gasPrice() (*big.Int, error) {
+	strategy := s.gasStrategy()
+	var (
+		price *big.Int
+		err   error
+	)
+	if strategy.Mode == FeeModeBaseFee {
+		if price, err = s.baseFeeGasPrice(strategy.TipCap); err != nil {
+			return nil, err
+		}
+	}
+	if price == nil {
+		if price, err = s.SuggestGasPrice(); err != nil {
+			return nil, err
+		}
+	}
+	if strategy.MaxGasPrice != nil && price.Cmp(strategy.MaxGasPrice) > 0 {
+		price = new(big.Int).Set(strategy.MaxGasPrice)
+	}
+	return price, nil
+}
+
+// baseFeeGasPrice is nil when the node is not london yet.
+func (s *EthereumSdk) baseFeeGasPrice(tipCap *big.Int) (*big.Int, error) {
+	if s.rpcClient == nil {
+		return nil, nil
+	}
+	var head struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := s.rpcClient.CallContext(context.Background(), &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, err
+	}
+	if head.BaseFee == nil {
+		return nil, nil
+	}
+	tip := tipCap
+	if tip == nil {
+		var suggested hexutil.Big
+		if err := s.rpcClient.CallContext(context.Background(), &suggested, "eth_maxPriorityFeePerGas"); err != nil {
+			return nil, err
+		}
+		tip = suggested.ToInt()
+	}
+	return baseFeePrice(head.BaseFee.ToInt(), tip), nil
+}
+
+// baseFeePrice leaves room for the base fee of the next block, which grows
+// 1/8 at most.
+func baseFeePrice(baseFee, tip *big.Int) *big.Int {
+	price := new(big.Int).Mul(baseFee, big.NewInt(9))
+	price.Div(price, big.NewInt(8))
+	return price.Add(price, tip)
+}
+
+// bumpPrice is the price of the replacement of a transaction priced old, nil
+// when the cap does not leave room for a replacement.
+func (g *GasStrategy) bumpPrice(old, current *big.Int) *big.Int {
+	percent := g.BumpPercent
+	if percent == 0 {
+		percent = DefaultBumpPercent
+	}
+	price := new(big.Int).Mul(old, new(big.Int).SetUint64(100+percent))
+	price.Div(price, big.NewInt(100))
+	if price.Cmp(old) <= 0 {
+		price.Add(old, big.NewInt(1))
+	}
+	if current != nil && current.Cmp(price) > 0 {
+		price.Set(current)
+	}
+	if g.MaxGasPrice != nil && price.Cmp(g.MaxGasPrice) > 0 {
+		return nil
+	}
+	return price
+}
+
+// replaceTx sends the transaction again with a higher price, nil when it can
+// not be replaced or one with its nonce is mined already.
+func (s *EthereumSdk) replaceTx(tx *types.Transaction) (*types.Transaction, error) {
+	sent := s.sent.get(tx.Hash())
+	if sent == nil {
+		return nil, nil
+	}
+	current, err := s.gasPrice()
+	if err != nil {
+		return nil, err
+	}
+	price := s.gasStrategy().bumpPrice(tx.GasPrice(), current)
+	if price == nil {
+		log.Warn("tx %s gas price %s can not be raised over the cap", tx.Hash().Hex(), tx.GasPrice().String())
+		return nil, nil
+	}
+	replacement, err := sent.signer.SignTx(withNonce(tx, tx.Nonce(), price), sent.chainId)
+	if err != nil {
+		return nil, err
+	}
+	s.sent.add(replacement, sent.signer, sent.chainId)
+	if err := s.rawClient.SendTransaction(context.Background(), replacement); err != nil {
+		s.sent.remove(replacement)
+		if isNonceTooLow(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return replacement, nil
+}
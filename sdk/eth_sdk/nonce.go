@@ -0,0 +1,214 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eth_sdk
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// NonceSource tells the next nonce of an account counting the transactions
+// in the pool, which is the PendingNonceAt of a node.
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out the nonces of the accounts sending through the sdk,
+// so the transactions of an account can be sent concurrently. The nonce of a
+// transaction which failed to send is handed out again before a new one.
+type NonceManager struct {
+	source   NonceSource
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonce
+}
+
+type accountNonce struct {
+	mu       sync.Mutex
+	next     uint64
+	released []uint64
+}
+
+func NewNonceManager(source NonceSource) *NonceManager {
+	return &NonceManager{
+		source:   source,
+		accounts: make(map[common.Address]*accountNonce),
+	}
+}
+
+func (m *NonceManager) account(addr common.Address) *accountNonce {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	acc, ok := m.accounts[addr]
+	if !ok {
+		acc = new(accountNonce)
+		m.accounts[addr] = acc
+	}
+	return acc
+}
+
+// Next hands out a nonce of the account, the pending nonce of the node is
+// taken when the account sent transactions the manager does not know.
+func (m *NonceManager) Next(addr common.Address) (uint64, error) {
+	acc := m.account(addr)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	pending, err := m.source.PendingNonceAt(context.Background(), addr)
+	if err != nil {
+		return 0, err
+	}
+	for len(acc.released) > 0 {
+		nonce := acc.released[0]
+		acc.released = acc.released[1:]
+		if nonce >= pending {
+			return nonce, nil
+		}
+	}
+	if pending > acc.next {
+		acc.next = pending
+	}
+	nonce := acc.next
+	acc.next++
+	return nonce, nil
+}
+
+// Release gives back the nonce of a transaction which was not sent.
+func (m *NonceManager) Release(addr common.Address, nonce uint64) {
+	acc := m.account(addr)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if nonce+1 == acc.next {
+		acc.next--
+		return
+	}
+	if nonce >= acc.next {
+		return
+	}
+	for _, released := range acc.released {
+		if released == nonce {
+			return
+		}
+	}
+	acc.released = append(acc.released, nonce)
+	sort.Slice(acc.released, func(i, j int) bool { return acc.released[i] < acc.released[j] })
+}
+
+// Reset forgets the nonces of the account, the next one is taken from the
+// node again.
+func (m *NonceManager) Reset(addr common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.accounts, addr)
+}
+
+// isNonceTooLow tells whether the node refused the transaction because its
+// nonce was used by another one.
+func isNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "nonce too low")
+}
+
+// sentTxs keeps who signed the transactions of the sdk until they are mined,
+// a stuck one is signed again when it is replaced.
+type sentTxs struct {
+	mu  sync.Mutex
+	txs map[common.Hash]*sentTx
+}
+
+type sentTx struct {
+	signer  Signer
+	chainId *big.Int
+}
+
+func newSentTxs() *sentTxs {
+	return &sentTxs{txs: make(map[common.Hash]*sentTx)}
+}
+
+func (t *sentTxs) add(tx *types.Transaction, signer Signer, chainId *big.Int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.txs[tx.Hash()] = &sentTx{signer: signer, chainId: chainId}
+}
+
+func (t *sentTxs) get(hash common.Hash) *sentTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.txs[hash]
+}
+
+func (t *sentTxs) remove(txs ...*types.Transaction) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, tx := range txs {
+		delete(t.txs, tx.Hash())
+	}
+}
+
+// signTx signs the transaction with the next nonce of the signer, it is
+// taken as late as possible so a transaction failing before does not leave
+// a gap.
+func (s *EthereumSdk) signTx(signer Signer, chainId *big.Int, tx *types.Transaction) (*types.Transaction, error) {
+	from := signer.Address()
+	nonce, err := s.nonces.Next(from)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := signer.SignTx(withNonce(tx, nonce, tx.GasPrice()), chainId)
+	if err != nil {
+		s.nonces.Release(from, nonce)
+		return nil, err
+	}
+	s.sent.add(signed, signer, chainId)
+	return signed, nil
+}
+
+func withNonce(tx *types.Transaction, nonce uint64, gasPrice *big.Int) *types.Transaction {
+	if tx.To() == nil {
+		return types.NewContractCreation(nonce, tx.Value(), tx.Gas(), gasPrice, tx.Data())
+	}
+	return types.NewTransaction(nonce, *tx.To(), tx.Value(), tx.Gas(), gasPrice, tx.Data())
+}
+
+// managedBackend gives back the nonce of a transaction the node refused.
+type managedBackend struct {
+	Backend
+	sdk *EthereumSdk
+}
+
+func (b *managedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	err := b.Backend.SendTransaction(ctx, tx)
+	if err == nil {
+		return nil
+	}
+	if sent := b.sdk.sent.get(tx.Hash()); sent != nil {
+		b.sdk.sent.remove(tx)
+		from := sent.signer.Address()
+		if isNonceTooLow(err) {
+			b.sdk.nonces.Reset(from)
+		} else {
+			b.sdk.nonces.Release(from, tx.Nonce())
+		}
+	}
+	return err
+}
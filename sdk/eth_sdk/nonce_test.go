@@ -0,0 +1,213 @@
+package eth_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubNonce uint64
+
+func (n *stubNonce) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return uint64(*n), nil
+}
+
+func TestNonceManager(t *testing.T) {
+	pending := stubNonce(3)
+	m := NewNonceManager(&pending)
+	addr := common.HexToAddress("0x5Fb03EB21303D39967a1a119B32DD744a0fA8986")
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		nonces = make(map[uint64]bool)
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := m.Next(addr)
+			assert.NoError(t, err)
+			mu.Lock()
+			nonces[nonce] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	for nonce := uint64(3); nonce < 23; nonce++ {
+		assert.True(t, nonces[nonce], "nonce %d", nonce)
+	}
+
+	// the nonces not sent are handed out first
+	m.Release(addr, 10)
+	m.Release(addr, 7)
+	m.Release(addr, 22)
+	for _, expect := range []uint64{7, 10, 22, 23} {
+		nonce, err := m.Next(addr)
+		assert.NoError(t, err)
+		assert.Equal(t, expect, nonce)
+	}
+
+	// the node is ahead when the account sent elsewhere
+	pending = 30
+	nonce, err := m.Next(addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(30), nonce)
+	m.Release(addr, 12)
+	nonce, err = m.Next(addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(31), nonce)
+
+	pending = 5
+	m.Reset(addr)
+	nonce, err = m.Next(addr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), nonce)
+}
+
+func TestGasStrategy(t *testing.T) {
+	g := &GasStrategy{MaxGasPrice: big.NewInt(150)}
+	assert.Equal(t, int64(110), g.bumpPrice(big.NewInt(100), big.NewInt(90)).Int64())
+	assert.Equal(t, int64(120), g.bumpPrice(big.NewInt(100), big.NewInt(120)).Int64())
+	assert.Equal(t, int64(2), g.bumpPrice(big.NewInt(1), nil).Int64())
+	assert.Nil(t, g.bumpPrice(big.NewInt(140), nil))
+	g.BumpPercent = 50
+	assert.Equal(t, int64(150), g.bumpPrice(big.NewInt(100), nil).Int64())
+
+	assert.Equal(t, int64(90+2), baseFeePrice(big.NewInt(80), big.NewInt(2)).Int64())
+
+	s := NewEthereumSdkWithBackend(backends.NewSimulatedBackend(core.GenesisAlloc{}, 12000000))
+	assert.Error(t, s.SetGasStrategy(&GasStrategy{Mode: "eip1559"}))
+	assert.NoError(t, s.SetGasStrategy(&GasStrategy{Mode: FeeModeBaseFee, MaxGasPrice: big.NewInt(1)}))
+	price, err := s.gasPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), price.Int64())
+}
+
+// txPool keeps the transactions pending until their price is high enough to
+// be mined, a transaction replaces the one with its nonce priced 10% lower.
+type txPool struct {
+	*backends.SimulatedBackend
+	mu       sync.Mutex
+	pending  map[uint64]*types.Transaction
+	minPrice *big.Int
+}
+
+func (p *txPool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if old, ok := p.pending[tx.Nonce()]; ok {
+		least := new(big.Int).Mul(old.GasPrice(), big.NewInt(110))
+		if new(big.Int).Mul(tx.GasPrice(), big.NewInt(100)).Cmp(least) < 0 {
+			return errors.New("replacement transaction underpriced")
+		}
+	}
+	p.pending[tx.Nonce()] = tx
+	return nil
+}
+
+func (p *txPool) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	p.mu.Lock()
+	for _, tx := range p.pending {
+		if tx.Hash() == hash {
+			p.mu.Unlock()
+			return tx, true, nil
+		}
+	}
+	p.mu.Unlock()
+	return p.SimulatedBackend.TransactionByHash(ctx, hash)
+}
+
+func (p *txPool) mine(from common.Address) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	nonce, err := p.SimulatedBackend.PendingNonceAt(context.Background(), from)
+	if err != nil {
+		return err
+	}
+	for tx, ok := p.pending[nonce]; ok && tx.GasPrice().Cmp(p.minPrice) >= 0; tx, ok = p.pending[nonce] {
+		if err := p.SimulatedBackend.SendTransaction(context.Background(), tx); err != nil {
+			return err
+		}
+		delete(p.pending, nonce)
+		nonce++
+	}
+	p.Commit()
+	return nil
+}
+
+func TestConcurrentSendWithFeeBump(t *testing.T) {
+	interval := txPollInterval
+	txPollInterval = 5 * time.Millisecond
+	defer func() { txPollInterval = interval }()
+
+	key, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x5Fb03EB21303D39967a1a119B32DD744a0fA8986")
+	pool := &txPool{
+		SimulatedBackend: backends.NewSimulatedBackend(core.GenesisAlloc{owner: {Balance: big.NewInt(1e18)}}, 12000000),
+		pending:          make(map[uint64]*types.Transaction),
+		// the simulated backend suggests 1, it takes two bumps
+		minPrice: big.NewInt(3),
+	}
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	stop, done := make(chan struct{}), make(chan struct{})
+	defer func() {
+		close(stop)
+		<-done
+	}()
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				assert.NoError(t, pool.mine(owner))
+			}
+		}
+	}()
+
+	s := NewEthereumSdkWithBackend(pool)
+	assert.NoError(t, s.SetGasStrategy(&GasStrategy{BumpAfter: 20 * time.Millisecond, MaxGasPrice: big.NewInt(3)}))
+	signer := NewKeySigner(key)
+	proxy, _, err := s.DeployNFTLockProxy(signer)
+	assert.NoError(t, err)
+	nft, err := s.DeployNFT(signer, proxy, "nft", "NFT")
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(tokenId int64) {
+			defer wg.Done()
+			hash, err := s.MintNFT(signer, nft, to, big.NewInt(tokenId), fmt.Sprintf("uri%d", tokenId))
+			assert.NoError(t, err)
+			tx, pending, err := pool.TransactionByHash(context.Background(), hash)
+			assert.NoError(t, err)
+			assert.False(t, pending)
+			assert.Equal(t, int64(3), tx.GasPrice().Int64())
+		}(int64(i))
+	}
+	wg.Wait()
+	for i := int64(0); i < 8; i++ {
+		tokenOwner, err := s.GetNFTOwner(nft, big.NewInt(i))
+		assert.NoError(t, err)
+		assert.Equal(t, to, tokenOwner)
+	}
+	nonce, err := pool.SimulatedBackend.PendingNonceAt(context.Background(), owner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), nonce)
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Notice: functions in this file only used for deploy_tool and test cases.
+
+package eth_sdk
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// UnsignedTx is a transaction built with the state of a node, it is signed by
+// SignTx on a machine without network and sent by SendSignedTx.
+type UnsignedTx struct {
+	// ChainId is the eip155 chain id of the network, the transaction is signed
+	// without replay protection when it is empty.
+	ChainId  string `json:",omitempty"`
+	From     string
+	To       string `json:",omitempty"` // empty when the transaction creates a contract
+	Nonce    uint64
+	GasPrice string
+	Gas      uint64
+	Value    string
+	Data     string
+}
+
+// BuildDeployTx builds the deployment of bin with the constructor params.
+func (s *EthereumSdk) BuildDeployTx(from common.Address, contractAbi, bin string, params ...interface{}) (*UnsignedTx, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		return nil, err
+	}
+	input, err := parsed.Pack("", params...)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTx(from, nil, append(common.FromHex(bin), input...))
+}
+
+// BuildCallTx builds the call of method on the contract to.
+func (s *EthereumSdk) BuildCallTx(from, to common.Address, contractAbi, method string, params ...interface{}) (*UnsignedTx, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		return nil, err
+	}
+	input, err := parsed.Pack(method, params...)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTx(from, &to, input)
+}
+
+func (s *EthereumSdk) buildTx(from common.Address, to *common.Address, data []byte) (*UnsignedTx, error) {
+	nonce, err := s.NonceAt(from)
+	if err != nil {
+		return nil, fmt.Errorf("buildTx, addr %s, err %v", from.Hex(), err)
+	}
+	gasPrice, err := s.SuggestGasPrice()
+	if err != nil {
+		return nil, fmt.Errorf("buildTx, get suggest gas price err: %v", err)
+	}
+	gas, err := s.EstimateGas(ethereum.CallMsg{From: from, To: to, GasPrice: gasPrice, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("buildTx, estimate gas err: %v", err)
+	}
+
+	tx := &UnsignedTx{
+		From:     strings.ToLower(from.Hex()),
+		Nonce:    nonce,
+		GasPrice: gasPrice.String(),
+		Gas:      gas,
+		Value:    "0",
+		Data:     hexutil.Encode(data),
+	}
+	if to != nil {
+		tx.To = strings.ToLower(to.Hex())
+	}
+	if chainId, err := s.chainId(); err != nil {
+		return nil, fmt.Errorf("buildTx, get chain id err: %v", err)
+	} else if chainId != nil {
+		tx.ChainId = chainId.String()
+	}
+	return tx, nil
+}
+
+// chainId is nil when the backend can not tell the chain id.
+func (s *EthereumSdk) chainId() (*big.Int, error) {
+	backend, ok := s.rawClient.(interface {
+		ChainID(ctx context.Context) (*big.Int, error)
+	})
+	if !ok {
+		return nil, nil
+	}
+	return backend.ChainID(context.Background())
+}
+
+// Contract is the address of the contract created by the transaction.
+func (tx *UnsignedTx) Contract() common.Address {
+	if tx.To != "" {
+		return EmptyAddress
+	}
+	return crypto.CreateAddress(common.HexToAddress(tx.From), tx.Nonce)
+}
+
+// Transaction is the transaction without signature.
+func (tx *UnsignedTx) Transaction() (*types.Transaction, error) {
+	gasPrice, ok := new(big.Int).SetString(tx.GasPrice, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid gas price %s", tx.GasPrice)
+	}
+	value, ok := new(big.Int).SetString(tx.Value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %s", tx.Value)
+	}
+	data, err := hexutil.Decode(tx.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data, err: %v", err)
+	}
+	if tx.To == "" {
+		return types.NewContractCreation(tx.Nonce, value, tx.Gas, gasPrice, data), nil
+	}
+	return types.NewTransaction(tx.Nonce, common.HexToAddress(tx.To), value, tx.Gas, gasPrice, data), nil
+}
+
+// SignTx signs the transaction with the signer of its sender, it never
+// touches the network.
+func SignTx(tx *UnsignedTx, signer Signer) (*types.Transaction, error) {
+	if addr := signer.Address(); addr != common.HexToAddress(tx.From) {
+		return nil, fmt.Errorf("signer of %s can not sign the transaction from %s", addr.Hex(), tx.From)
+	}
+	rawTx, err := tx.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	var chainId *big.Int
+	if tx.ChainId != "" {
+		var ok bool
+		if chainId, ok = new(big.Int).SetString(tx.ChainId, 10); !ok {
+			return nil, fmt.Errorf("invalid chain id %s", tx.ChainId)
+		}
+	}
+	return signer.SignTx(rawTx, chainId)
+}
+
+// SendSignedTx sends a transaction signed by SignTx and waits until it is
+// confirmed.
+func (s *EthereumSdk) SendSignedTx(tx *types.Transaction) error {
+	if err := s.SendRawTransaction(tx); err != nil {
+		return err
+	}
+	_, err := s.waitTxConfirm(tx)
+	return err
+}
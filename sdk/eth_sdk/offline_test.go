@@ -0,0 +1,63 @@
+package eth_sdk
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	nftwrap "github.com/polynetwork/poly-nft-bridge/go_abi/nft_wrap_abi"
+	"github.com/stretchr/testify/assert"
+)
+
+// committer mines the transactions as soon as they are sent.
+type committer struct {
+	*backends.SimulatedBackend
+}
+
+func (b committer) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+func TestOfflineSign(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	other, _ := crypto.GenerateKey()
+	collector := crypto.PubkeyToAddress(other.PublicKey)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{owner: {Balance: big.NewInt(1e18)}}, 12000000)
+	s := NewEthereumSdkWithBackend(committer{backend})
+
+	// the simulated backend does not tell its chain id
+	deploy, err := s.BuildDeployTx(owner, nftwrap.PolyNFTWrapperABI, nftwrap.PolyNFTWrapperBin, owner, big.NewInt(2))
+	assert.NoError(t, err)
+	assert.Equal(t, "", deploy.ChainId)
+	assert.Equal(t, "", deploy.To)
+	_, err = SignTx(deploy, NewKeySigner(other))
+	assert.Error(t, err)
+	signed, err := SignTx(deploy, NewKeySigner(key))
+	assert.NoError(t, err)
+	assert.NoError(t, s.SendSignedTx(signed))
+	wrapper := deploy.Contract()
+	has, err := s.HasCode(wrapper)
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	call, err := s.BuildCallTx(owner, wrapper, nftwrap.PolyNFTWrapperABI, "setFeeCollector", collector)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), call.Nonce)
+	call.ChainId = backend.Blockchain().Config().ChainID.String()
+	signed, err = SignTx(call, NewKeySigner(key))
+	assert.NoError(t, err)
+	assert.True(t, signed.Protected())
+	assert.NoError(t, s.SendSignedTx(signed))
+	feeCollector, err := s.GetWrapFeeCollector(wrapper)
+	assert.NoError(t, err)
+	assert.Equal(t, collector, feeCollector)
+}
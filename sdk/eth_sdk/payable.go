@@ -21,7 +21,6 @@ package eth_sdk
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"math/big"
 	"strings"
 
@@ -32,47 +31,40 @@ import (
 	erc20 "github.com/polynetwork/poly-nft-bridge/go_abi/mintable_erc20_abi"
 	nftmapping "github.com/polynetwork/poly-nft-bridge/go_abi/nft_mapping_abi"
 	nftwrap "github.com/polynetwork/poly-nft-bridge/go_abi/nft_wrap_abi"
-	xecdsa "github.com/polynetwork/poly-nft-bridge/utils/ecdsa"
 	polycm "github.com/polynetwork/poly/common"
 )
 
 var NativeFeeToken = common.HexToAddress("0x0000000000000000000000000000000000000000")
 
 func (s *EthereumSdk) TransferNative(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	to common.Address,
 	amount *big.Int,
 ) (common.Hash, error) {
 
-	from := xecdsa.Key2address(key)
-	nonce, err := s.NonceAt(from)
-	if err != nil {
-		return EmptyHash, err
-	}
-
-	gasPrice, err := s.SuggestGasPrice()
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
 
 	gasLimit, err := s.EstimateGas(ethereum.CallMsg{
-		From: from, To: &to, Gas: 0, GasPrice: gasPrice,
+		From: auth.From, To: &to, Gas: 0, GasPrice: auth.GasPrice,
 		Value: amount, Data: []byte{},
 	})
 	if err != nil {
 		return EmptyHash, err
 	}
 
-	tx := types.NewTransaction(nonce, to, amount, gasLimit, gasPrice, []byte{})
-	signedTx, err := types.SignTx(tx, types.HomesteadSigner{}, key)
+	tx := types.NewTransaction(auth.Nonce.Uint64(), to, amount, gasLimit, auth.GasPrice, []byte{})
+	signedTx, err := auth.Signer(nil, auth.From, tx)
 	if err != nil {
 		return EmptyHash, err
 	}
-	if err := s.SendRawTransaction(signedTx); err != nil {
+	if err := s.backend().SendTransaction(context.Background(), signedTx); err != nil {
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(signedTx.Hash()); err != nil {
+	if signedTx, err = s.waitTxConfirm(signedTx); err != nil {
 		return EmptyHash, err
 	}
 	return signedTx.Hash(), nil
@@ -83,7 +75,7 @@ func (s *EthereumSdk) GetNativeBalance(owner common.Address) (*big.Int, error) {
 }
 
 func (s *EthereumSdk) MintERC20Token(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	asset, to common.Address,
 	amount *big.Int) (common.Hash, error) {
 
@@ -92,7 +84,7 @@ func (s *EthereumSdk) MintERC20Token(
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -102,7 +94,7 @@ func (s *EthereumSdk) MintERC20Token(
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 
@@ -110,7 +102,7 @@ func (s *EthereumSdk) MintERC20Token(
 }
 
 func (s *EthereumSdk) TransferERC20Token(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	asset, to common.Address,
 	amount *big.Int,
 ) (common.Hash, error) {
@@ -120,7 +112,7 @@ func (s *EthereumSdk) TransferERC20Token(
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -129,7 +121,7 @@ func (s *EthereumSdk) TransferERC20Token(
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 
@@ -145,7 +137,7 @@ func (s *EthereumSdk) GetERC20Balance(asset, owner common.Address) (*big.Int, er
 }
 
 func (s *EthereumSdk) ApproveERC20Token(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	asset, spender common.Address,
 	amount *big.Int,
 ) (common.Hash, error) {
@@ -155,7 +147,7 @@ func (s *EthereumSdk) ApproveERC20Token(
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -164,7 +156,7 @@ func (s *EthereumSdk) ApproveERC20Token(
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 
@@ -180,19 +172,19 @@ func (s *EthereumSdk) GetERC20Allowance(asset, owner, spender common.Address) (*
 }
 
 func (s *EthereumSdk) MintNFT(
-	ownerKey *ecdsa.PrivateKey,
+	signer Signer,
 	asset,
 	to common.Address,
 	tokenID *big.Int,
 	uri string,
 ) (common.Hash, error) {
 
-	contract, err := nftmapping.NewCrossChainNFTMapping(asset, s.rawClient)
+	contract, err := nftmapping.NewCrossChainNFTMapping(asset, s.backend())
 	if err != nil {
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(ownerKey)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -202,14 +194,14 @@ func (s *EthereumSdk) MintNFT(
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
 }
 
 func (s *EthereumSdk) NFTSafeTransferFrom(
-	nftOwnerKey *ecdsa.PrivateKey,
+	signer Signer,
 	asset,
 	from,
 	proxy common.Address,
@@ -223,7 +215,7 @@ func (s *EthereumSdk) NFTSafeTransferFrom(
 		return EmptyHash, err
 	}
 
-	auth, err := s.makeAuth(nftOwnerKey)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -233,18 +225,18 @@ func (s *EthereumSdk) NFTSafeTransferFrom(
 		return EmptyHash, err
 	}
 
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
 }
 
-func (s *EthereumSdk) NFTApprove(key *ecdsa.PrivateKey, asset, to common.Address, token *big.Int) (common.Hash, error) {
+func (s *EthereumSdk) NFTApprove(signer Signer, asset, to common.Address, token *big.Int) (common.Hash, error) {
 	cm, err := nftmapping.NewCrossChainNFTMapping(asset, s.backend())
 	if err != nil {
 		return EmptyHash, err
 	}
-	auth, err := s.makeAuth(key)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
 		return EmptyHash, err
 	}
@@ -252,7 +244,26 @@ func (s *EthereumSdk) NFTApprove(key *ecdsa.PrivateKey, asset, to common.Address
 	if err != nil {
 		return EmptyHash, err
 	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
+	if tx, err = s.waitTxConfirm(tx); err != nil {
+		return EmptyHash, err
+	}
+	return tx.Hash(), nil
+}
+
+func (s *EthereumSdk) NFTSetApprovalForAll(signer Signer, asset, operator common.Address, approved bool) (common.Hash, error) {
+	cm, err := nftmapping.NewCrossChainNFTMapping(asset, s.backend())
+	if err != nil {
+		return EmptyHash, err
+	}
+	auth, err := s.makeAuth(signer)
+	if err != nil {
+		return EmptyHash, err
+	}
+	tx, err := cm.SetApprovalForAll(auth, operator, approved)
+	if err != nil {
+		return EmptyHash, err
+	}
+	if tx, err = s.waitTxConfirm(tx); err != nil {
 		return EmptyHash, err
 	}
 	return tx.Hash(), nil
@@ -274,6 +285,22 @@ func (s *EthereumSdk) GetNFTTokenUri(asset common.Address, tokenID *big.Int) (st
 	return cm.TokenURI(nil, tokenID)
 }
 
+func (s *EthereumSdk) GetNFTInfo(asset common.Address) (name, symbol, baseUri string, err error) {
+	cm, err := nftmapping.NewCrossChainNFTMapping(asset, s.backend())
+	if err != nil {
+		return
+	}
+	if name, err = cm.Name(nil); err != nil {
+		return
+	}
+	if symbol, err = cm.Symbol(nil); err != nil {
+		return
+	}
+	// baseURI is optional in erc721
+	baseUri, _ = cm.BaseURI(nil)
+	return
+}
+
 func (s *EthereumSdk) GetNFTApproved(asset common.Address, tokenID *big.Int) (common.Address, error) {
 	cm, err := nftmapping.NewCrossChainNFTMapping(asset, s.backend())
 	if err != nil {
@@ -282,6 +309,14 @@ func (s *EthereumSdk) GetNFTApproved(asset common.Address, tokenID *big.Int) (co
 	return cm.GetApproved(nil, tokenID)
 }
 
+func (s *EthereumSdk) IsNFTApprovedForAll(asset, owner, operator common.Address) (bool, error) {
+	cm, err := nftmapping.NewCrossChainNFTMapping(asset, s.backend())
+	if err != nil {
+		return false, err
+	}
+	return cm.IsApprovedForAll(nil, owner, operator)
+}
+
 func (s *EthereumSdk) GetNFTOwner(asset common.Address, tokenID *big.Int) (common.Address, error) {
 	cm, err := nftmapping.NewCrossChainNFTMapping(asset, s.backend())
 	if err != nil {
@@ -340,8 +375,11 @@ func (s *EthereumSdk) GetOwnerNFTUrls(asset common.Address, tokenIds []*big.Int)
 	return res, nil
 }
 
+// WrapLockWithErc20FeeToken locks the nft and waits until the lock is
+// confirmed. The hash is returned with the confirmation error once the lock
+// is sent, the token may be locked already then.
 func (s *EthereumSdk) WrapLockWithErc20FeeToken(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	wrapAddr,
 	fromAsset,
 	toAddr common.Address,
@@ -352,29 +390,44 @@ func (s *EthereumSdk) WrapLockWithErc20FeeToken(
 	id *big.Int,
 ) (common.Hash, error) {
 
-	wrapper, err := nftwrap.NewPolyNFTWrapper(wrapAddr, s.backend())
+	tx, err := s.SendWrapLockWithErc20FeeToken(signer, wrapAddr, fromAsset, toAddr, toChainId, tokenID, feeToken, feeAmount, id)
 	if err != nil {
 		return EmptyHash, err
 	}
+	return s.WaitTxConfirm(tx)
+}
 
-	auth, err := s.makeAuth(key)
+// SendWrapLockWithErc20FeeToken sends the lock without waiting for it.
+func (s *EthereumSdk) SendWrapLockWithErc20FeeToken(
+	signer Signer,
+	wrapAddr,
+	fromAsset,
+	toAddr common.Address,
+	toChainId uint64,
+	tokenID *big.Int,
+	feeToken common.Address,
+	feeAmount *big.Int,
+	id *big.Int,
+) (*types.Transaction, error) {
+
+	wrapper, err := nftwrap.NewPolyNFTWrapper(wrapAddr, s.backend())
 	if err != nil {
-		return EmptyHash, err
+		return nil, err
 	}
 
-	tx, err := wrapper.Lock(auth, fromAsset, toChainId, toAddr, tokenID, feeToken, feeAmount, id)
+	auth, err := s.makeAuth(signer)
 	if err != nil {
-		return EmptyHash, err
-	}
-	if err := s.waitTxConfirm(tx.Hash()); err != nil {
-		return EmptyHash, err
+		return nil, err
 	}
 
-	return tx.Hash(), nil
+	return wrapper.Lock(auth, fromAsset, toChainId, toAddr, tokenID, feeToken, feeAmount, id)
 }
 
+// WrapLockWithNativeFeeToken locks the nft and waits until the lock is
+// confirmed. The hash is returned with the confirmation error once the lock
+// is sent, the token may be locked already then.
 func (s *EthereumSdk) WrapLockWithNativeFeeToken(
-	key *ecdsa.PrivateKey,
+	signer Signer,
 	wrapAddr,
 	fromAsset,
 	toAddr common.Address,
@@ -384,37 +437,50 @@ func (s *EthereumSdk) WrapLockWithNativeFeeToken(
 	id *big.Int,
 ) (common.Hash, error) {
 
-	auth, err := s.makeAuth(key)
+	tx, err := s.SendWrapLockWithNativeFeeToken(signer, wrapAddr, fromAsset, toAddr, toChainId, tokenID, feeAmount, id)
 	if err != nil {
 		return EmptyHash, err
 	}
+	return s.WaitTxConfirm(tx)
+}
+
+// SendWrapLockWithNativeFeeToken sends the lock without waiting for it.
+func (s *EthereumSdk) SendWrapLockWithNativeFeeToken(
+	signer Signer,
+	wrapAddr,
+	fromAsset,
+	toAddr common.Address,
+	toChainId uint64,
+	tokenID *big.Int,
+	feeAmount *big.Int,
+	id *big.Int,
+) (*types.Transaction, error) {
+
+	auth, err := s.makeAuth(signer)
+	if err != nil {
+		return nil, err
+	}
 
 	contractABI, err := abi.JSON(strings.NewReader(nftwrap.PolyNFTWrapperABI))
 	if err != nil {
-		return EmptyHash, err
+		return nil, err
 	}
 
 	raw, err := contractABI.Pack("lock", fromAsset, toChainId, toAddr, tokenID, NativeFeeToken, feeAmount, id)
 	if err != nil {
-		return EmptyHash, err
+		return nil, err
 	}
 
 	unsignedTx := types.NewTransaction(auth.Nonce.Uint64(), wrapAddr, feeAmount, auth.GasLimit, auth.GasPrice, raw)
-	signedTx, err := types.SignTx(unsignedTx, types.HomesteadSigner{}, key)
+	signedTx, err := auth.Signer(nil, auth.From, unsignedTx)
 	if err != nil {
-		return EmptyHash, err
-	}
-
-	if err := s.rawClient.SendTransaction(context.Background(), signedTx); err != nil {
-		return EmptyHash, err
+		return nil, err
 	}
 
-	hash := signedTx.Hash()
-	if err := s.waitTxConfirm(hash); err != nil {
-		return EmptyHash, err
+	if err := s.backend().SendTransaction(context.Background(), signedTx); err != nil {
+		return nil, err
 	}
-
-	return hash, nil
+	return signedTx, nil
 }
 
 func (s *EthereumSdk) BatchGetTokenUrls(
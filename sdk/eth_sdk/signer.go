@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eth_sdk
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	xecdsa "github.com/polynetwork/poly-nft-bridge/utils/ecdsa"
+)
+
+// Signer signs the transactions of one account for the transacting calls of
+// the sdk, which never see the key behind it.
+type Signer interface {
+	Address() common.Address
+	// SignTx signs with eip155 replay protection when chainId is not nil.
+	SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error)
+}
+
+func txSigner(chainId *big.Int) types.Signer {
+	if chainId == nil {
+		return types.HomesteadSigner{}
+	}
+	return types.NewEIP155Signer(chainId)
+}
+
+// KeySigner signs with a key in memory, loaded from a keystore file or given
+// raw on test networks.
+type KeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+// NewRawKeySigner signs with the hex encoded key, only for test networks.
+func NewRawKeySigner(hexKey string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw key, err: %v", err)
+	}
+	return NewKeySigner(key), nil
+}
+
+// NewEnvSigner signs with the hex encoded key in the environment variable,
+// only for test networks.
+func NewEnvSigner(name string) (*KeySigner, error) {
+	hexKey, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s not set", name)
+	}
+	return NewRawKeySigner(hexKey)
+}
+
+func (s *KeySigner) Address() common.Address {
+	return xecdsa.Key2address(s.key)
+}
+
+func (s *KeySigner) SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, txSigner(chainId), s.key)
+}
+
+// RemoteSigner asks an external signer such as clef to sign by the json rpc
+// account_signTransaction, the key stays with the signer.
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// remoteTxArgs are the args of account_signTransaction.
+type remoteTxArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice hexutil.Big     `json:"gasPrice"`
+	Value    hexutil.Big     `json:"value"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Data     hexutil.Bytes   `json:"data"`
+	ChainId  *hexutil.Big    `json:"chainId,omitempty"`
+}
+
+type remoteSignResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+func NewRemoteSigner(url string, address common.Address) (*RemoteSigner, error) {
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("dial signer %s, err: %v", url, err)
+	}
+	return &RemoteSigner{client: client, address: address}, nil
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx checks the signer signed the transaction it was asked for with the
+// key of the account, nothing else is sent.
+func (s *RemoteSigner) SignTx(tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	args := &remoteTxArgs{
+		From:     s.address,
+		To:       tx.To(),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     tx.Data(),
+	}
+	if chainId != nil {
+		args.ChainId = (*hexutil.Big)(chainId)
+	}
+	result := new(remoteSignResult)
+	if err := s.client.CallContext(context.Background(), result, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("account_signTransaction, err: %v", err)
+	}
+
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(result.Raw, signed); err != nil {
+		return nil, fmt.Errorf("decode signed transaction, err: %v", err)
+	}
+	if signed.Nonce() != tx.Nonce() || signed.Gas() != tx.Gas() || signed.GasPrice().Cmp(tx.GasPrice()) != 0 ||
+		signed.Value().Cmp(tx.Value()) != 0 || !sameTo(signed.To(), tx.To()) || string(signed.Data()) != string(tx.Data()) {
+		return nil, fmt.Errorf("signer returned another transaction %s", signed.Hash().Hex())
+	}
+	if chainId != nil && (!signed.Protected() || signed.ChainId().Cmp(chainId) != 0) {
+		return nil, fmt.Errorf("signer did not sign for chain %s", chainId.String())
+	}
+	var signer types.Signer = types.HomesteadSigner{}
+	if signed.Protected() {
+		signer = types.NewEIP155Signer(signed.ChainId())
+	}
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return nil, err
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("signer signed with %s instead of %s", sender.Hex(), s.address.Hex())
+	}
+	return signed, nil
+}
+
+func sameTo(a, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,108 @@
+package eth_sdk
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSigner serves account_signTransaction like clef, tamper changes the
+// transaction before it is signed.
+type stubSigner struct {
+	key    *ecdsa.PrivateKey
+	tamper func(args *remoteTxArgs)
+}
+
+func (s *stubSigner) SignTransaction(args remoteTxArgs, methodSelector *string) (*remoteSignResult, error) {
+	if s.tamper != nil {
+		s.tamper(&args)
+	}
+	var tx *types.Transaction
+	if args.To == nil {
+		tx = types.NewContractCreation(uint64(args.Nonce), args.Value.ToInt(), uint64(args.Gas), args.GasPrice.ToInt(), args.Data)
+	} else {
+		tx = types.NewTransaction(uint64(args.Nonce), *args.To, args.Value.ToInt(), uint64(args.Gas), args.GasPrice.ToInt(), args.Data)
+	}
+	var chainId *big.Int
+	if args.ChainId != nil {
+		chainId = args.ChainId.ToInt()
+	}
+	signed, err := types.SignTx(tx, txSigner(chainId), s.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteSignResult{Raw: raw}, nil
+}
+
+func newStubSigner(t *testing.T, stub *stubSigner) string {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("account", stub))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return httpServer.URL
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	collector := common.HexToAddress("0x5Fb03EB21303D39967a1a119B32DD744a0fA8986")
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{owner: {Balance: big.NewInt(1e18)}}, 12000000)
+	s := NewEthereumSdkWithBackend(committer{backend})
+	stub := &stubSigner{key: key}
+	signer, err := NewRemoteSigner(newStubSigner(t, stub), owner)
+	assert.NoError(t, err)
+
+	// the sdk sends what the signer signed
+	wrapper, _, err := s.DeployWrapContract(signer, 2)
+	assert.NoError(t, err)
+	_, err = s.SetWrapFeeCollector(signer, wrapper, collector)
+	assert.NoError(t, err)
+	feeCollector, err := s.GetWrapFeeCollector(wrapper)
+	assert.NoError(t, err)
+	assert.Equal(t, collector, feeCollector)
+
+	tx := types.NewTransaction(7, collector, big.NewInt(1), 21000, big.NewInt(1e9), nil)
+	signed, err := signer.SignTx(tx, big.NewInt(1337))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1337), signed.ChainId().Int64())
+
+	// anything but the transaction asked for is refused
+	stub.tamper = func(args *remoteTxArgs) { args.Nonce++ }
+	_, err = signer.SignTx(tx, big.NewInt(1337))
+	assert.Error(t, err)
+	stub.tamper = func(args *remoteTxArgs) { args.ChainId = nil }
+	_, err = signer.SignTx(tx, big.NewInt(1337))
+	assert.Error(t, err)
+	stub.tamper = nil
+	stub.key, _ = crypto.GenerateKey()
+	_, err = signer.SignTx(tx, big.NewInt(1337))
+	assert.Error(t, err)
+}
+
+func TestEnvSigner(t *testing.T) {
+	const name = "ETH_SDK_TEST_SIGNER_KEY"
+	_, err := NewEnvSigner(name)
+	assert.Error(t, err)
+
+	key, _ := crypto.GenerateKey()
+	os.Setenv(name, "0x"+common.Bytes2Hex(crypto.FromECDSA(key)))
+	defer os.Unsetenv(name)
+	signer, err := NewEnvSigner(name)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer.Address())
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package poly_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	ecm "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ontio/ontology-crypto/signature"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/types"
+)
+
+// polyPubKeyLen is the length of a book keeper in the key list an eccm is
+// given, the key type, the curve label and the uncompressed key.
+const polyPubKeyLen = 67
+
+// PolyEpoch is the epoch of poly consensus started by the block with a new
+// chain config. RawHeader, PubKeyList and SigList change the book keepers of
+// an eccm from the previous epoch to it by changeBookKeeper.
+type PolyEpoch struct {
+	Height     uint64
+	Keepers    []ecm.Address
+	RawHeader  []byte
+	PubKeyList []byte
+	SigList    []byte
+}
+
+// GetEpochHeight is the height of the block which starts the epoch the block
+// at height is in.
+func (s *PolySDK) GetEpochHeight(height uint64) (uint64, error) {
+	block, err := s.GetBlockByHeight(height)
+	if err != nil {
+		return 0, err
+	}
+	return BlockEpochHeight(block)
+}
+
+// GetEpoch reads the epoch started by the block at height.
+func (s *PolySDK) GetEpoch(height uint64) (*PolyEpoch, error) {
+	block, err := s.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return BlockEpoch(block)
+}
+
+func (pro *PolySDKPro) GetEpochHeight(height uint64) (uint64, error) {
+	block, err := pro.GetBlockByHeight(height)
+	if err != nil {
+		return 0, err
+	}
+	return BlockEpochHeight(block)
+}
+
+func (pro *PolySDKPro) GetEpoch(height uint64) (*PolyEpoch, error) {
+	block, err := pro.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return BlockEpoch(block)
+}
+
+// BlockEpochHeight is the height of the block which starts the epoch of the
+// block.
+func BlockEpochHeight(block *types.Block) (uint64, error) {
+	height := uint64(block.Header.Height)
+	info := new(vconfig.VbftBlockInfo)
+	if err := json.Unmarshal(block.Header.ConsensusPayload, info); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal consensus payload of block %d, err: %s", height, err)
+	}
+	// the genesis block starts the first epoch without a last config block
+	if info.NewChainConfig != nil || info.LastConfigBlockNum == math.MaxUint32 {
+		return height, nil
+	}
+	return uint64(info.LastConfigBlockNum), nil
+}
+
+// BlockEpoch reads the epoch the block starts, the header of it is signed by
+// the book keepers of the previous epoch.
+func BlockEpoch(block *types.Block) (*PolyEpoch, error) {
+	height := uint64(block.Header.Height)
+	bookkeepers, err := GetBookeeper(block)
+	if err != nil {
+		return nil, fmt.Errorf("block %d does not start an epoch, err: %s", height, err)
+	}
+	pubKeys := AssembleNoCompressBookeeper(bookkeepers)
+	keepers, err := EpochKeepers(pubKeys)
+	if err != nil {
+		return nil, err
+	}
+	sigs := make([]byte, 0)
+	for _, sig := range block.Header.SigData {
+		temp := make([]byte, len(sig))
+		copy(temp, sig)
+		ethSig, err := signature.ConvertToEthCompatible(temp)
+		if err != nil {
+			return nil, fmt.Errorf("convert signature of block %d, err: %s", height, err)
+		}
+		sigs = append(sigs, ethSig...)
+	}
+	return &PolyEpoch{
+		Height:     height,
+		Keepers:    keepers,
+		RawHeader:  block.Header.GetMessage(),
+		PubKeyList: pubKeys,
+		SigList:    sigs,
+	}, nil
+}
+
+// EpochKeepers are the addresses an eccm keeps for the book keepers, the
+// keccak256 of the public key without its prefix, like ECCUtils.verifyPubkey.
+func EpochKeepers(pubKeys []byte) ([]ecm.Address, error) {
+	if len(pubKeys)%polyPubKeyLen != 0 {
+		return nil, fmt.Errorf("invalid book keepers length %d", len(pubKeys))
+	}
+	keepers := make([]ecm.Address, 0, len(pubKeys)/polyPubKeyLen)
+	for i := 0; i < len(pubKeys); i += polyPubKeyLen {
+		keepers = append(keepers, ecm.BytesToAddress(crypto.Keccak256(pubKeys[i+3 : i+polyPubKeyLen])[12:]))
+	}
+	return keepers, nil
+}
@@ -51,3 +51,7 @@ func (sdk *PolySDK) GetSmartContractEventByBlock(height uint64) ([]*common.Smart
 	event, err := sdk.sdk.GetSmartContractEventByBlock(uint32(height))
 	return event, err
 }
+
+func (sdk *PolySDK) GetStorage(contract string, key []byte) ([]byte, error) {
+	return sdk.sdk.GetStorage(contract, key)
+}
@@ -150,3 +150,20 @@ func (pro *PolySDKPro) GetSmartContractEventByBlock(height uint64) ([]*common.Sm
 	}
 	return nil, fmt.Errorf("all node is not working")
 }
+
+func (pro *PolySDKPro) GetStorage(contract string, key []byte) ([]byte, error) {
+	info := pro.GetLatest()
+	if info == nil {
+		return nil, fmt.Errorf("all node is not working")
+	}
+	for info != nil {
+		value, err := info.sdk.GetStorage(contract, key)
+		if err != nil {
+			info.latestHeight = 0
+			info = pro.GetLatest()
+		} else {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("all node is not working")
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package poly_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	ecm "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	scm "github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// SideChainHeader is the header of a side chain kept by the header sync
+// contract of poly.
+type SideChainHeader struct {
+	Number *big.Int
+	Hash   ecm.Hash
+}
+
+// GetSideChain is the side chain registered in poly, nil when it is not.
+func (s *PolySDK) GetSideChain(chainID uint64) (*scm.SideChain, error) {
+	return s.getSideChain(scm.SIDE_CHAIN, chainID)
+}
+
+// GetSideChainApply is the registration of the side chain waiting for the
+// approval of the poly validators, nil when there is none.
+func (s *PolySDK) GetSideChainApply(chainID uint64) (*scm.SideChain, error) {
+	return s.getSideChain(scm.SIDE_CHAIN_APPLY, chainID)
+}
+
+func (s *PolySDK) getSideChain(prefix string, chainID uint64) (*scm.SideChain, error) {
+	raw, err := s.getStorageItem(utils.SideChainManagerContractAddress, []byte(prefix), utils.GetUint64Bytes(chainID))
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	sideChain := new(scm.SideChain)
+	if err := sideChain.Deserialization(common.NewZeroCopySource(raw)); err != nil {
+		return nil, fmt.Errorf("deserialize side chain %d, err: %v", chainID, err)
+	}
+	return sideChain, nil
+}
+
+// GetGenesisHeader is the genesis header of the eth like side chain synced to
+// poly, nil when it is not synced yet.
+func (s *PolySDK) GetGenesisHeader(chainID uint64) (*SideChainHeader, error) {
+	raw, err := s.getStorageItem(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID))
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	// eth keeps the header with its difficulty sum and bsc, heco keep it with
+	// the validators, the header is the `header` field of them all.
+	genesis := struct {
+		Header struct {
+			Number *hexutil.Big
+			Hash   ecm.Hash
+		}
+	}{}
+	if err := json.Unmarshal(raw, &genesis); err != nil {
+		return nil, fmt.Errorf("decode genesis header of side chain %d, err: %v", chainID, err)
+	}
+	if genesis.Header.Number == nil {
+		return nil, fmt.Errorf("genesis header of side chain %d has no number", chainID)
+	}
+	return &SideChainHeader{Number: genesis.Header.Number.ToInt(), Hash: genesis.Header.Hash}, nil
+}
+
+// GetSideChainHeight is the height of the latest header of the side chain
+// synced to poly, false when no header is synced.
+func (s *PolySDK) GetSideChainHeight(chainID uint64) (uint64, bool, error) {
+	raw, err := s.getStorageItem(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID))
+	if err != nil || raw == nil {
+		return 0, false, err
+	}
+	return utils.GetBytesUint64(raw), true, nil
+}
+
+// getStorageItem reads the value a native contract keeps under the key, nil
+// when there is nothing.
+func (s *PolySDK) getStorageItem(contract common.Address, keys ...[]byte) ([]byte, error) {
+	var key []byte
+	for _, k := range keys {
+		key = append(key, k...)
+	}
+	raw, err := s.GetStorage(contract.ToHexString(), key)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return cstates.GetValueFromRawStorageItem(raw)
+}
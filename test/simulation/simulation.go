@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package simulation deploys the bridge contracts on chains simulated in
+// memory, so the listeners, the daos and the api are tested without a node.
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/wrap/eth"
+)
+
+const (
+	gasLimit = 12000000
+	// listenSlot is the node selection slot of the listeners, in seconds
+	listenSlot = 1
+)
+
+// Balance is the native balance of the accounts in the genesis block.
+var Balance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+
+// committer mines a block for each transaction, the sdk waits until its
+// transactions are not pending any more.
+type committer struct {
+	*backends.SimulatedBackend
+}
+
+func (b committer) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+// Chain is a side chain simulated in memory, the contracts are deployed and
+// owned by the admin.
+type Chain struct {
+	ChainId uint64
+	Backend *backends.SimulatedBackend
+	Sdk     *eth_sdk.EthereumSdk
+	Admin   eth_sdk.Signer
+
+	ECCD    common.Address
+	ECCM    common.Address
+	CCMP    common.Address
+	Proxy   common.Address
+	Wrapper common.Address
+	NFT     common.Address
+}
+
+// NewChain starts a chain whose genesis block funds a new admin and the
+// accounts.
+func NewChain(chainId uint64, accounts ...common.Address) (*Chain, error) {
+	admin, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	alloc := core.GenesisAlloc{crypto.PubkeyToAddress(admin.PublicKey): {Balance: Balance}}
+	for _, account := range accounts {
+		alloc[account] = core.GenesisAccount{Balance: Balance}
+	}
+	backend := backends.NewSimulatedBackend(alloc, gasLimit)
+	return &Chain{
+		ChainId: chainId,
+		Backend: backend,
+		Sdk:     eth_sdk.NewEthereumSdkWithBackend(committer{backend}),
+		Admin:   eth_sdk.NewKeySigner(admin),
+	}, nil
+}
+
+// Deploy deploys and wires the contracts in the order of `deploy_tool`, the
+// nft is a mapping contract of the lock proxy. The admin collects the fee.
+func (c *Chain) Deploy(name, symbol string) (err error) {
+	if c.ECCD, _, err = c.Sdk.DeployECCDContract(c.Admin); err != nil {
+		return fmt.Errorf("deploy eccd on chain %d, err: %v", c.ChainId, err)
+	}
+	if c.ECCM, _, err = c.Sdk.DeployECCMContract(c.Admin, c.ECCD, c.ChainId); err != nil {
+		return fmt.Errorf("deploy eccm on chain %d, err: %v", c.ChainId, err)
+	}
+	if c.CCMP, _, err = c.Sdk.DeployECCMPContract(c.Admin, c.ECCM); err != nil {
+		return fmt.Errorf("deploy ccmp on chain %d, err: %v", c.ChainId, err)
+	}
+	if _, err = c.Sdk.TransferECCDOwnership(c.Admin, c.ECCD, c.ECCM); err != nil {
+		return fmt.Errorf("transfer eccd ownership on chain %d, err: %v", c.ChainId, err)
+	}
+	if _, err = c.Sdk.TransferECCMOwnership(c.Admin, c.ECCM, c.CCMP); err != nil {
+		return fmt.Errorf("transfer eccm ownership on chain %d, err: %v", c.ChainId, err)
+	}
+	if c.Proxy, _, err = c.Sdk.DeployNFTLockProxy(c.Admin); err != nil {
+		return fmt.Errorf("deploy nft lock proxy on chain %d, err: %v", c.ChainId, err)
+	}
+	if _, err = c.Sdk.NFTLockProxySetCCMP(c.Admin, c.Proxy, c.CCMP); err != nil {
+		return fmt.Errorf("set ccmp of lock proxy on chain %d, err: %v", c.ChainId, err)
+	}
+	if c.Wrapper, _, err = c.Sdk.DeployWrapContract(c.Admin, c.ChainId); err != nil {
+		return fmt.Errorf("deploy wrapper on chain %d, err: %v", c.ChainId, err)
+	}
+	if _, err = c.Sdk.SetWrapLockProxy(c.Admin, c.Wrapper, c.Proxy); err != nil {
+		return fmt.Errorf("set lock proxy of wrapper on chain %d, err: %v", c.ChainId, err)
+	}
+	if _, err = c.Sdk.SetWrapFeeCollector(c.Admin, c.Wrapper, c.AdminAddress()); err != nil {
+		return fmt.Errorf("set fee collector of wrapper on chain %d, err: %v", c.ChainId, err)
+	}
+	if c.NFT, err = c.Sdk.DeployNFT(c.Admin, c.Proxy, name, symbol); err != nil {
+		return fmt.Errorf("deploy nft on chain %d, err: %v", c.ChainId, err)
+	}
+	return nil
+}
+
+func (c *Chain) AdminAddress() common.Address {
+	return c.Admin.Address()
+}
+
+// Height is the number of the latest block.
+func (c *Chain) Height() (uint64, error) {
+	return c.Sdk.GetCurrentBlockHeight()
+}
+
+// Mint mints a token of the nft to the user.
+func (c *Chain) Mint(to common.Address, tokenId *big.Int, uri string) (common.Hash, error) {
+	return c.Sdk.MintNFT(c.Admin, c.NFT, to, tokenId, uri)
+}
+
+// Lock approves the token to the wrapper and locks it to the user of the
+// destination chain, the fee is paid in the native token.
+func (c *Chain) Lock(user eth_sdk.Signer, dst *Chain, to common.Address, tokenId, fee *big.Int) (common.Hash, error) {
+	if _, err := c.Sdk.NFTApprove(user, c.NFT, c.Wrapper, tokenId); err != nil {
+		return common.Hash{}, fmt.Errorf("approve token %s to wrapper, err: %v", tokenId.String(), err)
+	}
+	return c.Sdk.WrapLockWithNativeFeeToken(user, c.Wrapper, c.NFT, to, dst.ChainId, tokenId, fee, big.NewInt(0))
+}
+
+// ListenConfig is the config of the listener of the chain.
+func (c *Chain) ListenConfig() *conf.ChainListenConfig {
+	return &conf.ChainListenConfig{
+		ChainName:       fmt.Sprintf("simulated%d", c.ChainId),
+		ChainId:         c.ChainId,
+		ListenSlot:      listenSlot,
+		WrapperContract: c.Wrapper.Hex(),
+		ECCMContract:    c.ECCM.Hex(),
+		CCMPContract:    c.CCMP.Hex(),
+		ProxyContract:   c.Proxy.Hex(),
+	}
+}
+
+// Listener listens the chain, it is made after the contracts are deployed,
+// the node selection skips a chain without blocks.
+func (c *Chain) Listener() *eth.EthereumChainListen {
+	sdk := eth_sdk.NewEthereumSdkProWithBackend(committer{c.Backend}, listenSlot, c.ChainId)
+	return eth.NewEthereumChainListenWithSdk(c.ListenConfig(), sdk)
+}
+
+// Bind binds the lock proxies and the nfts of the chains to each other.
+func Bind(a, b *Chain) error {
+	for _, pair := range [][2]*Chain{{a, b}, {b, a}} {
+		src, dst := pair[0], pair[1]
+		if _, err := src.Sdk.BindLockProxy(src.Admin, src.Proxy, dst.Proxy, dst.ChainId); err != nil {
+			return fmt.Errorf("bind lock proxy of chain %d to chain %d, err: %v", src.ChainId, dst.ChainId, err)
+		}
+		if _, err := src.Sdk.BindNFTAsset(src.Admin, src.Proxy, src.NFT, dst.NFT, dst.ChainId); err != nil {
+			return fmt.Errorf("bind nft of chain %d to chain %d, err: %v", src.ChainId, dst.ChainId, err)
+		}
+	}
+	return nil
+}
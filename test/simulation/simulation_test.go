@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package simulation
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock(t *testing.T) {
+	user, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	userAddr := crypto.PubkeyToAddress(user.PublicKey)
+	src, err := NewChain(basedef.ETHEREUM_CROSSCHAIN_ID, userAddr)
+	assert.NoError(t, err)
+	dst, err := NewChain(basedef.BSC_CROSSCHAIN_ID, userAddr)
+	assert.NoError(t, err)
+	assert.NoError(t, src.Deploy("dog", "DOG"))
+	assert.NoError(t, dst.Deploy("dog", "DOG"))
+	assert.NoError(t, Bind(src, dst))
+
+	tokenId := big.NewInt(1287)
+	_, err = src.Mint(userAddr, tokenId, "https://nft.poly.network/dog/1287")
+	assert.NoError(t, err)
+	fee := big.NewInt(1e16)
+	hash, err := src.Lock(eth_sdk.NewKeySigner(user), dst, userAddr, tokenId, fee)
+	assert.NoError(t, err)
+	owner, err := src.Sdk.GetNFTOwner(src.NFT, tokenId)
+	assert.NoError(t, err)
+	assert.Equal(t, src.Proxy, owner)
+
+	height, err := src.Height()
+	assert.NoError(t, err)
+	listener := src.Listener()
+	wrappers, srcs, polys, dsts, err := listener.HandleNewBlock(height)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(polys))
+	assert.Equal(t, 0, len(dsts))
+	if assert.Equal(t, 1, len(wrappers)) && assert.Equal(t, 1, len(srcs)) {
+		txHash := hash.String()[2:]
+		assert.Equal(t, txHash, wrappers[0].Hash)
+		assert.Equal(t, fee.String(), wrappers[0].FeeAmount.String())
+		assert.Equal(t, basedef.BSC_CROSSCHAIN_ID, wrappers[0].DstChainId)
+		assert.Equal(t, txHash, srcs[0].Hash)
+		assert.Equal(t, "1287", srcs[0].SrcTransfer.Amount.String())
+		assert.Equal(t, strings.ToLower(dst.NFT.Hex()[2:]), srcs[0].SrcTransfer.DstAsset)
+		assert.Equal(t, "unlock", srcs[0].Method)
+		assert.Equal(t, "https://nft.poly.network/dog/1287", srcs[0].ArgsTokenUri)
+		assert.Empty(t, srcs[0].ArgsError)
+	}
+
+	failures, err := listener.HandleRelayFailures(height)
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.DstRelayFailure{}, failures)
+}
+
+func TestListenDeployAndBind(t *testing.T) {
+	src, err := NewChain(basedef.ETHEREUM_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	dst, err := NewChain(basedef.BSC_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	assert.NoError(t, src.Deploy("dog", "DOG"))
+	assert.NoError(t, dst.Deploy("dog", "DOG"))
+	assert.NoError(t, Bind(src, dst))
+
+	height, err := src.Height()
+	assert.NoError(t, err)
+	listener := src.Listener()
+	governance := make(map[string]int)
+	assetBinds := make([]*models.AssetBindEvent, 0)
+	proxyBinds := make([]*models.ProxyBindEvent, 0)
+	for h := uint64(1); h <= height; h++ {
+		_, _, _, _, err := listener.HandleNewBlock(h)
+		assert.NoError(t, err)
+		events, err := listener.HandleGovernanceEvents(h)
+		assert.NoError(t, err)
+		for _, event := range events {
+			assert.Equal(t, h, event.Height)
+			assert.NotZero(t, event.Time)
+			governance[event.Contract+" "+event.Event]++
+		}
+		assets, proxies, err := listener.HandleAssetBinds(h)
+		assert.NoError(t, err)
+		assetBinds = append(assetBinds, assets...)
+		proxyBinds = append(proxyBinds, proxies...)
+		speedUps, err := listener.HandleSpeedUps(h)
+		assert.NoError(t, err)
+		assert.Empty(t, speedUps)
+	}
+
+	// the owners are set on deploy, the eccm passes to the ccmp and the wrapper
+	// constructor hands it to the owner once more
+	assert.Equal(t, map[string]int{
+		basedef.GOVERNANCE_ECCM + " " + basedef.GOVERNANCE_OWNERSHIP_TRANSFERRED:    2,
+		basedef.GOVERNANCE_CCMP + " " + basedef.GOVERNANCE_OWNERSHIP_TRANSFERRED:    1,
+		basedef.GOVERNANCE_PROXY + " " + basedef.GOVERNANCE_OWNERSHIP_TRANSFERRED:   1,
+		basedef.GOVERNANCE_PROXY + " " + basedef.GOVERNANCE_SET_MANAGER_PROXY:       1,
+		basedef.GOVERNANCE_PROXY + " " + basedef.GOVERNANCE_BIND_PROXY:              1,
+		basedef.GOVERNANCE_WRAPPER + " " + basedef.GOVERNANCE_OWNERSHIP_TRANSFERRED: 2,
+	}, governance)
+	if assert.Equal(t, 1, len(assetBinds)) {
+		assert.Equal(t, strings.ToLower(src.NFT.Hex()[2:]), assetBinds[0].AssetHash)
+		assert.Equal(t, strings.ToLower(dst.NFT.Hex()[2:]), assetBinds[0].ToAssetHash)
+		assert.Equal(t, "DOG", assetBinds[0].Symbol)
+	}
+	if assert.Equal(t, 1, len(proxyBinds)) {
+		assert.Equal(t, strings.ToLower(dst.Proxy.Hex()[2:]), proxyBinds[0].ProxyHash)
+	}
+}
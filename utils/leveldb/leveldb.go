@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/goleveldb/leveldb"
+	"github.com/btcsuite/goleveldb/leveldb/util"
 )
 
 type LevelDBImpl struct {
@@ -34,3 +35,21 @@ func (d *LevelDBImpl) Set(k, v []byte) error {
 func (d *LevelDBImpl) Get(k []byte) ([]byte, error) {
 	return d.db.Get(k, nil)
 }
+
+func (d *LevelDBImpl) Delete(k []byte) error {
+	return d.db.Delete(k, nil)
+}
+
+// DeletePrefix deletes all the keys starting with the prefix.
+func (d *LevelDBImpl) DeletePrefix(prefix []byte) error {
+	iter := d.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Delete(iter.Key())
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return d.db.Write(batch, nil)
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package param decodes the cross chain param which the ECCM commits to poly,
+// and the args of the nft lock proxy carried in it.
+package param
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly/common"
+	mcom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+)
+
+// NFTArgs are the args of the nft lock proxy, the asset and the user are
+// addresses of the target chain.
+type NFTArgs struct {
+	ToAsset  []byte
+	ToUser   []byte
+	TokenId  *big.Int
+	TokenUri string
+}
+
+// DecodeTxParam decodes the `Rawdata` of the ECCM CrossChainEvent.
+func DecodeTxParam(raw []byte) (*mcom.MakeTxParam, error) {
+	param := new(mcom.MakeTxParam)
+	source := common.NewZeroCopySource(raw)
+	if err := param.Deserialization(source); err != nil {
+		return nil, err
+	}
+	if source.Len() != 0 {
+		return nil, fmt.Errorf("MakeTxParam has %d bytes left", source.Len())
+	}
+	return param, nil
+}
+
+// DecodeMerkleValue decodes the request which poly keeps for a makeProof.
+func DecodeMerkleValue(raw []byte) (*mcom.ToMerkleValue, error) {
+	value := new(mcom.ToMerkleValue)
+	source := common.NewZeroCopySource(raw)
+	if err := value.Deserialization(source); err != nil {
+		return nil, err
+	}
+	if source.Len() != 0 {
+		return nil, fmt.Errorf("ToMerkleValue has %d bytes left", source.Len())
+	}
+	return value, nil
+}
+
+// DecodeMerkleProof decodes the request at the leaf of a poly merkle proof, the
+// `proof` a relayer passes to verifyHeaderAndExecuteTx. The path to the root
+// follows the leaf and is left alone.
+func DecodeMerkleProof(proof []byte) (*mcom.ToMerkleValue, error) {
+	source := common.NewZeroCopySource(proof)
+	raw, eof := source.NextVarBytes()
+	if eof {
+		return nil, fmt.Errorf("merkle proof has no value")
+	}
+	return DecodeMerkleValue(raw)
+}
+
+// DecodeNFTArgs decodes the args which the nft lock proxy serializes, the
+// token id is written as a little endian uint255.
+func DecodeNFTArgs(raw []byte) (*NFTArgs, error) {
+	source := common.NewZeroCopySource(raw)
+	toAsset, eof := source.NextVarBytes()
+	if eof {
+		return nil, fmt.Errorf("NFTArgs deserialize toAsset error")
+	}
+	toUser, eof := source.NextVarBytes()
+	if eof {
+		return nil, fmt.Errorf("NFTArgs deserialize toUser error")
+	}
+	tokenId, eof := source.NextHash()
+	if eof {
+		return nil, fmt.Errorf("NFTArgs deserialize tokenId error")
+	}
+	tokenUri, eof := source.NextVarBytes()
+	if eof {
+		return nil, fmt.Errorf("NFTArgs deserialize tokenUri error")
+	}
+	if source.Len() != 0 {
+		return nil, fmt.Errorf("NFTArgs has %d bytes left", source.Len())
+	}
+	return &NFTArgs{
+		ToAsset:  toAsset,
+		ToUser:   toUser,
+		TokenId:  new(big.Int).SetBytes(reverse(tokenId[:])),
+		TokenUri: string(tokenUri),
+	}, nil
+}
+
+// EncodeNFTArgs serializes the args the way the nft lock proxy does.
+func EncodeNFTArgs(args *NFTArgs) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes(args.ToAsset)
+	sink.WriteVarBytes(args.ToUser)
+	var tokenId common.Uint256
+	copy(tokenId[:], reverse(args.TokenId.Bytes()))
+	sink.WriteHash(tokenId)
+	sink.WriteVarBytes([]byte(args.TokenUri))
+	return sink.Bytes()
+}
+
+// CrossChainArgs fills the columns of the param, ArgsError is set when the args
+// are not the ones of the nft lock proxy.
+func CrossChainArgs(param *mcom.MakeTxParam) models.CrossChainArgs {
+	result := models.CrossChainArgs{
+		ToContract: hex.EncodeToString(param.ToContractAddress),
+		Method:     limit(param.Method, 32),
+	}
+	args, err := DecodeNFTArgs(param.Args)
+	if err != nil {
+		result.ArgsError = limit(err.Error(), 256)
+		return result
+	}
+	result.ArgsAsset = hex.EncodeToString(args.ToAsset)
+	result.ArgsUser = hex.EncodeToString(args.ToUser)
+	result.ArgsTokenId = args.TokenId.String()
+	result.ArgsTokenUri = limit(args.TokenUri, 1024)
+	return result
+}
+
+// limit cuts the value to fit its column.
+func limit(value string, size int) string {
+	if len(value) <= size {
+		return value
+	}
+	return strings.ToValidUTF8(value[:size], "")
+}
+
+func reverse(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, b := range data {
+		result[len(data)-1-i] = b
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package param
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// readHex reads a raw fixture, lock_param.hex is the param of a lock of dog
+// 1287 from ethereum to bsc and poly_request.hex is the request poly keeps for it.
+func readHex(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile("testdata/" + name)
+	assert.NoError(t, err)
+	raw, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	assert.NoError(t, err)
+	return raw
+}
+
+func TestDecodeTxParam(t *testing.T) {
+	raw := readHex(t, "lock_param.hex")
+	txParam, err := DecodeTxParam(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000a1f", hex.EncodeToString(txParam.TxHash))
+	assert.Equal(t, "e5f0d3a9a8e3a7e3c6f2b4d1a0c9e8f7a6b5c4d3", hex.EncodeToString(txParam.FromContractAddress))
+	assert.Equal(t, uint64(79), txParam.ToChainID)
+	assert.Equal(t, "unlock", txParam.Method)
+
+	args, err := DecodeNFTArgs(txParam.Args)
+	assert.NoError(t, err)
+	assert.Equal(t, "4c5f9c1ad1c2d5e6b4a1c0c9b6b1e4b7b7e1e1a2", hex.EncodeToString(args.ToAsset))
+	assert.Equal(t, "5fb03eb21303d39967a1a119b32dd744a0fa8986", hex.EncodeToString(args.ToUser))
+	assert.Equal(t, "1287", args.TokenId.String())
+	assert.Equal(t, "https://nft.poly.network/dog/1287", args.TokenUri)
+	assert.Equal(t, txParam.Args, EncodeNFTArgs(args))
+
+	_, err = DecodeTxParam(raw[:len(raw)-1])
+	assert.Error(t, err)
+	_, err = DecodeTxParam(append(raw, 0))
+	assert.Error(t, err)
+}
+
+func TestDecodeMerkleValue(t *testing.T) {
+	value, err := DecodeMerkleValue(readHex(t, "poly_request.hex"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), value.FromChainID)
+	assert.Equal(t, "59adef4817eaa9a2486114812b7b6b9fe86fb0e51d6619528a4778fb16779d0f", hex.EncodeToString(value.TxHash))
+
+	args := CrossChainArgs(value.MakeTxParam)
+	assert.Equal(t, "6d6e3a7b7d4a2bd0e6c1c8f4e2a6b9d8c7b6a5f4", args.ToContract)
+	assert.Equal(t, "unlock", args.Method)
+	assert.Equal(t, "4c5f9c1ad1c2d5e6b4a1c0c9b6b1e4b7b7e1e1a2", args.ArgsAsset)
+	assert.Equal(t, "5fb03eb21303d39967a1a119b32dd744a0fa8986", args.ArgsUser)
+	assert.Equal(t, "1287", args.ArgsTokenId)
+	assert.Equal(t, "https://nft.poly.network/dog/1287", args.ArgsTokenUri)
+	assert.Empty(t, args.ArgsError)
+
+	// the args of another proxy
+	value.MakeTxParam.Args = []byte{0x01, 0x02}
+	args = CrossChainArgs(value.MakeTxParam)
+	assert.Equal(t, "unlock", args.Method)
+	assert.Empty(t, args.ArgsTokenId)
+	assert.NotEmpty(t, args.ArgsError)
+}
+
+func TestDecodeMerkleProof(t *testing.T) {
+	raw := readHex(t, "poly_request.hex")
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes(raw)
+	// one node of the path, the position and the hash of the sibling
+	sink.WriteByte(0)
+	sink.WriteHash(common.Uint256{1})
+	value, err := DecodeMerkleProof(sink.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, "59adef4817eaa9a2486114812b7b6b9fe86fb0e51d6619528a4778fb16779d0f", hex.EncodeToString(value.TxHash))
+
+	_, err = DecodeMerkleProof(nil)
+	assert.Error(t, err)
+	_, err = DecodeMerkleProof(raw)
+	assert.Error(t, err)
+}
@@ -39,8 +39,10 @@ func LoadEthAccount(storage *leveldb.LevelDBImpl, keystore string, address strin
 }
 
 func repeatDecrypt(storage *leveldb.LevelDBImpl, enc []byte, address string, pwd string) (key *keystore.Key, err error) {
-	if existPwd, err := getEthPwdSession(storage, address); err == nil {
-		return keystore.DecryptKey(enc, existPwd)
+	if existPwd, sessErr := getEthPwdSession(storage, address); sessErr == nil {
+		if key, err = keystore.DecryptKey(enc, existPwd); err == nil {
+			return
+		}
 	}
 
 	if key, err = keystore.DecryptKey(enc, pwd); err == nil {
@@ -72,17 +74,11 @@ func repeatDecrypt(storage *leveldb.LevelDBImpl, enc []byte, address string, pwd
 }
 
 func setEthPwdSession(storage *leveldb.LevelDBImpl, address string, pwd string) error {
-	key := formatEthKey(address)
-	return storage.Set(key, []byte(pwd))
+	return setPwdSession(storage, formatEthKey(address), pwd)
 }
 
 func getEthPwdSession(storage *leveldb.LevelDBImpl, address string) (string, error) {
-	key := formatEthKey(address)
-	bz, err := storage.Get(key)
-	if err != nil {
-		return "", err
-	}
-	return string(bz), nil
+	return getPwdSession(storage, formatEthKey(address))
 }
 
 const ethPersistPrefix = "ethereum:account:"
@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	polysdk "github.com/polynetwork/poly-go-sdk"
+	"github.com/polynetwork/poly-nft-bridge/utils/leveldb"
 )
 
 var (
@@ -23,7 +24,7 @@ func initPolySdk() {
 	})
 }
 
-func LoadPolyAccountList(keystore string, pwd string) ([]*polysdk.Account, error) {
+func LoadPolyAccountList(storage *leveldb.LevelDBImpl, keystore string, pwd string) ([]*polysdk.Account, error) {
 	fs, err := ioutil.ReadDir(keystore)
 	if err != nil {
 		return nil, err
@@ -33,24 +34,24 @@ func LoadPolyAccountList(keystore string, pwd string) ([]*polysdk.Account, error
 	for _, f := range fs {
 		fullPath := path.Join(keystore, f.Name())
 		fmt.Println("full path is ", fullPath)
-		acc, err := LoadPolyAccount(fullPath, pwd)
+		acc, err := LoadPolyAccount(storage, fullPath, pwd)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 		list = append(list, acc)
 	}
 	return list, nil
 }
 
-func LoadPolyAccount(path string, pwd string) (*polysdk.Account, error) {
-	acc, err := getPolyAccountByPassword(path, []byte(pwd))
+func LoadPolyAccount(storage *leveldb.LevelDBImpl, path string, pwd string) (*polysdk.Account, error) {
+	acc, err := getPolyAccountByPassword(storage, path, []byte(pwd))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get poly account, err: %s", err)
 	}
 	return acc, nil
 }
 
-func getPolyAccountByPassword(path string, pwd []byte) (*polysdk.Account, error) {
+func getPolyAccountByPassword(storage *leveldb.LevelDBImpl, path string, pwd []byte) (*polysdk.Account, error) {
 
 	initPolySdk()
 
@@ -58,12 +59,26 @@ func getPolyAccountByPassword(path string, pwd []byte) (*polysdk.Account, error)
 	if err != nil {
 		return nil, fmt.Errorf("open wallet error: %v", err)
 	}
+	data, err := wallet.GetDefaultAccountData()
+	if err != nil {
+		return nil, err
+	}
+	address := data.Address
+
+	if existPwd, err := getPolyPwdSession(storage, address); err == nil {
+		if acc, err := wallet.GetDefaultAccount([]byte(existPwd)); err == nil {
+			return acc, nil
+		}
+	}
 
 	acc, err := wallet.GetDefaultAccount(pwd)
 	if err == nil {
+		_ = setPolyPwdSession(storage, address, string(pwd))
 		return acc, nil
 	}
 
+	fmt.Printf("please input password for poly account %s \r\n", address)
+
 	reader := bufio.NewReader(os.Stdin)
 	for i := 0; i < 10; i++ {
 		curPwd, err := reader.ReadString('\n')
@@ -75,11 +90,26 @@ func getPolyAccountByPassword(path string, pwd []byte) (*polysdk.Account, error)
 		curPwd = strings.Trim(curPwd, "\r")
 		curPwd = strings.Trim(curPwd, "\n")
 		if acc, err := wallet.GetDefaultAccount([]byte(curPwd)); err == nil {
+			_ = setPolyPwdSession(storage, address, curPwd)
 			return acc, nil
 		} else {
 			fmt.Printf("password invalid, err %s, try it again......\r\n", err.Error())
 		}
 	}
 
-	return acc, nil
+	return nil, fmt.Errorf("invalid password for poly account %s", address)
+}
+
+func setPolyPwdSession(storage *leveldb.LevelDBImpl, address string, pwd string) error {
+	return setPwdSession(storage, formatPolyKey(address), pwd)
+}
+
+func getPolyPwdSession(storage *leveldb.LevelDBImpl, address string) (string, error) {
+	return getPwdSession(storage, formatPolyKey(address))
+}
+
+const polyPersistPrefix = "poly:account:"
+
+func formatPolyKey(address string) []byte {
+	return []byte(fmt.Sprintf("%s:%s", polyPersistPrefix, address))
 }
@@ -0,0 +1,186 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/polynetwork/poly-nft-bridge/utils/leveldb"
+)
+
+// DefaultSessionTTL is how long a password is cached after it is typed.
+const DefaultSessionTTL = 30 * time.Minute
+
+const sessionKeyLen = 32
+
+// Keyring keeps the key the password sessions are encrypted with, away from
+// the leveldb they are stored in, copying the leveldb alone gives nothing.
+type Keyring interface {
+	// Key returns the session key, it is created on first use.
+	Key() ([]byte, error)
+	// Reset forgets the key, the sessions encrypted with it can not be read
+	// any more.
+	Reset() error
+}
+
+type memoryKeyring struct {
+	mu  sync.Mutex
+	key []byte
+}
+
+// NewMemoryKeyring keeps the key of this run only, the sessions can not be
+// read by the next one.
+func NewMemoryKeyring() Keyring {
+	return &memoryKeyring{}
+}
+
+func (k *memoryKeyring) Key() ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.key == nil {
+		key := make([]byte, sessionKeyLen)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		k.key = key
+	}
+	return k.key, nil
+}
+
+func (k *memoryKeyring) Reset() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.key = nil
+	return nil
+}
+
+type fileKeyring struct {
+	path string
+}
+
+// NewFileKeyring keeps the key in a file only the user can read, so the
+// sessions last between runs. The file must not be kept with the leveldb.
+func NewFileKeyring(path string) Keyring {
+	return &fileKeyring{path: path}
+}
+
+func (k *fileKeyring) Key() ([]byte, error) {
+	key, err := ioutil.ReadFile(k.path)
+	if err == nil && len(key) == sessionKeyLen {
+		return key, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	key = make([]byte, sessionKeyLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(k.path), 0700); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(k.path, key, 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (k *fileKeyring) Reset() error {
+	if err := os.Remove(k.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+var (
+	sessionKeyring = NewMemoryKeyring()
+	sessionTTL     = DefaultSessionTTL
+)
+
+// SetSession sets the keyring the password sessions are encrypted with and
+// how long they last, a zero ttl caches nothing.
+func SetSession(keyring Keyring, ttl time.Duration) {
+	sessionKeyring = keyring
+	sessionTTL = ttl
+}
+
+// SessionTTL is how long the password sessions last.
+func SessionTTL() time.Duration {
+	return sessionTTL
+}
+
+// Lock removes the password sessions of all accounts and forgets their key.
+func Lock(storage *leveldb.LevelDBImpl) error {
+	for _, prefix := range []string{ethPersistPrefix, polyPersistPrefix} {
+		if err := storage.DeletePrefix([]byte(prefix)); err != nil {
+			return err
+		}
+	}
+	return sessionKeyring.Reset()
+}
+
+func sessionCipher() (cipher.AEAD, error) {
+	key, err := sessionKeyring.Key()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// setPwdSession stores the password sealed with its expiry, the storage key
+// is authenticated as well so a session can not be moved to another account.
+func setPwdSession(storage *leveldb.LevelDBImpl, key []byte, pwd string) error {
+	if sessionTTL <= 0 {
+		return nil
+	}
+	aead, err := sessionCipher()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	plain := make([]byte, 8, 8+len(pwd))
+	binary.BigEndian.PutUint64(plain, uint64(time.Now().Add(sessionTTL).Unix()))
+	plain = append(plain, pwd...)
+	return storage.Set(key, aead.Seal(nonce, nonce, plain, key))
+}
+
+// getPwdSession removes the sessions which expired or can not be opened, such
+// as the ones stored in plaintext before.
+func getPwdSession(storage *leveldb.LevelDBImpl, key []byte) (string, error) {
+	enc, err := storage.Get(key)
+	if err != nil {
+		return "", err
+	}
+	aead, err := sessionCipher()
+	if err != nil {
+		return "", err
+	}
+	if len(enc) < aead.NonceSize() {
+		_ = storage.Delete(key)
+		return "", fmt.Errorf("invalid session")
+	}
+	plain, err := aead.Open(nil, enc[:aead.NonceSize()], enc[aead.NonceSize():], key)
+	if err != nil || len(plain) < 8 {
+		_ = storage.Delete(key)
+		return "", fmt.Errorf("invalid session")
+	}
+	if time.Now().Unix() > int64(binary.BigEndian.Uint64(plain)) {
+		_ = storage.Delete(key)
+		return "", fmt.Errorf("session expired")
+	}
+	return string(plain[8:]), nil
+}
@@ -0,0 +1,91 @@
+package wallet
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly-nft-bridge/utils/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPwdSession(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallet-session")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	storage := leveldb.NewLevelDBInstance(filepath.Join(dir, "leveldb"))
+	keyring := NewFileKeyring(filepath.Join(dir, "keyring"))
+	SetSession(keyring, time.Minute)
+	defer SetSession(NewMemoryKeyring(), DefaultSessionTTL)
+
+	const (
+		account = "0x31c0dd87B33Dcd66f9a255Cf4CF39287F8AE593C"
+		pwd     = "correct horse battery staple"
+	)
+	assert.NoError(t, setEthPwdSession(storage, account, pwd))
+	enc, err := storage.Get(formatEthKey(account))
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(enc, []byte(pwd)))
+	got, err := getEthPwdSession(storage, account)
+	assert.NoError(t, err)
+	assert.Equal(t, pwd, got)
+
+	// a session is bound to its account
+	assert.NoError(t, storage.Set(formatPolyKey(account), enc))
+	_, err = getPolyPwdSession(storage, account)
+	assert.Error(t, err)
+
+	// the sessions written in plaintext before are dropped
+	assert.NoError(t, storage.Set(formatPolyKey(account), []byte(pwd)))
+	_, err = getPolyPwdSession(storage, account)
+	assert.Error(t, err)
+	_, err = storage.Get(formatPolyKey(account))
+	assert.Error(t, err)
+
+	// the next run reads the session with the same keyring file
+	SetSession(NewFileKeyring(filepath.Join(dir, "keyring")), time.Minute)
+	got, err = getEthPwdSession(storage, account)
+	assert.NoError(t, err)
+	assert.Equal(t, pwd, got)
+	SetSession(NewMemoryKeyring(), time.Minute)
+	_, err = getEthPwdSession(storage, account)
+	assert.Error(t, err)
+
+	SetSession(keyring, -time.Second)
+	assert.NoError(t, setPolyPwdSession(storage, account, pwd))
+	_, err = storage.Get(formatPolyKey(account))
+	assert.Error(t, err)
+
+	SetSession(keyring, time.Minute)
+	assert.NoError(t, setEthPwdSession(storage, account, pwd))
+	assert.NoError(t, setPolyPwdSession(storage, account, pwd))
+	assert.NoError(t, Lock(storage))
+	_, err = storage.Get(formatEthKey(account))
+	assert.Error(t, err)
+	_, err = storage.Get(formatPolyKey(account))
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(dir, "keyring"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestPwdSessionExpired(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wallet-session")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	storage := leveldb.NewLevelDBInstance(dir)
+	SetSession(NewMemoryKeyring(), time.Second)
+	defer SetSession(NewMemoryKeyring(), DefaultSessionTTL)
+
+	const account = "0x31c0dd87B33Dcd66f9a255Cf4CF39287F8AE593C"
+	assert.NoError(t, setEthPwdSession(storage, account, "111111"))
+	_, err = getEthPwdSession(storage, account)
+	assert.NoError(t, err)
+	time.Sleep(2100 * time.Millisecond)
+	_, err = getEthPwdSession(storage, account)
+	assert.Error(t, err)
+	_, err = storage.Get(formatEthKey(account))
+	assert.Error(t, err)
+}
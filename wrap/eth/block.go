@@ -0,0 +1,363 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eth
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/go_abi/eccm_abi"
+	"github.com/polynetwork/poly-nft-bridge/go_abi/eccmp_abi"
+	nftlp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_lock_proxy_abi"
+	nftwp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_wrap_abi"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+)
+
+// contractLogs decodes the logs of one of the bridge contracts.
+type contractLogs struct {
+	abi      abi.ABI
+	contract *bind.BoundContract
+	events   []string // events the listener reads
+}
+
+func newContractLogs(raw string, events ...string) *contractLogs {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(err)
+	}
+	return &contractLogs{
+		abi:      parsed,
+		contract: bind.NewBoundContract(common.Address{}, parsed, nil, nil, nil),
+		events:   events,
+	}
+}
+
+var (
+	eccmLogs    = newContractLogs(eccm_abi.EthCrossChainManagerABI, "CrossChainEvent", "VerifyHeaderAndExecuteTxEvent", "OwnershipTransferred")
+	ccmpLogs    = newContractLogs(eccmp_abi.EthCrossChainManagerProxyABI, "OwnershipTransferred")
+	proxyLogs   = newContractLogs(nftlp.PolyNFTLockProxyABI, "LockEvent", "UnlockEvent", "BindAssetEvent", "BindProxyEvent", "OwnershipTransferred", "SetManagerProxyEvent")
+	wrapperLogs = newContractLogs(nftwp.PolyNFTWrapperABI, "PolyWrapperLock", "PolyWrapperSpeedUp", "OwnershipTransferred", "Paused", "Unpaused")
+)
+
+func (c *contractLogs) topics() []common.Hash {
+	topics := make([]common.Hash, 0, len(c.events))
+	for _, name := range c.events {
+		topics = append(topics, c.abi.Events[name].ID)
+	}
+	return topics
+}
+
+// name returns the event of the log, empty when the contract has no such event.
+func (c *contractLogs) name(log types.Log) string {
+	if len(log.Topics) == 0 {
+		return ""
+	}
+	event, err := c.abi.EventByID(log.Topics[0])
+	if err != nil {
+		return ""
+	}
+	return event.Name
+}
+
+func (c *contractLogs) unpack(out interface{}, name string, log types.Log) error {
+	if err := c.contract.UnpackLog(out, name, log); err != nil {
+		return fmt.Errorf("unpack %s of tx %s: %v", name, log.TxHash.String(), err)
+	}
+	return nil
+}
+
+// bridgeEvents are the events of the bridge contracts in a range of blocks,
+// in the order of the logs.
+type bridgeEvents struct {
+	wrapperTransactions []*models.WrapperTransaction
+	speedUps            []*models.WrapperSpeedUp
+	crossChainEvents    []*eccm_abi.EthCrossChainManagerCrossChainEvent
+	executeTxEvents     []*eccm_abi.EthCrossChainManagerVerifyHeaderAndExecuteTxEvent
+	proxyLockEvents     []*models.ProxyLockEvent
+	proxyUnlockEvents   []*models.ProxyUnlockEvent
+	assetBinds          []*models.AssetBindEvent
+	proxyBinds          []*models.ProxyBindEvent
+	governance          []*models.GovernanceEvent
+}
+
+// getBridgeEvents reads the logs of the wrapper, eccm, ccmp and lock proxy by
+// one FilterLogs and dispatches them by contract and topic. The ccmp is
+// skipped when it is not configured.
+func (e *EthereumChainListen) getBridgeEvents(startHeight, endHeight uint64) (*bridgeEvents, error) {
+	contracts := map[common.Address]*contractLogs{
+		e.WrapperAddress(): wrapperLogs,
+		e.ECCMAddress():    eccmLogs,
+		e.ProxyAddress():   proxyLogs,
+	}
+	if e.ethCfg.CCMPContract != "" {
+		contracts[e.CCMPAddress()] = ccmpLogs
+	}
+	addresses := make([]common.Address, 0, len(contracts))
+	topics := make([]common.Hash, 0)
+	seen := make(map[common.Hash]bool)
+	for address, c := range contracts {
+		addresses = append(addresses, address)
+		for _, topic := range c.topics() {
+			if !seen[topic] {
+				seen[topic] = true
+				topics = append(topics, topic)
+			}
+		}
+	}
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(startHeight),
+		ToBlock:   new(big.Int).SetUint64(endHeight),
+		Addresses: addresses,
+		Topics:    [][]common.Hash{topics},
+	}
+	raws, err := e.ethSdk.FilterLogs(query)
+	if err != nil {
+		return nil, fmt.Errorf("GetSmartContractEventByBlock, filter logs: %s", err.Error())
+	}
+
+	events := &bridgeEvents{
+		wrapperTransactions: make([]*models.WrapperTransaction, 0),
+		speedUps:            make([]*models.WrapperSpeedUp, 0),
+		crossChainEvents:    make([]*eccm_abi.EthCrossChainManagerCrossChainEvent, 0),
+		executeTxEvents:     make([]*eccm_abi.EthCrossChainManagerVerifyHeaderAndExecuteTxEvent, 0),
+		proxyLockEvents:     make([]*models.ProxyLockEvent, 0),
+		proxyUnlockEvents:   make([]*models.ProxyUnlockEvent, 0),
+		assetBinds:          make([]*models.AssetBindEvent, 0),
+		proxyBinds:          make([]*models.ProxyBindEvent, 0),
+		governance:          make([]*models.GovernanceEvent, 0),
+	}
+	for _, raw := range raws {
+		if raw.Removed {
+			continue
+		}
+		var err error
+		switch contracts[raw.Address] {
+		case wrapperLogs:
+			err = e.dispatchWrapperLog(events, raw)
+		case eccmLogs:
+			err = e.dispatchECCMLog(events, raw)
+		case ccmpLogs:
+			err = e.dispatchCCMPLog(events, raw)
+		case proxyLogs:
+			err = e.dispatchProxyLog(events, raw)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("GetSmartContractEventByBlock, %s", err.Error())
+		}
+	}
+	return events, nil
+}
+
+func (e *EthereumChainListen) dispatchWrapperLog(events *bridgeEvents, raw types.Log) error {
+	chainID := e.GetChainId()
+	switch name := wrapperLogs.name(raw); name {
+	case "PolyWrapperLock":
+		evt := new(nftwp.PolyNFTWrapperPolyWrapperLock)
+		if err := wrapperLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		evt.Raw = raw
+		events.wrapperTransactions = append(events.wrapperTransactions, wrapLockEvent2WrapTx(evt))
+	case "PolyWrapperSpeedUp":
+		evt := new(nftwp.PolyNFTWrapperPolyWrapperSpeedUp)
+		if err := wrapperLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		evt.Raw = raw
+		events.speedUps = append(events.speedUps, wrapSpeedUpEvent2SpeedUp(evt, chainID))
+	case "OwnershipTransferred":
+		evt := new(nftwp.PolyNFTWrapperOwnershipTransferred)
+		if err := wrapperLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		events.governance = append(events.governance,
+			convertOwnershipTransferred(raw, evt.PreviousOwner, evt.NewOwner, chainID, basedef.GOVERNANCE_WRAPPER))
+	case "Paused":
+		evt := new(nftwp.PolyNFTWrapperPaused)
+		if err := wrapperLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		events.governance = append(events.governance, convertPauseEvent(raw, evt.Account, chainID, basedef.GOVERNANCE_PAUSED))
+	case "Unpaused":
+		evt := new(nftwp.PolyNFTWrapperUnpaused)
+		if err := wrapperLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		events.governance = append(events.governance, convertPauseEvent(raw, evt.Account, chainID, basedef.GOVERNANCE_UNPAUSED))
+	}
+	return nil
+}
+
+func (e *EthereumChainListen) dispatchECCMLog(events *bridgeEvents, raw types.Log) error {
+	switch name := eccmLogs.name(raw); name {
+	case "CrossChainEvent":
+		evt := new(eccm_abi.EthCrossChainManagerCrossChainEvent)
+		if err := eccmLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		evt.Raw = raw
+		events.crossChainEvents = append(events.crossChainEvents, evt)
+	case "VerifyHeaderAndExecuteTxEvent":
+		evt := new(eccm_abi.EthCrossChainManagerVerifyHeaderAndExecuteTxEvent)
+		if err := eccmLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		evt.Raw = raw
+		events.executeTxEvents = append(events.executeTxEvents, evt)
+	case "OwnershipTransferred":
+		evt := new(eccm_abi.EthCrossChainManagerOwnershipTransferred)
+		if err := eccmLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		events.governance = append(events.governance,
+			convertOwnershipTransferred(raw, evt.PreviousOwner, evt.NewOwner, e.GetChainId(), basedef.GOVERNANCE_ECCM))
+	}
+	return nil
+}
+
+func (e *EthereumChainListen) dispatchCCMPLog(events *bridgeEvents, raw types.Log) error {
+	switch name := ccmpLogs.name(raw); name {
+	case "OwnershipTransferred":
+		evt := new(eccmp_abi.EthCrossChainManagerProxyOwnershipTransferred)
+		if err := ccmpLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		events.governance = append(events.governance,
+			convertOwnershipTransferred(raw, evt.PreviousOwner, evt.NewOwner, e.GetChainId(), basedef.GOVERNANCE_CCMP))
+	}
+	return nil
+}
+
+func (e *EthereumChainListen) dispatchProxyLog(events *bridgeEvents, raw types.Log) error {
+	chainID := e.GetChainId()
+	switch name := proxyLogs.name(raw); name {
+	case "LockEvent":
+		evt := new(nftlp.PolyNFTLockProxyLockEvent)
+		if err := proxyLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		evt.Raw = raw
+		events.proxyLockEvents = append(events.proxyLockEvents, convertLockProxyEvent(evt))
+	case "UnlockEvent":
+		evt := new(nftlp.PolyNFTLockProxyUnlockEvent)
+		if err := proxyLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		evt.Raw = raw
+		events.proxyUnlockEvents = append(events.proxyUnlockEvents, convertUnlockProxyEvent(evt))
+	case "BindAssetEvent":
+		evt := new(nftlp.PolyNFTLockProxyBindAssetEvent)
+		if err := proxyLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		evt.Raw = raw
+		events.assetBinds = append(events.assetBinds, convertBindAssetEvent(evt, chainID))
+	case "BindProxyEvent":
+		evt := new(nftlp.PolyNFTLockProxyBindProxyEvent)
+		if err := proxyLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		evt.Raw = raw
+		events.proxyBinds = append(events.proxyBinds, convertBindProxyEvent(evt, chainID))
+		events.governance = append(events.governance, convertGovernanceBindProxyEvent(evt, chainID))
+	case "OwnershipTransferred":
+		evt := new(nftlp.PolyNFTLockProxyOwnershipTransferred)
+		if err := proxyLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		events.governance = append(events.governance,
+			convertOwnershipTransferred(raw, evt.PreviousOwner, evt.NewOwner, chainID, basedef.GOVERNANCE_PROXY))
+	case "SetManagerProxyEvent":
+		evt := new(nftlp.PolyNFTLockProxySetManagerProxyEvent)
+		if err := proxyLogs.unpack(evt, name, raw); err != nil {
+			return err
+		}
+		evt.Raw = raw
+		events.governance = append(events.governance, convertSetManagerProxyEvent(evt, chainID))
+	}
+	return nil
+}
+
+// blockData is what the handlers read of one block: the time of its header
+// and the bridge events. HandleNewBlock reads it and the handlers called after
+// it for the same height use it again.
+type blockData struct {
+	height uint64
+	time   uint64
+	events *bridgeEvents
+}
+
+func (e *EthereumChainListen) readBlock(height uint64) (*blockData, error) {
+	header, err := e.ethSdk.GetHeaderByNumber(height)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("there is no ethereum block!")
+	}
+	events, err := e.getBridgeEvents(height, height)
+	if err != nil {
+		return nil, err
+	}
+	data := &blockData{height: height, time: header.Time, events: events}
+	e.last = data
+	return data, nil
+}
+
+// getBlock returns the block read by the last HandleNewBlock when it is of the
+// height, or reads it.
+func (e *EthereumChainListen) getBlock(height uint64) (*blockData, error) {
+	if e.last != nil && e.last.height == height {
+		return e.last, nil
+	}
+	return e.readBlock(height)
+}
+
+// eccmTransactions are the transactions of the block sent to the eccm with
+// their receipts. A transaction whose receipt can not be read is left out.
+func (e *EthereumChainListen) eccmTransactions(height uint64) ([]*eth_sdk.BlockTransaction, map[common.Hash]*types.Receipt, error) {
+	txs, err := e.ethSdk.GetBlockTransactions(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	eccmAddr := e.ECCMAddress()
+	eccmTxs := make([]*eth_sdk.BlockTransaction, 0)
+	receipts := make(map[common.Hash]*types.Receipt)
+	for _, tx := range txs {
+		if tx.To == nil || *tx.To != eccmAddr {
+			continue
+		}
+		receipt, err := e.ethSdk.GetTransactionReceiptOnce(tx.Hash)
+		if err != nil {
+			logs.Warn("(relay failure) chain: %s, txhash: %s, get receipt err: %v", e.GetChainName(), tx.Hash.String()[2:], err)
+			continue
+		}
+		eccmTxs = append(eccmTxs, tx)
+		receipts[tx.Hash] = receipt
+	}
+	return eccmTxs, receipts, nil
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package eth
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/polynetwork/poly-nft-bridge/conf"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/go_abi/eccm_abi"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	pcom "github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	typedRelayHash  = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	legacyRelayHash = "0x2222222222222222222222222222222222222222222222222222222222222222"
+	testECCM        = "0x00000000000000000000000000000000000000ec"
+	testRelayer     = "0x00000000000000000000000000000000000000aa"
+)
+
+// fakeNode answers the calls of the listener as a node of a london chain
+// does, the relay in its block is a reverted dynamic fee transaction.
+type fakeNode struct {
+	input string
+}
+
+func (n *fakeNode) BlockNumber() hexutil.Uint64 {
+	return 0x10
+}
+
+func (n *fakeNode) GetBlockByNumber(number string, full bool) (map[string]interface{}, error) {
+	block := map[string]interface{}{
+		"parentHash":       common.Hash{}.Hex(),
+		"sha3Uncles":       common.Hash{}.Hex(),
+		"miner":            common.Address{}.Hex(),
+		"stateRoot":        common.Hash{}.Hex(),
+		"transactionsRoot": common.Hash{}.Hex(),
+		"receiptsRoot":     common.Hash{}.Hex(),
+		"logsBloom":        hexutil.Encode(make([]byte, 256)),
+		"difficulty":       "0x1",
+		"number":           number,
+		"gasLimit":         "0x1c9c380",
+		"gasUsed":          "0x0",
+		"timestamp":        "0x64",
+		"extraData":        "0x",
+		"mixHash":          common.Hash{}.Hex(),
+		"nonce":            "0x0000000000000000",
+		"baseFeePerGas":    "0x7",
+		"hash":             common.Hash{1}.Hex(),
+		"uncles":           []string{},
+	}
+	if !full {
+		block["transactions"] = []string{typedRelayHash, legacyRelayHash}
+		return block, nil
+	}
+	block["transactions"] = []map[string]interface{}{
+		{
+			"type":                 "0x2",
+			"chainId":              "0x61",
+			"hash":                 typedRelayHash,
+			"from":                 testRelayer,
+			"to":                   testECCM,
+			"nonce":                "0x3",
+			"gas":                  "0x493e0",
+			"gasPrice":             "0xa",
+			"maxFeePerGas":         "0x14",
+			"maxPriorityFeePerGas": "0x3",
+			"value":                "0x0",
+			"input":                n.input,
+			"accessList":           []interface{}{},
+			"v":                    "0x1",
+			"r":                    "0x1",
+			"s":                    "0x1",
+		},
+		{
+			"type":     "0x0",
+			"hash":     legacyRelayHash,
+			"from":     testRelayer,
+			"to":       testECCM,
+			"nonce":    "0x4",
+			"gas":      "0x493e0",
+			"gasPrice": "0xa",
+			"value":    "0x0",
+			"input":    n.input,
+			"v":        "0xe5",
+			"r":        "0x1",
+			"s":        "0x1",
+		},
+	}
+	return block, nil
+}
+
+func (n *fakeNode) GetLogs(query map[string]interface{}) ([]interface{}, error) {
+	return []interface{}{}, nil
+}
+
+func (n *fakeNode) GetTransactionReceipt(hash common.Hash) (map[string]interface{}, error) {
+	if hash.Hex() != typedRelayHash {
+		return nil, fmt.Errorf("receipt of %s is not available", hash.Hex())
+	}
+	return map[string]interface{}{
+		"type":              "0x2",
+		"transactionHash":   typedRelayHash,
+		"blockNumber":       "0x10",
+		"status":            "0x0",
+		"cumulativeGasUsed": "0xc350",
+		"gasUsed":           "0xc350",
+		"effectiveGasPrice": "0xa",
+		"logsBloom":         hexutil.Encode(make([]byte, 256)),
+		"logs":              []interface{}{},
+	}, nil
+}
+
+func (n *fakeNode) Call(msg map[string]interface{}, number string) (hexutil.Bytes, error) {
+	return nil, fmt.Errorf("execution reverted: boom")
+}
+
+func TestTypedTransactionBlock(t *testing.T) {
+	raw, err := ioutil.ReadFile("../../utils/param/testdata/poly_request.hex")
+	assert.NoError(t, err)
+	value, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	assert.NoError(t, err)
+	sink := pcom.NewZeroCopySink(nil)
+	sink.WriteVarBytes(value)
+	sink.WriteByte(0)
+	sink.WriteHash(pcom.Uint256{1})
+	eccmAbi, err := abi.JSON(strings.NewReader(eccm_abi.EthCrossChainManagerABI))
+	assert.NoError(t, err)
+	data, err := eccmAbi.Pack("verifyHeaderAndExecuteTx", sink.Bytes(), []byte{1}, []byte{}, []byte{}, []byte{})
+	assert.NoError(t, err)
+
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", &fakeNode{input: hexutil.Encode(data)}))
+	node := httptest.NewServer(server)
+	defer node.Close()
+	defer server.Stop()
+
+	sdk, err := eth_sdk.NewEthereumSdk(node.URL)
+	assert.NoError(t, err)
+	// the block can not be decoded as types.Block
+	_, err = sdk.GetBlockByNumber(0x10)
+	assert.Error(t, err)
+
+	cfg := &conf.ChainListenConfig{
+		ChainName:       "bsc",
+		ChainId:         basedef.BSC_CROSSCHAIN_ID,
+		ListenSlot:      1,
+		ECCMContract:    testECCM,
+		WrapperContract: "0x00000000000000000000000000000000000000e1",
+		ProxyContract:   "0x00000000000000000000000000000000000000e2",
+	}
+	listener := NewEthereumChainListenWithSdk(cfg, eth_sdk.NewEthereumSdkPro([]string{node.URL}, 1, cfg.ChainId))
+	wrappers, srcs, _, dsts, err := listener.HandleNewBlock(0x10)
+	assert.NoError(t, err)
+	assert.Empty(t, wrappers)
+	assert.Empty(t, srcs)
+	assert.Empty(t, dsts)
+
+	// the receipt of the legacy relay is missing, it is skipped
+	failures, err := listener.HandleRelayFailures(0x10)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(failures)) {
+		assert.Equal(t, typedRelayHash[2:], failures[0].TxHash)
+		assert.Equal(t, testRelayer[2:], failures[0].Relayer)
+		assert.Equal(t, "500000", failures[0].Fee.String())
+		assert.Equal(t, uint64(0x64), failures[0].Time)
+		assert.Equal(t, uint64(0x10), failures[0].Height)
+		assert.Equal(t, "execution reverted: boom", failures[0].Reason)
+	}
+}
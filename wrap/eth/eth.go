@@ -22,18 +22,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/astaxie/beego/logs"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/polynetwork/poly-nft-bridge/conf"
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
-	"github.com/polynetwork/poly-nft-bridge/go_abi/eccm_abi"
-	nftlp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_lock_proxy_abi"
-	nftwp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_wrap_abi"
 	"github.com/polynetwork/poly-nft-bridge/models"
 	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
 )
@@ -48,6 +48,7 @@ const (
 type EthereumChainListen struct {
 	ethCfg *conf.ChainListenConfig
 	ethSdk *eth_sdk.EthereumSdkPro
+	last   *blockData // the block read by the last HandleNewBlock
 }
 
 func NewEthereumChainListen(cfg *conf.ChainListenConfig) *EthereumChainListen {
@@ -60,6 +61,12 @@ func NewEthereumChainListen(cfg *conf.ChainListenConfig) *EthereumChainListen {
 	return ethListen
 }
 
+// NewEthereumChainListenWithSdk listens the chain through the sdk, the nodes
+// of the config are not used.
+func NewEthereumChainListenWithSdk(cfg *conf.ChainListenConfig, sdk *eth_sdk.EthereumSdkPro) *EthereumChainListen {
+	return &EthereumChainListen{ethCfg: cfg, ethSdk: sdk}
+}
+
 func (e *EthereumChainListen) WrapperAddress() common.Address {
 	return common.HexToAddress(e.ethCfg.WrapperContract)
 }
@@ -72,6 +79,10 @@ func (e *EthereumChainListen) ProxyAddress() common.Address {
 	return common.HexToAddress(e.ethCfg.ProxyContract)
 }
 
+func (e *EthereumChainListen) CCMPAddress() common.Address {
+	return common.HexToAddress(e.ethCfg.CCMPContract)
+}
+
 func (e *EthereumChainListen) GetLatestHeight() (uint64, error) {
 	return e.ethSdk.GetLatestHeight()
 }
@@ -92,6 +103,8 @@ func (e *EthereumChainListen) GetDefer() uint64 {
 	return e.ethCfg.Defer
 }
 
+// HandleNewBlock reads the header and the logs of the bridge contracts of the
+// block, the other handlers of the same height use them again.
 func (e *EthereumChainListen) HandleNewBlock(height uint64) (
 	[]*models.WrapperTransaction,
 	[]*models.SrcTransaction,
@@ -100,57 +113,40 @@ func (e *EthereumChainListen) HandleNewBlock(height uint64) (
 	error,
 ) {
 
-	wrapAddr := e.WrapperAddress()
-	eccmAddr := e.ECCMAddress()
-	proxyAddr := e.ProxyAddress()
 	chainName := e.GetChainName()
 	chainID := e.GetChainId()
 
-	blockHeader, err := e.ethSdk.GetHeaderByNumber(height)
+	data, err := e.readBlock(height)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
-	if blockHeader == nil {
-		return nil, nil, nil, nil, fmt.Errorf("there is no ethereum block!")
-	}
-	tt := blockHeader.Time
+	tt := data.time
 
-	wrapperTransactions, err := e.getWrapperEventByBlockNumber(wrapAddr, height, height)
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
+	wrapperTransactions := data.events.wrapperTransactions
 	for _, wtx := range wrapperTransactions {
 		logs.Info("(wrapper) from chain: %s, txhash: %s", chainName, wtx.Hash)
 		wtx.Time = tt
 		wtx.SrcChainId = e.GetChainId()
 		wtx.Status = basedef.STATE_SOURCE_DONE
 	}
-	eccmLockEvents, eccmUnLockEvents, err := e.getECCMEventByBlockNumber(eccmAddr, height, height)
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
-	proxyLockEvents, proxyUnlockEvents, err := e.getProxyEventByBlockNumber(proxyAddr, height, height)
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
 
 	srcTransactions := make([]*models.SrcTransaction, 0)
 	dstTransactions := make([]*models.DstTransaction, 0)
-	for _, lockEvent := range eccmLockEvents {
-		if lockEvent.Method == _eth_crosschainlock {
-			logs.Info("(lock) from chain: %s, txhash: %s, txid: %s",
-				chainName, lockEvent.TxHash, lockEvent.Txid)
-			srcTransaction := assembleSrcTransaction(lockEvent, proxyLockEvents, chainID, tt)
-			srcTransactions = append(srcTransactions, srcTransaction)
-		}
+	for _, evt := range data.events.crossChainEvents {
+		lockEvent := crossChainEvent2ProxyLockEvent(evt, e.GetConsumeGas(evt.Raw.TxHash))
+		logs.Info("(lock) from chain: %s, txhash: %s, txid: %s",
+			chainName, lockEvent.TxHash, lockEvent.Txid)
+		srcTransaction := assembleSrcTransaction(lockEvent, data.events.proxyLockEvents, chainID, tt)
+		srcTransactions = append(srcTransactions, srcTransaction)
 	}
 	// save unLockEvent to db
-	for _, unLockEvent := range eccmUnLockEvents {
-		if unLockEvent.Method == _eth_crosschainunlock {
-			logs.Info("(unlock) to chain: %s, txhash: %s", chainName, unLockEvent.TxHash)
-			dstTransaction := assembleDstTransaction(unLockEvent, proxyUnlockEvents, chainID, tt)
-			dstTransactions = append(dstTransactions, dstTransaction)
-		}
+	for _, evt := range data.events.executeTxEvents {
+		fee, sender := e.txFee(evt.Raw.TxHash)
+		unLockEvent := verifyAndExecuteEvent2ProxyUnlockEvent(evt, fee)
+		logs.Info("(unlock) to chain: %s, txhash: %s", chainName, unLockEvent.TxHash)
+		dstTransaction := assembleDstTransaction(unLockEvent, data.events.proxyUnlockEvents, chainID, tt)
+		dstTransaction.Sender = sender
+		dstTransactions = append(dstTransactions, dstTransaction)
 	}
 	return wrapperTransactions, srcTransactions, nil, dstTransactions, nil
 }
@@ -163,188 +159,170 @@ func (e *EthereumChainListen) HandleNewBlockBatch(startHeight, endHeight uint64)
 	error,
 ) {
 
-	wrapAddr := e.WrapperAddress()
-	eccmAddr := e.ECCMAddress()
-	proxyAddr := e.ProxyAddress()
 	chainName := e.GetChainName()
 	chainID := e.GetChainId()
 
-	wrapperTransactions, err := e.getWrapperEventByBlockNumber(wrapAddr, startHeight, endHeight)
+	events, err := e.getBridgeEvents(startHeight, endHeight)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
+	wrapperTransactions := events.wrapperTransactions
 	for _, wtx := range wrapperTransactions {
 		logs.Info("(wrapper) from chain: %s, txhash: %s", chainName, wtx.Hash)
 		wtx.SrcChainId = e.GetChainId()
 		wtx.Status = basedef.STATE_SOURCE_DONE
 	}
-	eccmLockEvents, eccmUnLockEvents, err := e.getECCMEventByBlockNumber(eccmAddr, startHeight, endHeight)
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
-	proxyLockEvents, proxyUnlockEvents, err := e.getProxyEventByBlockNumber(proxyAddr, startHeight, endHeight)
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
-	//
 
 	srcTransactions := make([]*models.SrcTransaction, 0)
 	dstTransactions := make([]*models.DstTransaction, 0)
-	for _, lockEvent := range eccmLockEvents {
-		if lockEvent.Method == _eth_crosschainlock {
-			logs.Info("(lock) from chain: %s, txhash: %s, txid: %s", chainName, lockEvent.TxHash, lockEvent.Txid)
-			srcTransaction := assembleSrcTransaction(lockEvent, proxyLockEvents, chainID, 0)
-			srcTransactions = append(srcTransactions, srcTransaction)
-		}
+	for _, evt := range events.crossChainEvents {
+		lockEvent := crossChainEvent2ProxyLockEvent(evt, e.GetConsumeGas(evt.Raw.TxHash))
+		logs.Info("(lock) from chain: %s, txhash: %s, txid: %s", chainName, lockEvent.TxHash, lockEvent.Txid)
+		srcTransaction := assembleSrcTransaction(lockEvent, events.proxyLockEvents, chainID, 0)
+		srcTransactions = append(srcTransactions, srcTransaction)
 	}
 	// save unLockEvent to db
-	for _, unLockEvent := range eccmUnLockEvents {
-		if unLockEvent.Method == _eth_crosschainunlock {
-			logs.Info("(unlock) to chain: %s, txhash: %s", chainName, unLockEvent.TxHash)
-			dstTransaction := assembleDstTransaction(unLockEvent, proxyUnlockEvents, chainID, 0)
-			dstTransactions = append(dstTransactions, dstTransaction)
-		}
+	for _, evt := range events.executeTxEvents {
+		fee, sender := e.txFee(evt.Raw.TxHash)
+		unLockEvent := verifyAndExecuteEvent2ProxyUnlockEvent(evt, fee)
+		logs.Info("(unlock) to chain: %s, txhash: %s", chainName, unLockEvent.TxHash)
+		dstTransaction := assembleDstTransaction(unLockEvent, events.proxyUnlockEvents, chainID, 0)
+		dstTransaction.Sender = sender
+		dstTransactions = append(dstTransactions, dstTransaction)
 	}
 	return wrapperTransactions, srcTransactions, nil, dstTransactions, nil
 }
 
-func (e *EthereumChainListen) getWrapperEventByBlockNumber(
-	wrapAddr common.Address,
-	startHeight, endHeight uint64) (
-	[]*models.WrapperTransaction,
-	error,
-) {
-
-	// todo: newPolyWrapper change to IPolyNFTWrapper
-	wrapperContract, err := nftwp.NewPolyNFTWrapper(wrapAddr, e.ethSdk.GetClient())
+// HandleSpeedUps fetches the speed ups of the wrapper transactions in the block,
+// they are kept apart from the wrapper transactions they add the fee to.
+func (e *EthereumChainListen) HandleSpeedUps(height uint64) ([]*models.WrapperSpeedUp, error) {
+	data, err := e.getBlock(height)
 	if err != nil {
-		return nil, fmt.Errorf("GetSmartContractEventByBlock, error: %s", err.Error())
+		return nil, err
 	}
-	opt := &bind.FilterOpts{
-		Start:   startHeight,
-		End:     &endHeight,
-		Context: context.Background(),
+	for _, speedUp := range data.events.speedUps {
+		speedUp.Time = data.time
+		logs.Info("(speedup) from chain: %s, txhash: %s, src hash: %s", e.GetChainName(), speedUp.TxHash, speedUp.SrcHash)
 	}
+	return data.events.speedUps, nil
+}
 
-	// get ethereum lock events from given block
-	wrapperTransactions := make([]*models.WrapperTransaction, 0)
-	lockEvents, err := wrapperContract.FilterPolyWrapperLock(opt, nil, nil)
+// HandleAssetBinds fetches the bindings of the lock proxy, the name and symbol
+// of the bound assets are read from the contracts.
+func (e *EthereumChainListen) HandleAssetBinds(height uint64) ([]*models.AssetBindEvent, []*models.ProxyBindEvent, error) {
+	data, err := e.getBlock(height)
 	if err != nil {
-		return nil, fmt.Errorf("GetSmartContractEventByBlock, filter lock events :%s", err.Error())
-	}
-	for lockEvents.Next() {
-		evt := lockEvents.Event
-		wtx := wrapLockEvent2WrapTx(evt)
-		wrapperTransactions = append(wrapperTransactions, wtx)
-	}
-	speedupEvents, err := wrapperContract.FilterPolyWrapperSpeedUp(opt, nil, nil, nil)
-	if err != nil {
-		return nil, fmt.Errorf("GetSmartContractEventByBlock, filter lock events :%s", err.Error())
+		return nil, nil, err
+	}
+	assetBinds, proxyBinds := data.events.assetBinds, data.events.proxyBinds
+	for _, bind := range assetBinds {
+		logs.Info("(bind asset) chain: %s, asset: %s, to chain: %d, to asset: %s",
+			e.GetChainName(), bind.AssetHash, bind.ToChainId, bind.ToAssetHash)
+		name, symbol, baseUri, err := e.ethSdk.GetNFTInfo(common.HexToAddress(bind.AssetHash))
+		if err != nil {
+			logs.Warn("HandleAssetBinds - read asset %s info, err: %v", bind.AssetHash, err)
+			continue
+		}
+		bind.Name, bind.Symbol, bind.BaseUri = name, symbol, baseUri
 	}
-	for speedupEvents.Next() {
-		evt := speedupEvents.Event
-		wtx := wrapSpeedUpEvent2WrapTx(evt)
-		wrapperTransactions = append(wrapperTransactions, wtx)
+	for _, bind := range proxyBinds {
+		logs.Info("(bind proxy) chain: %s, to chain: %d, to proxy: %s", e.GetChainName(), bind.ToChainId, bind.ProxyHash)
 	}
-	return wrapperTransactions, nil
+	return assetBinds, proxyBinds, nil
 }
 
-func (e *EthereumChainListen) getECCMEventByBlockNumber(
-	eccmAddr common.Address,
-	startHeight, endHeight uint64) (
-	[]*models.ECCMLockEvent,
-	[]*models.ECCMUnlockEvent,
-	error,
-) {
-
-	eccmContract, err := eccm_abi.NewEthCrossChainManager(eccmAddr, e.ethSdk.GetClient())
+// HandleGovernanceEvents fetches the ownership and manager changes of the
+// eccm, ccmp, lock proxy and wrapper, the proxy bindings and the wrapper
+// pauses. The ccmp is skipped when it is not configured.
+func (e *EthereumChainListen) HandleGovernanceEvents(height uint64) ([]*models.GovernanceEvent, error) {
+	data, err := e.getBlock(height)
 	if err != nil {
-		return nil, nil, fmt.Errorf("GetSmartContractEventByBlock, error: %s", err.Error())
+		return nil, err
 	}
-	opt := &bind.FilterOpts{
-		Start:   startHeight,
-		End:     &endHeight,
-		Context: context.Background(),
+	for _, event := range data.events.governance {
+		event.Time = data.time
+		logs.Warn("(governance) chain: %s, contract: %s, event: %s, %s, txhash: %s",
+			e.GetChainName(), event.Contract, event.Event, event.Detail, event.TxHash)
 	}
-	// get ethereum lock events from given block
-	eccmLockEvents := make([]*models.ECCMLockEvent, 0)
-	crossChainEvents, err := eccmContract.FilterCrossChainEvent(opt, nil)
+	return data.events.governance, nil
+}
+
+// HandleRelayFailures finds the relays to the cross chain manager which are
+// reverted, they leave no event so the receipts of the transactions sent to
+// the cross chain manager are checked. It is only a diagnosis, a block or a
+// receipt which can not be read is logged and skipped.
+func (e *EthereumChainListen) HandleRelayFailures(height uint64) ([]*models.DstRelayFailure, error) {
+	failures := make([]*models.DstRelayFailure, 0)
+	data, err := e.getBlock(height)
 	if err != nil {
-		return nil, nil, fmt.Errorf("GetSmartContractEventByBlock, filter lock events :%s", err.Error())
+		return nil, err
 	}
-	for crossChainEvents.Next() {
-		evt := crossChainEvents.Event
-		Fee := e.GetConsumeGas(evt.Raw.TxHash)
-		eccmLockEvent := crossChainEvent2ProxyLockEvent(evt, Fee)
-		eccmLockEvents = append(eccmLockEvents, eccmLockEvent)
-	}
-	// ethereum unlock events from given block
-	eccmUnlockEvents := make([]*models.ECCMUnlockEvent, 0)
-	executeTxEvent, err := eccmContract.FilterVerifyHeaderAndExecuteTxEvent(opt)
+	txs, receipts, err := e.eccmTransactions(height)
 	if err != nil {
-		return nil, nil, fmt.Errorf("GetSmartContractEventByBlock, filter unlock events :%s", err.Error())
+		logs.Warn("(relay failure) chain: %s, height: %d, read block err: %v", e.GetChainName(), height, err)
+		return failures, nil
 	}
-
-	for executeTxEvent.Next() {
-		evt := executeTxEvent.Event
-		Fee := e.GetConsumeGas(evt.Raw.TxHash)
-		eccmUnlockEvent := verifyAndExecuteEvent2ProxyUnlockEvent(evt, Fee)
-		eccmUnlockEvents = append(eccmUnlockEvents, eccmUnlockEvent)
+	for _, tx := range txs {
+		receipt := receipts[tx.Hash]
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			continue
+		}
+		failure, err := relayFailure(tx, receipt, e.GetChainId())
+		if err != nil {
+			logs.Warn("(relay failure) chain: %s, txhash: %s, err: %v", e.GetChainName(), tx.Hash.String()[2:], err)
+			continue
+		}
+		if failure == nil {
+			continue
+		}
+		failure.Height = height
+		failure.Time = data.time
+		failure.Reason = e.revertReason(tx, receipt, height)
+		logs.Info("(relay failure) chain: %s, txhash: %s, poly hash: %s, reason: %s", e.GetChainName(), failure.TxHash, failure.PolyHash, failure.Reason)
+		failures = append(failures, failure)
 	}
-	return eccmLockEvents, eccmUnlockEvents, nil
+	return failures, nil
 }
 
-func (e *EthereumChainListen) getProxyEventByBlockNumber(
-	proxyAddr common.Address,
-	startHeight, endHeight uint64) (
-	[]*models.ProxyLockEvent,
-	[]*models.ProxyUnlockEvent,
-	error,
-) {
-
-	proxyContract, err := nftlp.NewPolyNFTLockProxy(proxyAddr, e.ethSdk.GetClient())
-	if err != nil {
-		return nil, nil, fmt.Errorf("GetSmartContractEventByBlock, error: %s", err.Error())
+// revertReason replays the relay on the state before its block, the node
+// returns the reason it reverted with. It is empty when the replay passes.
+func (e *EthereumChainListen) revertReason(tx *eth_sdk.BlockTransaction, receipt *types.Receipt, height uint64) string {
+	if receipt.GasUsed == tx.Gas {
+		return "out of gas"
 	}
-	opt := &bind.FilterOpts{
-		Start:   startHeight,
-		End:     &endHeight,
-		Context: context.Background(),
+	client := e.ethSdk.GetClient()
+	if client == nil || height == 0 {
+		return ""
 	}
-	// get ethereum lock events from given block
-	proxyLockEvents := make([]*models.ProxyLockEvent, 0)
-	lockEvents, err := proxyContract.FilterLockEvent(opt)
-	if err != nil {
-		return nil, nil, fmt.Errorf("GetSmartContractEventByBlock, filter lock events :%s", err.Error())
+	msg := ethereum.CallMsg{From: tx.From, To: tx.To, Gas: tx.Gas, GasPrice: tx.GasPrice, Value: tx.Value, Data: tx.Data}
+	_, err := client.CallContract(context.Background(), msg, new(big.Int).SetUint64(height-1))
+	if err == nil {
+		return ""
 	}
-	for lockEvents.Next() {
-		proxyLockEvent := convertLockProxyEvent(lockEvents.Event)
-		proxyLockEvents = append(proxyLockEvents, proxyLockEvent)
+	reason := err.Error()
+	if len(reason) > 256 {
+		reason = reason[:256]
 	}
-
-	// ethereum unlock events from given block
-	proxyUnlockEvents := make([]*models.ProxyUnlockEvent, 0)
-	unlockEvents, err := proxyContract.FilterUnlockEvent(opt)
-	if err != nil {
-		return nil, nil, fmt.Errorf("GetSmartContractEventByBlock, filter unlock events :%s", err.Error())
-	}
-	for unlockEvents.Next() {
-		proxyUnlockEvent := convertUnlockProxyEvent(unlockEvents.Event)
-		proxyUnlockEvents = append(proxyUnlockEvents, proxyUnlockEvent)
-	}
-	return proxyLockEvents, proxyUnlockEvents, nil
+	return reason
 }
 
 func (e *EthereumChainListen) GetConsumeGas(hash common.Hash) uint64 {
-	tx, err := e.ethSdk.GetTransactionByHash(hash)
+	fee, _ := e.txFee(hash)
+	return fee
+}
+
+// txFee is the fee paid by the transaction and its sender, zero and empty when
+// they can not be read.
+func (e *EthereumChainListen) txFee(hash common.Hash) (uint64, string) {
+	tx, err := e.ethSdk.GetBlockTransaction(hash)
 	if err != nil {
-		return 0
+		return 0, ""
 	}
 	receipt, err := e.ethSdk.GetTransactionReceipt(hash)
 	if err != nil {
-		return 0
+		return 0, ""
 	}
-	return tx.GasPrice().Uint64() * receipt.GasUsed
+	return tx.GasPrice.Uint64() * receipt.GasUsed, strings.ToLower(tx.From.String()[2:])
 }
 
 type ExtendHeightRsp struct {
@@ -2,13 +2,21 @@ package eth
 
 import (
 	"encoding/hex"
+	"fmt"
+	"math/big"
 	"strings"
 
+	"github.com/astaxie/beego/logs"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
 	"github.com/polynetwork/poly-nft-bridge/go_abi/eccm_abi"
 	nftlp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_lock_proxy_abi"
 	nftwp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_wrap_abi"
 	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	"github.com/polynetwork/poly-nft-bridge/utils/param"
 )
 
 func assembleSrcTransaction(
@@ -31,8 +39,10 @@ func assembleSrcTransaction(
 	srcTransaction.Contract = eccmLockEvent.Contract
 	srcTransaction.Key = eccmLockEvent.Txid
 	srcTransaction.Param = hex.EncodeToString(eccmLockEvent.Value)
+	var lockEvent *models.ProxyLockEvent
 	for _, v := range proxyLockEvents {
 		if v.TxHash == eccmLockEvent.TxHash {
+			lockEvent = v
 			toAssetHash := v.ToAssetHash
 			srcTransfer := &models.SrcTransfer{}
 			if tt > 0 {
@@ -51,9 +61,86 @@ func assembleSrcTransaction(
 			break
 		}
 	}
+	srcTransaction.CrossChainArgs = decodeSrcArgs(eccmLockEvent.Value, lockEvent)
+	if srcTransaction.ArgsError != "" {
+		logs.Warn("(lock) txhash: %s, param: %s", eccmLockEvent.TxHash, srcTransaction.ArgsError)
+	}
 	return srcTransaction
 }
 
+// decodeSrcArgs decodes the param committed to poly and checks it against the
+// lock event of the proxy, if there is one.
+func decodeSrcArgs(raw []byte, lockEvent *models.ProxyLockEvent) models.CrossChainArgs {
+	txParam, err := param.DecodeTxParam(raw)
+	if err != nil {
+		return models.CrossChainArgs{ArgsError: err.Error()}
+	}
+	args := param.CrossChainArgs(txParam)
+	if args.ArgsError != "" || lockEvent == nil {
+		return args
+	}
+	diffs := make([]string, 0)
+	if txParam.ToChainID != uint64(lockEvent.ToChainId) {
+		diffs = append(diffs, fmt.Sprintf("to chain %d", txParam.ToChainID))
+	}
+	if args.ArgsAsset != lockEvent.ToAssetHash {
+		diffs = append(diffs, fmt.Sprintf("asset %s", args.ArgsAsset))
+	}
+	if args.ArgsUser != lockEvent.ToAddress {
+		diffs = append(diffs, fmt.Sprintf("user %s", args.ArgsUser))
+	}
+	if lockEvent.TokenId == nil || args.ArgsTokenId != lockEvent.TokenId.String() {
+		diffs = append(diffs, fmt.Sprintf("token %s", args.ArgsTokenId))
+	}
+	if len(diffs) > 0 {
+		args.ArgsError = fmt.Sprintf("differs from lock event: %s", strings.Join(diffs, ", "))
+		if len(args.ArgsError) > 256 {
+			args.ArgsError = args.ArgsError[:256]
+		}
+	}
+	return args
+}
+
+// relayFailure links a reverted transaction to the poly transaction it relays,
+// the proof passed to verifyHeaderAndExecuteTx starts with the poly request.
+// Transactions which call another method of the cross chain manager are nil.
+func relayFailure(tx *eth_sdk.BlockTransaction, receipt *types.Receipt, chainId uint64) (*models.DstRelayFailure, error) {
+	eccmAbi, err := abi.JSON(strings.NewReader(eccm_abi.EthCrossChainManagerABI))
+	if err != nil {
+		return nil, err
+	}
+	data := tx.Data
+	if len(data) < 4 {
+		return nil, nil
+	}
+	method, err := eccmAbi.MethodById(data[:4])
+	if err != nil || method.Name != "verifyHeaderAndExecuteTx" {
+		return nil, nil
+	}
+	args, err := method.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	proof, ok := args[0].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("proof is %T", args[0])
+	}
+	value, err := param.DecodeMerkleProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	fee := new(big.Int).Mul(tx.GasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+	return &models.DstRelayFailure{
+		TxHash:     tx.Hash.String()[2:],
+		ChainId:    chainId,
+		PolyHash:   basedef.HexStringReverse(hex.EncodeToString(value.TxHash)),
+		SrcChainId: value.FromChainID,
+		SrcKey:     hex.EncodeToString(value.MakeTxParam.TxHash),
+		Relayer:    strings.ToLower(tx.From.String()[2:]),
+		Fee:        models.NewBigInt(fee),
+	}, nil
+}
+
 func assembleDstTransaction(
 	eccmUnlockEvent *models.ECCMUnlockEvent,
 	proxyUnlockEvents []*models.ProxyUnlockEvent,
@@ -101,17 +188,22 @@ func wrapLockEvent2WrapTx(evt *nftwp.PolyNFTWrapperPolyWrapperLock) *models.Wrap
 		DstUser:      strings.ToLower(evt.ToAddress.String()[2:]),
 		FeeTokenHash: strings.ToLower(evt.FeeToken.String()[2:]),
 		FeeAmount:    models.NewBigInt(evt.Fee),
+		TotalFee:     models.NewBigInt(evt.Fee),
 		ServerId:     evt.Id.Uint64(),
 		BlockHeight:  evt.Raw.BlockNumber,
 	}
 }
 
-func wrapSpeedUpEvent2WrapTx(evt *nftwp.PolyNFTWrapperPolyWrapperSpeedUp) *models.WrapperTransaction {
-	return &models.WrapperTransaction{
-		Hash:         evt.TxHash.String(),
-		User:         evt.Sender.String(),
-		FeeTokenHash: evt.FeeToken.String(),
+func wrapSpeedUpEvent2SpeedUp(evt *nftwp.PolyNFTWrapperPolyWrapperSpeedUp, chainId uint64) *models.WrapperSpeedUp {
+	return &models.WrapperSpeedUp{
+		TxHash:       evt.Raw.TxHash.String()[2:],
+		LogIndex:     uint64(evt.Raw.Index),
+		SrcHash:      evt.TxHash.String()[2:],
+		ChainId:      chainId,
+		User:         strings.ToLower(evt.Sender.String()[2:]),
+		FeeTokenHash: strings.ToLower(evt.FeeToken.String()[2:]),
 		FeeAmount:    models.NewBigInt(evt.Efee),
+		Height:       evt.Raw.BlockNumber,
 	}
 }
 
@@ -171,3 +263,58 @@ func convertUnlockProxyEvent(evt *nftlp.PolyNFTLockProxyUnlockEvent) *models.Pro
 		TokenId:     evt.TokenId,
 	}
 }
+
+// the lock proxy names the bound asset of the target chain targetProxyHash
+func convertBindAssetEvent(evt *nftlp.PolyNFTLockProxyBindAssetEvent, chainID uint64) *models.AssetBindEvent {
+	return &models.AssetBindEvent{
+		TxHash:      evt.Raw.TxHash.String()[2:],
+		ChainId:     chainID,
+		AssetHash:   strings.ToLower(evt.FromAssetHash.String()[2:]),
+		ToChainId:   evt.ToChainId,
+		ToAssetHash: hex.EncodeToString(evt.TargetProxyHash),
+		Height:      evt.Raw.BlockNumber,
+	}
+}
+
+func convertBindProxyEvent(evt *nftlp.PolyNFTLockProxyBindProxyEvent, chainID uint64) *models.ProxyBindEvent {
+	return &models.ProxyBindEvent{
+		TxHash:    evt.Raw.TxHash.String()[2:],
+		ChainId:   chainID,
+		ToChainId: evt.ToChainId,
+		ProxyHash: hex.EncodeToString(evt.TargetProxyHash),
+		Height:    evt.Raw.BlockNumber,
+	}
+}
+
+func newGovernanceEvent(raw types.Log, chainID uint64, contract, event, detail string) *models.GovernanceEvent {
+	return &models.GovernanceEvent{
+		TxHash:   raw.TxHash.String()[2:],
+		LogIndex: uint64(raw.Index),
+		ChainId:  chainID,
+		Contract: contract,
+		Address:  strings.ToLower(raw.Address.String()[2:]),
+		Event:    event,
+		Detail:   detail,
+		Height:   raw.BlockNumber,
+	}
+}
+
+func convertOwnershipTransferred(raw types.Log, previousOwner, newOwner common.Address, chainID uint64, contract string) *models.GovernanceEvent {
+	detail := fmt.Sprintf("owner %s -> %s", strings.ToLower(previousOwner.String()[2:]), strings.ToLower(newOwner.String()[2:]))
+	return newGovernanceEvent(raw, chainID, contract, basedef.GOVERNANCE_OWNERSHIP_TRANSFERRED, detail)
+}
+
+func convertSetManagerProxyEvent(evt *nftlp.PolyNFTLockProxySetManagerProxyEvent, chainID uint64) *models.GovernanceEvent {
+	detail := fmt.Sprintf("manager %s", strings.ToLower(evt.Manager.String()[2:]))
+	return newGovernanceEvent(evt.Raw, chainID, basedef.GOVERNANCE_PROXY, basedef.GOVERNANCE_SET_MANAGER_PROXY, detail)
+}
+
+func convertGovernanceBindProxyEvent(evt *nftlp.PolyNFTLockProxyBindProxyEvent, chainID uint64) *models.GovernanceEvent {
+	detail := fmt.Sprintf("to chain %d proxy %s", evt.ToChainId, hex.EncodeToString(evt.TargetProxyHash))
+	return newGovernanceEvent(evt.Raw, chainID, basedef.GOVERNANCE_PROXY, basedef.GOVERNANCE_BIND_PROXY, detail)
+}
+
+func convertPauseEvent(raw types.Log, account common.Address, chainID uint64, event string) *models.GovernanceEvent {
+	detail := fmt.Sprintf("by %s", strings.ToLower(account.String()[2:]))
+	return newGovernanceEvent(raw, chainID, basedef.GOVERNANCE_WRAPPER, event, detail)
+}
@@ -1,6 +1,25 @@
 package eth
 
-import "testing"
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	basedef "github.com/polynetwork/poly-nft-bridge/const"
+	"github.com/polynetwork/poly-nft-bridge/go_abi/eccm_abi"
+	nftlp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_lock_proxy_abi"
+	nftwp "github.com/polynetwork/poly-nft-bridge/go_abi/nft_wrap_abi"
+	"github.com/polynetwork/poly-nft-bridge/models"
+	"github.com/polynetwork/poly-nft-bridge/sdk/eth_sdk"
+	pcom "github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
 
 func TestSimple(t *testing.T) {
 	for i := 0; i < 3; i++ {
@@ -12,3 +31,122 @@ func TestSimple(t *testing.T) {
 		}
 	}
 }
+
+func TestConvertGovernanceEvents(t *testing.T) {
+	raw := types.Log{
+		Address:     common.HexToAddress("0xA1B2"),
+		TxHash:      common.HexToHash("0x01"),
+		Index:       3,
+		BlockNumber: 100,
+	}
+
+	event := convertOwnershipTransferred(raw, common.HexToAddress("0x01"), common.HexToAddress("0x02"), 2, basedef.GOVERNANCE_ECCM)
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001", event.TxHash)
+	assert.Equal(t, uint64(3), event.LogIndex)
+	assert.Equal(t, "000000000000000000000000000000000000a1b2", event.Address)
+	assert.Equal(t, basedef.GOVERNANCE_OWNERSHIP_TRANSFERRED, event.Event)
+	assert.Equal(t, "owner 0000000000000000000000000000000000000001 -> 0000000000000000000000000000000000000002", event.Detail)
+	assert.Equal(t, uint64(100), event.Height)
+
+	event = convertGovernanceBindProxyEvent(&nftlp.PolyNFTLockProxyBindProxyEvent{ToChainId: 79, TargetProxyHash: []byte{0xab}, Raw: raw}, 2)
+	assert.Equal(t, basedef.GOVERNANCE_PROXY, event.Contract)
+	assert.Equal(t, "to chain 79 proxy ab", event.Detail)
+
+	event = convertPauseEvent(raw, common.HexToAddress("0x03"), 2, basedef.GOVERNANCE_PAUSED)
+	assert.Equal(t, basedef.GOVERNANCE_WRAPPER, event.Contract)
+	assert.Equal(t, basedef.GOVERNANCE_PAUSED, event.Event)
+}
+
+func TestConvertSpeedUpEvent(t *testing.T) {
+	raw := types.Log{TxHash: common.HexToHash("0x02"), Index: 4, BlockNumber: 101}
+	speedUp := wrapSpeedUpEvent2SpeedUp(&nftwp.PolyNFTWrapperPolyWrapperSpeedUp{
+		FeeToken: common.HexToAddress("0xA1B2"),
+		TxHash:   common.HexToHash("0x01"),
+		Sender:   common.HexToAddress("0xC3D4"),
+		Efee:     big.NewInt(5),
+		Raw:      raw,
+	}, 2)
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000002", speedUp.TxHash)
+	assert.Equal(t, uint64(4), speedUp.LogIndex)
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001", speedUp.SrcHash)
+	assert.Equal(t, "000000000000000000000000000000000000a1b2", speedUp.FeeTokenHash)
+	assert.Equal(t, "000000000000000000000000000000000000c3d4", speedUp.User)
+	assert.Equal(t, "5", speedUp.FeeAmount.String())
+	assert.Equal(t, uint64(2), speedUp.ChainId)
+	assert.Equal(t, uint64(101), speedUp.Height)
+}
+
+func TestDecodeSrcArgs(t *testing.T) {
+	raw, err := ioutil.ReadFile("../../utils/param/testdata/lock_param.hex")
+	assert.NoError(t, err)
+	value, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	assert.NoError(t, err)
+
+	lock := &models.ProxyLockEvent{ToChainId: 79, ToAssetHash: "4c5f9c1ad1c2d5e6b4a1c0c9b6b1e4b7b7e1e1a2",
+		ToAddress: "5fb03eb21303d39967a1a119b32dd744a0fa8986", TokenId: big.NewInt(1287)}
+	args := decodeSrcArgs(value, lock)
+	assert.Empty(t, args.ArgsError)
+	assert.Equal(t, "1287", args.ArgsTokenId)
+	assert.Equal(t, "https://nft.poly.network/dog/1287", args.ArgsTokenUri)
+
+	lock.TokenId = big.NewInt(1288)
+	lock.ToChainId = 6
+	args = decodeSrcArgs(value, lock)
+	assert.Equal(t, "differs from lock event: to chain 79, token 1287", args.ArgsError)
+
+	args = decodeSrcArgs(value[:10], lock)
+	assert.NotEmpty(t, args.ArgsError)
+	assert.Empty(t, args.ArgsTokenId)
+}
+
+func TestRelayFailure(t *testing.T) {
+	raw, err := ioutil.ReadFile("../../utils/param/testdata/poly_request.hex")
+	assert.NoError(t, err)
+	value, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	assert.NoError(t, err)
+	sink := pcom.NewZeroCopySink(nil)
+	sink.WriteVarBytes(value)
+	sink.WriteByte(0)
+	sink.WriteHash(pcom.Uint256{1})
+
+	eccmAbi, err := abi.JSON(strings.NewReader(eccm_abi.EthCrossChainManagerABI))
+	assert.NoError(t, err)
+	data, err := eccmAbi.Pack("verifyHeaderAndExecuteTx", sink.Bytes(), []byte{1}, []byte{}, []byte{}, []byte{})
+	assert.NoError(t, err)
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	signer := types.NewEIP155Signer(big.NewInt(97))
+	sign := func(data []byte) *eth_sdk.BlockTransaction {
+		tx := types.NewTransaction(3, common.HexToAddress("0x01"), big.NewInt(0), 300000, big.NewInt(10), data)
+		tx, err := types.SignTx(tx, signer, key)
+		assert.NoError(t, err)
+		blockTx, err := eth_sdk.NewBlockTransaction(tx)
+		assert.NoError(t, err)
+		return blockTx
+	}
+	receipt := &types.Receipt{Status: types.ReceiptStatusFailed, GasUsed: 50000}
+
+	tx := sign(data)
+	failure, err := relayFailure(tx, receipt, basedef.BSC_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Hash.String()[2:], failure.TxHash)
+	assert.Equal(t, basedef.HexStringReverse("59adef4817eaa9a2486114812b7b6b9fe86fb0e51d6619528a4778fb16779d0f"), failure.PolyHash)
+	assert.Equal(t, uint64(2), failure.SrcChainId)
+	assert.Equal(t, "0000000000000000000000000000000000000000000000000000000000000a1f", failure.SrcKey)
+	assert.Equal(t, strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).String()[2:]), failure.Relayer)
+	assert.Equal(t, "500000", failure.Fee.String())
+	assert.Equal(t, basedef.BSC_CROSSCHAIN_ID, failure.ChainId)
+
+	// another method of the cross chain manager
+	pause, err := eccmAbi.Pack("pause")
+	assert.NoError(t, err)
+	failure, err = relayFailure(sign(pause), receipt, basedef.BSC_CROSSCHAIN_ID)
+	assert.NoError(t, err)
+	assert.Nil(t, failure)
+
+	// a proof which is not a poly request
+	data, err = eccmAbi.Pack("verifyHeaderAndExecuteTx", []byte{1, 2}, []byte{1}, []byte{}, []byte{}, []byte{})
+	assert.NoError(t, err)
+	_, err = relayFailure(sign(data), receipt, basedef.BSC_CROSSCHAIN_ID)
+	assert.Error(t, err)
+}
@@ -44,3 +44,27 @@ type ChainHandle interface {
 	// `defer` is the diff result of normal chain node height and extend chain node height
 	GetDefer() uint64
 }
+
+// AssetBindHandle is implemented by the chains whose nft lock proxy emits the
+// asset and proxy bindings, so new collections are found without a config.
+type AssetBindHandle interface {
+	HandleAssetBinds(height uint64) ([]*models.AssetBindEvent, []*models.ProxyBindEvent, error)
+}
+
+// GovernanceHandle is implemented by the chains which report ownership,
+// manager and pause changes of the bridge contracts.
+type GovernanceHandle interface {
+	HandleGovernanceEvents(height uint64) ([]*models.GovernanceEvent, error)
+}
+
+// SpeedUpHandle is implemented by the chains whose wrapper lets the user add
+// fee to a wrapper transaction which is not relayed yet.
+type SpeedUpHandle interface {
+	HandleSpeedUps(height uint64) ([]*models.WrapperSpeedUp, error)
+}
+
+// RelayFailureHandle is implemented by the chains which find the relays of
+// poly transactions reverted by the cross chain manager.
+type RelayFailureHandle interface {
+	HandleRelayFailures(height uint64) ([]*models.DstRelayFailure, error)
+}
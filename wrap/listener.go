@@ -26,9 +26,11 @@ import (
 	"github.com/polynetwork/poly-nft-bridge/wrap/poly"
 
 	"github.com/astaxie/beego/logs"
+	"github.com/polynetwork/poly-nft-bridge/alert"
 	"github.com/polynetwork/poly-nft-bridge/conf"
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
 	"github.com/polynetwork/poly-nft-bridge/dao/crosschaindao"
+	"github.com/polynetwork/poly-nft-bridge/models"
 	"github.com/polynetwork/poly-nft-bridge/wrap/eth"
 )
 
@@ -77,20 +79,27 @@ func NewChainHandle(c *conf.ChainListenConfig) (handler ChainHandle) {
 }
 
 type CrossChainListen struct {
-	handle ChainHandle
-	db     crosschaindao.CrossChainDao
-	exit   chan bool
+	handle   ChainHandle
+	db       crosschaindao.CrossChainDao
+	notifier *alert.Notifier
+	exit     chan bool
 }
 
 func NewCrossChainListen(handle ChainHandle, db crosschaindao.CrossChainDao) *CrossChainListen {
 	crossChainListen := &CrossChainListen{
-		handle: handle,
-		db:     db,
-		exit:   make(chan bool, 0),
+		handle:   handle,
+		db:       db,
+		notifier: alert.NewNotifier(alert.NewLogSink()),
+		exit:     make(chan bool, 0),
 	}
 	return crossChainListen
 }
 
+// SetNotifier replaces the log only notifier used for the governance alerts.
+func (ccl *CrossChainListen) SetNotifier(notifier *alert.Notifier) {
+	ccl.notifier = notifier
+}
+
 func (ccl *CrossChainListen) Start() {
 	logs.Info("start cross chain listen: %s", ccl.handle.GetChainName())
 	go ccl.ListenChain()
@@ -157,6 +166,23 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 					logs.Error("HandleNewBlock err: %v", err)
 					break
 				}
+				if err := ccl.updateAssetBinds(chain.Height + 1); err != nil {
+					logs.Error("updateAssetBinds err: %v", err)
+					break
+				}
+				governanceEvents, err := ccl.updateGovernance(chain.Height + 1)
+				if err != nil {
+					logs.Error("updateGovernance err: %v", err)
+					break
+				}
+				if err := ccl.updateSpeedUps(chain.Height + 1); err != nil {
+					logs.Error("updateSpeedUps err: %v", err)
+					break
+				}
+				if err := ccl.updateRelayFailures(chain.Height + 1); err != nil {
+					logs.Error("updateRelayFailures err: %v", err)
+					break
+				}
 				chain.Height += 1
 				err = ccl.db.UpdateEvents(chain, wrapperTransactions, srcTransactions, polyTransactions, dstTransactions)
 				if err != nil {
@@ -164,6 +190,7 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 					chain.Height -= 1
 					break
 				}
+				ccl.notifyGovernance(governanceEvents)
 			}
 		case <-ccl.exit:
 			logs.Info("cross chain listen exit, chain: %s, dao: %s......", ccl.handle.GetChainName(), ccl.db.Name())
@@ -171,3 +198,78 @@ func (ccl *CrossChainListen) listenChain() (exit bool) {
 		}
 	}
 }
+
+// updateAssetBinds saves the bindings of the block before the chain height
+// moves on, saving them again is harmless.
+func (ccl *CrossChainListen) updateAssetBinds(height uint64) error {
+	handle, ok := ccl.handle.(AssetBindHandle)
+	if !ok {
+		return nil
+	}
+	assetBinds, proxyBinds, err := handle.HandleAssetBinds(height)
+	if err != nil {
+		return err
+	}
+	return ccl.db.UpdateAssetBinds(assetBinds, proxyBinds)
+}
+
+// updateSpeedUps saves the speed ups of the block, the total fee of the wrapper
+// transactions which are saved already is counted again.
+func (ccl *CrossChainListen) updateSpeedUps(height uint64) error {
+	handle, ok := ccl.handle.(SpeedUpHandle)
+	if !ok {
+		return nil
+	}
+	speedUps, err := handle.HandleSpeedUps(height)
+	if err != nil {
+		return err
+	}
+	return ccl.db.UpdateSpeedUps(speedUps)
+}
+
+// updateRelayFailures saves the reverted relays of the block, they explain the
+// transfers which stay confirmed by poly.
+func (ccl *CrossChainListen) updateRelayFailures(height uint64) error {
+	handle, ok := ccl.handle.(RelayFailureHandle)
+	if !ok {
+		return nil
+	}
+	failures, err := handle.HandleRelayFailures(height)
+	if err != nil {
+		return err
+	}
+	return ccl.db.UpdateRelayFailures(failures)
+}
+
+// updateGovernance saves the governance changes of the block, saving them again
+// is harmless. They are alerted by notifyGovernance once the height is saved,
+// so a block handled again after a failure does not alert twice.
+func (ccl *CrossChainListen) updateGovernance(height uint64) ([]*models.GovernanceEvent, error) {
+	handle, ok := ccl.handle.(GovernanceHandle)
+	if !ok {
+		return nil, nil
+	}
+	events, err := handle.HandleGovernanceEvents(height)
+	if err != nil {
+		return nil, err
+	}
+	if err := ccl.db.UpdateGovernanceEvents(events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// notifyGovernance raises a critical alert for each governance change, none of
+// them is expected in normal running.
+func (ccl *CrossChainListen) notifyGovernance(events []*models.GovernanceEvent) {
+	for _, event := range events {
+		ccl.notifier.Notify(&alert.Alert{
+			Level:  alert.LevelCritical,
+			Source: ccl.handle.GetChainName(),
+			Title:  fmt.Sprintf("%s %s", event.Contract, event.Event),
+			Content: fmt.Sprintf("chain: %d, contract: %s, height: %d, tx: %s, %s",
+				event.ChainId, event.Address, event.Height, event.TxHash, event.Detail),
+			Time: int64(event.Time),
+		})
+	}
+}
@@ -18,6 +18,7 @@
 package poly
 
 import (
+	"encoding/hex"
 	"fmt"
 	"math/big"
 
@@ -27,6 +28,8 @@ import (
 	basedef "github.com/polynetwork/poly-nft-bridge/const"
 	"github.com/polynetwork/poly-nft-bridge/models"
 	"github.com/polynetwork/poly-nft-bridge/sdk/poly_sdk"
+	"github.com/polynetwork/poly-nft-bridge/utils/param"
+	"github.com/polynetwork/poly/common"
 )
 
 const (
@@ -107,6 +110,9 @@ func (p *PolyChainListen) HandleNewBlock(height uint64) (
 				}
 
 				tx := assemblePolyTransaction(event, states, chainID, height, tt)
+				if err := p.fillCrossChainArgs(tx); err != nil {
+					return nil, nil, nil, nil, err
+				}
 				polyTransactions = append(polyTransactions, tx)
 			}
 		}
@@ -114,6 +120,34 @@ func (p *PolyChainListen) HandleNewBlock(height uint64) (
 	return nil, nil, polyTransactions, nil, nil
 }
 
+// fillCrossChainArgs reads the request which poly keeps under the key of the
+// makeProof, and decodes the param committed by the source chain.
+func (p *PolyChainListen) fillCrossChainArgs(tx *models.PolyTransaction) error {
+	key, err := hex.DecodeString(tx.Key)
+	if err != nil || len(key) <= common.ADDR_LEN {
+		tx.ArgsError = fmt.Sprintf("invalid key %s", tx.Key)
+		return nil
+	}
+	// the key starts with the address of the contract
+	raw, err := p.polySdk.GetStorage(p.polyCfg.ECCMContract, key[common.ADDR_LEN:])
+	if err != nil {
+		return err
+	}
+	tx.CrossChainArgs = decodePolyArgs(raw)
+	if tx.ArgsError != "" {
+		logs.Warn("(poly) txhash: %s, param: %s", tx.Hash, tx.ArgsError)
+	}
+	return nil
+}
+
+func decodePolyArgs(raw []byte) models.CrossChainArgs {
+	value, err := param.DecodeMerkleValue(raw)
+	if err != nil {
+		return models.CrossChainArgs{ArgsError: err.Error()}
+	}
+	return param.CrossChainArgs(value.MakeTxParam)
+}
+
 func (p *PolyChainListen) GetExtendLatestHeight() (uint64, error) {
 	if len(p.polyCfg.ExtendNodes) == 0 {
 		return p.GetLatestHeight()
@@ -145,5 +179,8 @@ func assemblePolyTransaction(
 	} else {
 		mctx.SrcHash = basedef.HexStringReverse(states[3].(string))
 	}
+	if len(states) > 5 {
+		mctx.Key, _ = states[5].(string)
+	}
 	return mctx
 }